@@ -6,11 +6,9 @@ import (
 	"fmt"
 	"go/scanner"
 	"io"
-	"io/ioutil"
 	"net"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"runtime"
 	"sort"
 	"strconv"
@@ -21,7 +19,6 @@ import (
 
 	"github.com/logrusorgru/aurora/v3"
 	"github.com/rs/zerolog/log"
-	"golang.org/x/mod/modfile"
 
 	"encr.dev/cli/daemon/export"
 	"encr.dev/cli/daemon/internal/manifest"
@@ -402,23 +399,12 @@ func (s *Server) OnStderr(r *run.Run, line []byte) {
 
 // parseApp parses the app.
 func (s *Server) parseApp(appRoot, workingDir string, parseTests bool) (*parser.Result, error) {
-	modPath := filepath.Join(appRoot, "go.mod")
-	modData, err := ioutil.ReadFile(modPath)
-	if err != nil {
-		return nil, err
-	}
-	mod, err := modfile.Parse(modPath, modData, nil)
-	if err != nil {
-		return nil, err
-	}
-
 	vcsRevision := vcs.GetRevision(appRoot)
 
 	cfg := &parser.Config{
 		AppRoot:                  appRoot,
 		AppRevision:              vcsRevision.Revision,
 		AppHasUncommittedChanges: vcsRevision.Uncommitted,
-		ModulePath:               mod.Module.Mod.Path,
 		WorkingDir:               workingDir,
 		ParseTests:               parseTests,
 	}
@@ -15,7 +15,6 @@ import (
 	"net/http"
 	"os"
 	"os/exec"
-	"path/filepath"
 	"sort"
 	"strconv"
 	"sync/atomic"
@@ -24,7 +23,6 @@ import (
 	"github.com/rs/xid"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
-	"golang.org/x/mod/modfile"
 
 	"github.com/hashicorp/yamux"
 
@@ -157,23 +155,12 @@ func (r *Run) Done() <-chan struct{} {
 // Reload rebuilds the app and, if successful,
 // starts a new proc and switches over.
 func (r *Run) Reload() (*Proc, error) {
-	modPath := filepath.Join(r.Root, "go.mod")
-	modData, err := ioutil.ReadFile(modPath)
-	if err != nil {
-		return nil, err
-	}
-	mod, err := modfile.Parse(modPath, modData, nil)
-	if err != nil {
-		return nil, err
-	}
-
 	vcsRevision := vcs.GetRevision(r.Root)
 
 	cfg := &parser.Config{
 		AppRoot:                  r.Root,
 		AppRevision:              vcsRevision.Revision,
 		AppHasUncommittedChanges: vcsRevision.Uncommitted,
-		ModulePath:               mod.Module.Mod.Path,
 		WorkingDir:               r.params.WorkingDir,
 		ParseTests:               false,
 	}
@@ -282,7 +282,7 @@ func (b *Builder) buildRPC(svc *est.Service, rpc *est.RPC) *Statement {
 		} else {
 			g.Qual("encore.dev/runtime", "FinishRequest").Call(Nil(), Nil())
 			g.Id("w").Dot("Header").Call().Dot("Set").Call(Lit("Content-Type"), Lit("application/json"))
-			g.Id("w").Dot("WriteHeader").Call(Lit(200))
+			g.Id("w").Dot("WriteHeader").Call(Lit(rpc.SuccessStatus))
 		}
 	})
 }
@@ -346,7 +346,7 @@ func (b *Builder) encodeResponse(g *Group, rpc *est.RPC) {
 		)
 	}
 	g.Id("w").Dot("Header").Call().Dot("Set").Call(Lit("Content-Type"), Lit("application/json"))
-	g.Id("w").Dot("WriteHeader").Call(Lit(200))
+	g.Id("w").Dot("WriteHeader").Call(Lit(rpc.SuccessStatus))
 	g.Id("w").Dot("Write").Call(Id("respData"))
 }
 
@@ -45,6 +45,14 @@ func (b *builder) rewritePkg(pkg *est.Package, targetDir string) error {
 				// do nothing
 				return true
 
+			case est.PubSubNode:
+				// do nothing
+				return true
+
+			case est.CustomResourceNode:
+				// do nothing
+				return true
+
 			case est.RPCRefNode:
 				rpc := rewrite.RPC
 				wrapperName := "__encore_" + rpc.Svc.Name + "_" + rpc.Name
@@ -0,0 +1,125 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strings"
+
+	"encr.dev/parser/est"
+	schema "encr.dev/proto/encore/parser/schema/v1"
+)
+
+// parseConfigs parses the calls to config.Load[T]() and records
+// the resolved config type on the service making the call.
+// Each service can load its configuration at most once.
+func (p *parser) parseConfigs() {
+	for _, pkg := range p.pkgs {
+		for _, file := range pkg.Files {
+			info := p.names[pkg].Files[file]
+			ast.Inspect(file.AST, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+
+				var typeArg ast.Expr
+				fun := call.Fun
+				if idx, ok := fun.(*ast.IndexExpr); ok {
+					fun, typeArg = idx.X, idx.Index
+				}
+				if imp, obj := pkgObj(info, fun); imp != configImportPath || obj != "Load" {
+					return true
+				}
+
+				switch {
+				case typeArg == nil:
+					p.errf(call.Pos(), "config.Load must be called with the config struct type as type argument (like config.Load[Config]())")
+				case len(call.Args) > 0:
+					p.errf(call.Args[0].Pos(), "config.Load does not take any arguments")
+				case pkg.Service == nil:
+					p.errf(call.Pos(), "config.Load can only be called within a service\n\tpackage %s is not considered a service (it has no APIs defined)", pkg.Name)
+				default:
+					p.parseConfig(pkg, file, call, typeArg)
+				}
+				return true
+			})
+		}
+	}
+}
+
+// parseConfig resolves and validates the config type of a config.Load call.
+func (p *parser) parseConfig(pkg *est.Package, file *est.File, call *ast.CallExpr, typeArg ast.Expr) {
+	svc := pkg.Service
+	if prev := svc.Config; prev != nil {
+		p.errf(call.Pos(), "config.Load cannot be called multiple times in service %s (previous call at %s)",
+			svc.Name, p.fset.Position(prev.Call.Pos()))
+		return
+	}
+
+	typ := p.resolveType(pkg, file, typeArg, nil)
+	named := p.unalias(typ).GetNamed()
+	if named == nil || p.decls[named.Id].Type.GetStruct() == nil {
+		p.errf(typeArg.Pos(), "config.Load must be called with a named struct type, got %s", types.ExprString(typeArg))
+		return
+	}
+	if !p.validateConfigType(typeArg.Pos(), "", typ, make(map[uint32]bool)) {
+		return
+	}
+
+	svc.Config = &est.ServiceConfig{
+		Name: p.decls[named.Id].Name,
+		Type: typ,
+		File: file,
+		Call: call,
+	}
+}
+
+// validateConfigType reports whether typ is a supported config type.
+// Configs may consist of builtin types, structs, lists and maps with string keys.
+// Unsupported types are reported as errors at pos.
+func (p *parser) validateConfigType(pos token.Pos, field string, typ *schema.Type, seen map[uint32]bool) bool {
+	switch t := typ.Typ.(type) {
+	case *schema.Type_Builtin:
+		switch t.Builtin {
+		case schema.Builtin_ANY, schema.Builtin_USER_ID:
+			p.errf(pos, "config field %s has unsupported type %s", field, strings.ToLower(t.Builtin.String()))
+			return false
+		}
+		return true
+
+	case *schema.Type_Named:
+		if seen[t.Named.Id] {
+			return true
+		}
+		seen[t.Named.Id] = true
+		return p.validateConfigType(pos, field, p.decls[t.Named.Id].Type, seen)
+
+	case *schema.Type_Struct:
+		ok := true
+		for _, f := range t.Struct.Fields {
+			name := f.Name
+			if field != "" {
+				name = field + "." + f.Name
+			}
+			if !p.validateConfigType(pos, name, f.Typ, seen) {
+				ok = false
+			}
+		}
+		return ok
+
+	case *schema.Type_List:
+		return p.validateConfigType(pos, field, t.List.Elem, seen)
+
+	case *schema.Type_Map:
+		if t.Map.Key.GetBuiltin() != schema.Builtin_STRING {
+			p.errf(pos, "config field %s must be a map with string keys", field)
+			return false
+		}
+		return p.validateConfigType(pos, field, t.Map.Value, seen)
+
+	default:
+		p.errf(pos, "config field %s has unsupported type", field)
+		return false
+	}
+}
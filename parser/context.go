@@ -0,0 +1,196 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+
+	"encr.dev/parser/est"
+	"encr.dev/parser/internal/names"
+)
+
+// validateContextPropagation warns about APIs that call other APIs or query
+// databases without passing on the context they received, so cancellation
+// and deadlines don't propagate to the outbound calls. A call passes on the
+// context if its first argument uses the API's context parameter, or
+// a variable derived from it (like one returned by context.WithTimeout).
+func (p *parser) validateContextPropagation() {
+	for _, svc := range p.svcs {
+		for _, rpc := range svc.RPCs {
+			if rpc.Raw || rpc.Func.Body == nil {
+				continue
+			}
+			info := p.names[rpc.File.Pkg].Files[rpc.File]
+			derived := contextVars(info, rpc.Func)
+
+			ast.Inspect(rpc.Func.Body, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok || len(call.Args) == 0 || !p.isOutboundCall(rpc.File, info, call) {
+					return true
+				}
+				if !usesVars(info, call.Args[0], derived) {
+					p.warnf(call.Args[0].Pos(), "API %s.%s calls %s without passing on its context: "+
+						"pass the context the API received so cancellation and deadlines propagate",
+						svc.Name, rpc.Name, types.ExprString(call.Fun))
+				}
+				return true
+			})
+		}
+	}
+}
+
+// isOutboundCall reports whether call calls an API or queries a database.
+func (p *parser) isOutboundCall(file *est.File, info *names.File, call *ast.CallExpr) bool {
+	if ref := file.References[call.Fun]; ref != nil && ref.Type == est.RPCRefNode {
+		return true
+	}
+	if imp, obj := pkgObj(info, call.Fun); imp == sqldbImportPath {
+		return sqldbQueryMethods[obj]
+	}
+
+	// Queries on database handles declared with sqldb.Named,
+	// either in the same package or in another one.
+	sel, ok := call.Fun.(*ast.SelectorExpr)
+	if !ok || !sqldbQueryMethods[sel.Sel.Name] {
+		return false
+	}
+	switch x := sel.X.(type) {
+	case *ast.Ident:
+		if ri := info.Idents[x]; ri != nil && ri.Package {
+			for _, res := range file.Pkg.Resources {
+				if db, ok := res.(*est.SQLDB); ok && db.DeclName.Name == x.Name {
+					return true
+				}
+			}
+		}
+	case *ast.SelectorExpr:
+		ref := file.References[x]
+		return ref != nil && ref.Type == est.SQLDBNode
+	}
+	return false
+}
+
+// contextVars returns the local variables holding the context received
+// by fd, its first parameter, or a context derived from it.
+// A variable is considered derived if it is assigned from an expression
+// using the context or another derived variable.
+func contextVars(info *names.File, fd *ast.FuncDecl) map[*names.Name]bool {
+	derived := make(map[*names.Name]bool)
+	param, _ := getField(fd.Type.Params, 0)
+	if param == nil || len(param.Names) == 0 || param.Names[0].Name == "_" {
+		return derived
+	}
+	ctxName := param.Names[0].Name
+
+	// The parameter itself is not recorded by name resolution, so find it
+	// through its uses: identifiers with its name that resolve to a variable
+	// not defined within the body.
+	defined := make(map[*names.Name]bool)
+	ast.Inspect(fd.Body, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.AssignStmt:
+			if n.Tok == token.DEFINE {
+				for _, lhs := range n.Lhs {
+					if id, ok := lhs.(*ast.Ident); ok && info.Idents[id] != nil {
+						defined[info.Idents[id]] = true
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			for _, id := range n.Names {
+				if info.Idents[id] != nil {
+					defined[info.Idents[id]] = true
+				}
+			}
+		case *ast.RangeStmt:
+			if n.Tok == token.DEFINE {
+				for _, x := range []ast.Expr{n.Key, n.Value} {
+					if id, ok := x.(*ast.Ident); ok && info.Idents[id] != nil {
+						defined[info.Idents[id]] = true
+					}
+				}
+			}
+		case *ast.FuncLit:
+			for _, field := range n.Type.Params.List {
+				for _, id := range field.Names {
+					if info.Idents[id] != nil {
+						defined[info.Idents[id]] = true
+					}
+				}
+			}
+		}
+		return true
+	})
+	byName := make(map[string][]*names.Name)
+	ast.Inspect(fd.Body, func(node ast.Node) bool {
+		if id, ok := node.(*ast.Ident); ok {
+			if ri := info.Idents[id]; ri != nil && ri.Local {
+				byName[id.Name] = append(byName[id.Name], ri)
+			}
+		}
+		return true
+	})
+	for _, ri := range byName[ctxName] {
+		if !defined[ri] {
+			derived[ri] = true
+		}
+	}
+
+	// Propagate through assignments until no more variables are derived.
+	for changed := len(derived) > 0; changed; {
+		changed = false
+		ast.Inspect(fd.Body, func(node ast.Node) bool {
+			var lhs, rhs []ast.Expr
+			switch n := node.(type) {
+			case *ast.AssignStmt:
+				lhs, rhs = n.Lhs, n.Rhs
+			case *ast.ValueSpec:
+				for _, id := range n.Names {
+					lhs = append(lhs, id)
+				}
+				rhs = n.Values
+			default:
+				return true
+			}
+			for _, x := range rhs {
+				if !usesVars(info, x, derived) {
+					continue
+				}
+				for _, l := range lhs {
+					id, ok := l.(*ast.Ident)
+					if !ok {
+						continue
+					}
+					// Name resolution only records the variables being defined,
+					// so the target of a plain assignment is not known. Assume
+					// it is any variable with that name, to avoid false positives.
+					targets := byName[id.Name]
+					if ri := info.Idents[id]; ri != nil {
+						targets = []*names.Name{ri}
+					}
+					for _, ri := range targets {
+						if !derived[ri] {
+							derived[ri] = true
+							changed = true
+						}
+					}
+				}
+				break
+			}
+			return true
+		})
+	}
+	return derived
+}
+
+// usesVars reports whether expr uses any of the given variables.
+func usesVars(info *names.File, expr ast.Expr, vars map[*names.Name]bool) bool {
+	found := false
+	ast.Inspect(expr, func(node ast.Node) bool {
+		if id, ok := node.(*ast.Ident); ok && vars[info.Idents[id]] {
+			found = true
+		}
+		return !found
+	})
+	return found
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"go/ast"
+	"path"
+
+	"encr.dev/parser/est"
+)
+
+// A ResourceDetector recognizes custom infrastructure resources,
+// declared as package-level variables initialized by calling
+// a factory function, like:
+//
+//	var users = cache.NewKeyspace("users")
+//
+// The package declaring the factory function is expected to be
+// named after the last element of its import path.
+type ResourceDetector interface {
+	// PkgPath is the import path of the package declaring the factory function.
+	PkgPath() string
+
+	// FuncName is the name of the factory function.
+	FuncName() string
+
+	// Detect builds the resource declared by a call to the factory function.
+	// The resource's Type must be est.CustomResource.
+	//
+	// An error means the call does not declare a valid resource,
+	// and is reported at the position of the call.
+	Detect(call *ResourceCall) (est.Resource, error)
+}
+
+// A ResourceCall is a call to the factory function of a ResourceDetector.
+type ResourceCall struct {
+	Pkg   *est.Package
+	File  *est.File
+	Ident *ast.Ident // the variable being declared
+	Call  *ast.CallExpr
+}
+
+// resourceDetector returns the detector configured for the factory
+// function pkgPath.funcName, or nil if there is none.
+func (p *parser) resourceDetector(pkgPath, funcName string) ResourceDetector {
+	for _, d := range p.cfg.ResourceDetectors {
+		if d.PkgPath() == pkgPath && d.FuncName() == funcName {
+			return d
+		}
+	}
+	return nil
+}
+
+// detectResource runs the detector d for the resource declared
+// as ident by call and adds the resulting resource to pkg.
+func (p *parser) detectResource(d ResourceDetector, pkg *est.Package, file *est.File, ident *ast.Ident, call *ast.CallExpr) {
+	fn := path.Base(d.PkgPath()) + "." + d.FuncName()
+	res, err := d.Detect(&ResourceCall{
+		Pkg:   pkg,
+		File:  file,
+		Ident: ident,
+		Call:  call,
+	})
+	if err != nil {
+		p.errf(call.Pos(), "%s: %v", fn, err)
+		return
+	} else if res == nil {
+		return
+	} else if res.Type() != est.CustomResource {
+		p.errf(call.Pos(), "%s: resource detector returned a resource of type %v, not %v", fn, res.Type(), est.CustomResource)
+		return
+	}
+	pkg.Resources = append(pkg.Resources, res)
+}
@@ -0,0 +1,159 @@
+package parser
+
+import (
+	"errors"
+	"go/ast"
+	"go/token"
+	"strconv"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/rogpeppe/go-internal/txtar"
+
+	"encr.dev/parser/est"
+)
+
+// keyspace is a custom resource declared with cache.NewKeyspace,
+// optionally falling back to the keyspaces with the given names,
+// as in cache.NewKeyspace("users", "sessions").
+type keyspace struct {
+	file      *est.File
+	ident     *ast.Ident
+	callPos   token.Pos
+	name      string
+	fallbacks []string
+	byName    map[string]*keyspace
+}
+
+func (k *keyspace) Type() est.ResourceType { return est.CustomResource }
+func (k *keyspace) File() *est.File        { return k.file }
+func (k *keyspace) Ident() *ast.Ident      { return k.ident }
+func (k *keyspace) Pos() token.Pos         { return k.callPos }
+
+// Dependencies implements est.DependentResource.
+func (k *keyspace) Dependencies() []est.Resource {
+	var deps []est.Resource
+	for _, name := range k.fallbacks {
+		if dep := k.byName[name]; dep != nil {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// keyspaceDetector detects keyspaces. A new detector
+// must be used for each parse.
+type keyspaceDetector struct {
+	byName map[string]*keyspace
+}
+
+func newKeyspaceDetector() *keyspaceDetector {
+	return &keyspaceDetector{byName: make(map[string]*keyspace)}
+}
+
+func (*keyspaceDetector) PkgPath() string  { return "example.com/lib/cache" }
+func (*keyspaceDetector) FuncName() string { return "NewKeyspace" }
+
+func (d *keyspaceDetector) Detect(c *ResourceCall) (est.Resource, error) {
+	if len(c.Call.Args) == 0 {
+		return nil, errors.New("expected a keyspace name")
+	}
+	var names []string
+	for _, arg := range c.Call.Args {
+		lit, ok := arg.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return nil, errors.New("keyspace name must be a string literal")
+		}
+		name, _ := strconv.Unquote(lit.Value)
+		names = append(names, name)
+	}
+	k := &keyspace{file: c.File, ident: c.Ident, callPos: c.Call.Pos(), name: names[0], fallbacks: names[1:], byName: d.byName}
+	d.byName[k.name] = k
+	return k, nil
+}
+
+func TestResourceDetectors(t *testing.T) {
+	const src = `
+-- svc/svc.go --
+package svc
+
+import (
+	"context"
+
+	"example.com/lib/cache"
+)
+
+var Users = cache.NewKeyspace("users")
+
+var sessions = cache.NewKeyspace("sessions")
+
+var other = cache.NewOther("other")
+
+//encore:api public
+func Get(ctx context.Context) error { return nil }
+-- svc/util/util.go --
+package util
+
+import "test/svc"
+
+var _ = svc.Users
+`
+	c := qt.New(t)
+	base := t.TempDir()
+	err := txtar.Write(txtar.Parse([]byte(src)), base)
+	c.Assert(err, qt.IsNil)
+
+	cfg := &Config{
+		AppRoot:           base,
+		WorkingDir:        ".",
+		ModulePath:        "test",
+		ResourceDetectors: []ResourceDetector{newKeyspaceDetector()},
+	}
+	res, err := Parse(cfg)
+	c.Assert(err, qt.IsNil)
+
+	var names []string
+	var refs []est.Resource
+	for _, pkg := range res.App.Packages {
+		for _, r := range pkg.Resources {
+			k, ok := r.(*keyspace)
+			c.Assert(ok, qt.IsTrue, qt.Commentf("got resource %T", r))
+			names = append(names, k.ident.Name+"="+k.name)
+		}
+		for _, f := range pkg.Files {
+			for _, node := range f.References {
+				if node.Type == est.CustomResourceNode {
+					refs = append(refs, node.Res)
+				}
+			}
+		}
+	}
+	c.Assert(names, qt.DeepEquals, []string{"Users=users", "sessions=sessions"})
+	c.Assert(refs, qt.HasLen, 1)
+	c.Assert(refs[0].Ident().Name, qt.Equals, "Users")
+
+	// Errors from the detector are reported at the call.
+	a := txtar.Parse([]byte(`
+-- svc/svc.go --
+package svc
+
+import (
+	"context"
+
+	"example.com/lib/cache"
+)
+
+var name = "users"
+
+var users = cache.NewKeyspace(name)
+
+//encore:api public
+func Get(ctx context.Context) error { return nil }
+`))
+	base = t.TempDir()
+	err = txtar.Write(a, base)
+	c.Assert(err, qt.IsNil)
+	cfg.AppRoot = base
+	_, err = Parse(cfg)
+	c.Assert(err, qt.ErrorMatches, `svc/svc.go:11:13: cache.NewKeyspace: keyspace name must be a string literal`)
+}
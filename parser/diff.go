@@ -0,0 +1,327 @@
+package parser
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"encr.dev/parser/est"
+	"encr.dev/parser/paths"
+)
+
+// Changes describes the differences between two parse results
+// of the same application, as computed by Diff.
+//
+// Each kind of entity is identified by a stable key: services by name,
+// APIs by "service.API", cron jobs by ID and databases by name.
+// The changes of each kind are sorted by key.
+type Changes struct {
+	Services  []*Change
+	RPCs      []*Change
+	CronJobs  []*Change
+	Databases []*Change
+}
+
+// Empty reports whether there are no changes.
+func (c *Changes) Empty() bool {
+	return len(c.Services) == 0 && len(c.RPCs) == 0 && len(c.CronJobs) == 0 && len(c.Databases) == 0
+}
+
+// ChangeKind describes how an entity changed.
+type ChangeKind string
+
+const (
+	Added    ChangeKind = "added"
+	Removed  ChangeKind = "removed"
+	Modified ChangeKind = "modified"
+)
+
+// A Change describes a single entity that was added, removed or modified.
+type Change struct {
+	Kind ChangeKind
+	Key  string
+
+	// Fields are the properties that changed, for Kind == Modified.
+	// For services they are "config", "path_prefix", "cors" and "secrets";
+	// for APIs "access", "raw", "path", "methods", "rate_limit" and "signature"
+	// (the request, response or stream message types, including the
+	// types they reference); for cron jobs "title", "schedule" and "endpoint";
+	// and for databases "services" (the services using the database).
+	Fields []string
+}
+
+// Diff computes the changes from the application parsed in old
+// to the application parsed in new.
+func Diff(old, new *Result) *Changes {
+	oldSigs, newSigs := rpcSignatures(old.App), rpcSignatures(new.App)
+	c := &Changes{}
+
+	oldSvcs, newSvcs := serviceMap(old.App), serviceMap(new.App)
+	c.Services = diffKeyed(oldSvcs, newSvcs, func(key string) (fields []string) {
+		a, b := oldSvcs[key], newSvcs[key]
+		if configName(a) != configName(b) {
+			fields = append(fields, "config")
+		}
+		if pathString(a.PathPrefix) != pathString(b.PathPrefix) {
+			fields = append(fields, "path_prefix")
+		}
+		if !equalCORS(a.CORS, b.CORS) {
+			fields = append(fields, "cors")
+		}
+		if !equalStrings(a.Secrets, b.Secrets) {
+			fields = append(fields, "secrets")
+		}
+		return fields
+	})
+
+	oldRPCs, newRPCs := rpcMap(old.App), rpcMap(new.App)
+	c.RPCs = diffKeyed(oldRPCs, newRPCs, func(key string) (fields []string) {
+		a, b := oldRPCs[key], newRPCs[key]
+		if a.Access != b.Access {
+			fields = append(fields, "access")
+		}
+		if a.Raw != b.Raw {
+			fields = append(fields, "raw")
+		}
+		if pathString(a.Path) != pathString(b.Path) {
+			fields = append(fields, "path")
+		}
+		if !equalStrings(a.HTTPMethods, b.HTTPMethods) {
+			fields = append(fields, "methods")
+		}
+		if !equalRateLimit(a.RateLimit, b.RateLimit) {
+			fields = append(fields, "rate_limit")
+		}
+		if oldSigs[a] != newSigs[b] || a.Streaming != b.Streaming {
+			fields = append(fields, "signature")
+		}
+		return fields
+	})
+
+	oldJobs, newJobs := cronJobMap(old.App), cronJobMap(new.App)
+	c.CronJobs = diffKeyed(oldJobs, newJobs, func(key string) (fields []string) {
+		a, b := oldJobs[key], newJobs[key]
+		if a.Title != b.Title {
+			fields = append(fields, "title")
+		}
+		if a.Schedule != b.Schedule {
+			fields = append(fields, "schedule")
+		}
+		if rpcKey(a.Endpoint) != rpcKey(b.Endpoint) {
+			fields = append(fields, "endpoint")
+		}
+		return fields
+	})
+
+	oldDBs, newDBs := databaseMap(old), databaseMap(new)
+	c.Databases = diffKeyed(oldDBs, newDBs, func(key string) (fields []string) {
+		if !equalStrings(oldDBs[key], newDBs[key]) {
+			fields = append(fields, "services")
+		}
+		return fields
+	})
+
+	return c
+}
+
+// diffKeyed computes the changes between two sets of entities, given as maps
+// keyed by the entities' keys. The modified func lists the properties that
+// changed for a key present in both.
+func diffKeyed(old, new interface{}, modified func(key string) []string) []*Change {
+	oldKeys, newKeys := keySet(old), keySet(new)
+	var changes []*Change
+	for key := range oldKeys {
+		if !newKeys[key] {
+			changes = append(changes, &Change{Kind: Removed, Key: key})
+		} else if fields := modified(key); len(fields) > 0 {
+			changes = append(changes, &Change{Kind: Modified, Key: key, Fields: fields})
+		}
+	}
+	for key := range newKeys {
+		if !oldKeys[key] {
+			changes = append(changes, &Change{Kind: Added, Key: key})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].Key < changes[j].Key
+	})
+	return changes
+}
+
+// keySet returns the keys of m, which must be a map with string keys.
+func keySet(m interface{}) map[string]bool {
+	keys := make(map[string]bool)
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys[k.String()] = true
+	}
+	return keys
+}
+
+func serviceMap(app *est.Application) map[string]*est.Service {
+	m := make(map[string]*est.Service, len(app.Services))
+	for _, svc := range app.Services {
+		m[svc.Name] = svc
+	}
+	return m
+}
+
+func rpcMap(app *est.Application) map[string]*est.RPC {
+	m := make(map[string]*est.RPC)
+	for _, svc := range app.Services {
+		for _, rpc := range svc.RPCs {
+			m[rpcKey(rpc)] = rpc
+		}
+	}
+	return m
+}
+
+func cronJobMap(app *est.Application) map[string]*est.CronJob {
+	m := make(map[string]*est.CronJob, len(app.CronJobs))
+	for _, job := range app.CronJobs {
+		m[job.ID] = job
+	}
+	return m
+}
+
+// databaseMap maps each database to the sorted names of the services using it.
+func databaseMap(res *Result) map[string][]string {
+	m := make(map[string][]string)
+	for _, svc := range res.Meta.Svcs {
+		for _, db := range svc.Databases {
+			m[db] = append(m[db], svc.Name)
+		}
+	}
+	for _, svcs := range m {
+		sort.Strings(svcs)
+	}
+	return m
+}
+
+// rpcSignatures describes the request, response and stream message types of each API,
+// together with the types they reference. The descriptions refer to types by name
+// rather than by declaration id, so they can be compared across parse results.
+func rpcSignatures(app *est.Application) map[*est.RPC]string {
+	e := newSchemaExporter(app, NamingAsIs)
+	params := make(map[*est.RPC][]*ExportedParam)
+	for _, svc := range app.Services {
+		for _, rpc := range svc.RPCs {
+			for _, p := range []*est.Param{rpc.Request, rpc.Response, rpc.StreamMessage} {
+				param, _ := e.param(p)
+				params[rpc] = append(params[rpc], param)
+			}
+		}
+	}
+
+	// Resolve the referenced declarations.
+	// Resolving a declaration may queue further declarations.
+	types := make(map[string]*ExportedType)
+	for len(e.queue) > 0 {
+		id := e.queue[0]
+		e.queue = e.queue[1:]
+		if t, err := e.decl(id); err == nil {
+			types[t.Name] = t
+		}
+	}
+
+	sigs := make(map[*est.RPC]string, len(params))
+	for rpc, ps := range params {
+		seen := make(map[string]bool)
+		for _, p := range ps {
+			if p != nil {
+				collectTypeRefs(p.Type, types, seen)
+			}
+		}
+		refs := make([]*ExportedType, 0, len(seen))
+		for name := range seen {
+			if t := types[name]; t != nil {
+				refs = append(refs, t)
+			}
+		}
+		sort.Slice(refs, func(i, j int) bool {
+			return refs[i].Name < refs[j].Name
+		})
+		data, _ := json.Marshal(struct {
+			Params []*ExportedParam
+			Types  []*ExportedType
+		}{ps, refs})
+		sigs[rpc] = string(data)
+	}
+	return sigs
+}
+
+// collectTypeRefs adds the names of the types referenced by ref
+// to seen, including the types referenced by those types.
+func collectTypeRefs(ref *ExportedTypeRef, types map[string]*ExportedType, seen map[string]bool) {
+	if ref == nil {
+		return
+	}
+	if ref.Kind == "named" && !seen[ref.Ref] {
+		seen[ref.Ref] = true
+		if t := types[ref.Ref]; t != nil {
+			for _, f := range t.Fields {
+				collectTypeRefs(f.Type, types, seen)
+			}
+			collectTypeRefs(t.Underlying, types, seen)
+		}
+	}
+	for _, arg := range ref.TypeArgs {
+		collectTypeRefs(arg, types, seen)
+	}
+	for _, f := range ref.Fields {
+		collectTypeRefs(f.Type, types, seen)
+	}
+	collectTypeRefs(ref.Elem, types, seen)
+	collectTypeRefs(ref.Key, types, seen)
+	collectTypeRefs(ref.Value, types, seen)
+}
+
+func rpcKey(rpc *est.RPC) string {
+	if rpc == nil {
+		return ""
+	}
+	return rpc.Svc.Name + "." + rpc.Name
+}
+
+func configName(svc *est.Service) string {
+	if svc.Config == nil {
+		return ""
+	}
+	return svc.Config.Name
+}
+
+func pathString(p *paths.Path) string {
+	if p == nil {
+		return ""
+	}
+	return p.String()
+}
+
+// equalRateLimit reports whether a and b are the same rate limit.
+func equalRateLimit(a, b *est.RateLimit) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// equalCORS reports whether a and b are the same CORS configuration.
+func equalCORS(a, b *est.CORS) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return equalStrings(a.AllowOrigins, b.AllowOrigins) &&
+		equalStrings(a.AllowMethods, b.AllowMethods) &&
+		equalStrings(a.AllowHeaders, b.AllowHeaders)
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
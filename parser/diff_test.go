@@ -0,0 +1,170 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/rogpeppe/go-internal/txtar"
+)
+
+func TestDiff(t *testing.T) {
+	const base = `
+-- users/users.go --
+package users
+
+import "context"
+
+type Params struct {
+	Name string
+}
+
+//encore:api public path=/users/:id
+func Get(ctx context.Context, id string) error { return nil }
+
+//encore:api public method=POST path=/users
+func Create(ctx context.Context, p *Params) error { return nil }
+-- billing/billing.go --
+package billing
+
+import "context"
+
+//encore:api private
+func Charge(ctx context.Context) error { return nil }
+`
+
+	testcases := []struct {
+		desc   string
+		change string // txtar archive of files to overwrite; empty files are deleted
+		want   *Changes
+	}{
+		{
+			desc: "no changes",
+			want: &Changes{},
+		},
+		{
+			desc: "added endpoint",
+			change: `
+-- billing/billing.go --
+package billing
+
+import "context"
+
+//encore:api private
+func Charge(ctx context.Context) error { return nil }
+
+//encore:api private
+func Refund(ctx context.Context) error { return nil }
+`,
+			want: &Changes{
+				RPCs: []*Change{{Kind: Added, Key: "billing.Refund"}},
+			},
+		},
+		{
+			desc: "removed service",
+			change: `
+-- billing/billing.go --
+`,
+			want: &Changes{
+				Services: []*Change{{Kind: Removed, Key: "billing"}},
+				RPCs:     []*Change{{Kind: Removed, Key: "billing.Charge"}},
+			},
+		},
+		{
+			desc: "changed path",
+			change: `
+-- users/users.go --
+package users
+
+import "context"
+
+type Params struct {
+	Name string
+}
+
+//encore:api public path=/user/:id
+func Get(ctx context.Context, id string) error { return nil }
+
+//encore:api public method=POST path=/users
+func Create(ctx context.Context, p *Params) error { return nil }
+`,
+			want: &Changes{
+				RPCs: []*Change{{Kind: Modified, Key: "users.Get", Fields: []string{"path"}}},
+			},
+		},
+		{
+			desc: "changed request type",
+			change: `
+-- users/users.go --
+package users
+
+import "context"
+
+type Params struct {
+	Name  string
+	Email string
+}
+
+//encore:api public path=/users/:id
+func Get(ctx context.Context, id string) error { return nil }
+
+//encore:api public method=PUT path=/users
+func Create(ctx context.Context, p *Params) error { return nil }
+`,
+			want: &Changes{
+				RPCs: []*Change{{Kind: Modified, Key: "users.Create", Fields: []string{"methods", "signature"}}},
+			},
+		},
+		{
+			desc: "added rate limit",
+			change: `
+-- users/users.go --
+package users
+
+import "context"
+
+type Params struct {
+	Name string
+}
+
+//encore:api public path=/users/:id
+func Get(ctx context.Context, id string) error { return nil }
+
+//encore:api public method=POST path=/users rateLimit=10/minute
+func Create(ctx context.Context, p *Params) error { return nil }
+`,
+			want: &Changes{
+				RPCs: []*Change{{Kind: Modified, Key: "users.Create", Fields: []string{"rate_limit"}}},
+			},
+		},
+	}
+
+	parse := func(c *qt.C, archives ...string) *Result {
+		dir := c.TempDir()
+		for _, archive := range archives {
+			for _, f := range txtar.Parse([]byte(archive)).Files {
+				path := filepath.Join(dir, f.Name)
+				if len(f.Data) == 0 {
+					c.Assert(os.Remove(path), qt.IsNil)
+					continue
+				}
+				c.Assert(os.MkdirAll(filepath.Dir(path), 0755), qt.IsNil)
+				c.Assert(os.WriteFile(path, f.Data, 0644), qt.IsNil)
+			}
+		}
+		res, err := Parse(&Config{AppRoot: dir, WorkingDir: ".", ModulePath: "test"})
+		c.Assert(err, qt.IsNil)
+		return res
+	}
+
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := qt.New(t)
+			old := parse(c, base)
+			new := parse(c, base, tc.change)
+			c.Assert(Diff(old, new), qt.DeepEquals, tc.want)
+			c.Assert(Diff(old, old).Empty(), qt.IsTrue)
+		})
+	}
+}
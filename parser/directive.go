@@ -4,21 +4,160 @@ import (
 	"errors"
 	"fmt"
 	"go/ast"
+	goparser "go/parser"
+	"go/scanner"
 	"go/token"
+	"math"
+	"net/url"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
 	"encr.dev/parser/est"
 	"encr.dev/parser/paths"
+	"encr.dev/pkg/errlist"
 )
 
 const directiveParamPath = "path"
 
+// tagRe matches valid API tags.
+var tagRe = regexp.MustCompile(`^[a-z][a-z0-9-]*$`)
+
+var (
+	// corsMethodRe matches valid CORS methods.
+	corsMethodRe = regexp.MustCompile(`^[A-Z]+$`)
+	// corsHeaderRe matches valid HTTP header names (RFC 7230 tokens).
+	corsHeaderRe = regexp.MustCompile("^[A-Za-z0-9!#$%&'*+.^_`|~-]+$")
+)
+
+// validateCORSOrigin reports an error unless origin is "*" or
+// an http or https URL consisting only of a scheme, host and optional port.
+func validateCORSOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	switch {
+	case err != nil || (u.Scheme != "http" && u.Scheme != "https"):
+		return errors.New(`origins must be "*" or URLs like https://example.com`)
+	case u.Host == "" || u.Hostname() == "":
+		return errors.New("origin has no host")
+	case strings.Contains(u.Host, "*"):
+		return errors.New(`wildcards are only supported as the origin "*"`)
+	case u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" || strings.HasSuffix(origin, "?") || strings.HasSuffix(origin, "#"):
+		return errors.New("origins cannot contain user info, a path, a query or a fragment")
+	}
+	return nil
+}
+
+// hasTag reports whether tags contains tag.
+func hasTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseDirectives parses and validates the Encore directives in the Go source file src,
+// without requiring the rest of the application. The filename is only used for
+// recording positions.
+//
+// It returns the directives found, in source order, together with the list
+// of syntax and validation errors encountered. The list is never nil;
+// use its Err method to check whether any errors were found.
+func ParseDirectives(src []byte, filename string) (dirs []Directive, errs *errlist.List) {
+	fset := token.NewFileSet()
+	p := &parser{fset: fset, errors: errlist.New(fset)}
+	defer func() {
+		if e := recover(); e != nil {
+			if _, ok := e.(errlist.Bailout); !ok {
+				panic(e)
+			}
+		}
+		errs = p.errors
+	}()
+
+	f, err := goparser.ParseFile(fset, filename, src, goparser.ParseComments)
+	if list, ok := err.(scanner.ErrorList); ok {
+		for _, e := range list {
+			p.errors.AddRaw(e)
+		}
+	} else if err != nil {
+		p.errors.Add(token.NoPos, err.Error())
+	}
+	if f == nil {
+		return nil, p.errors
+	}
+
+	for _, cg := range f.Comments {
+		if dir, _ := p.parseDirectives(cg); dir != nil {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs, p.errors
+}
+
+// validateDirectiveNamespaces checks the directives in the custom namespaces
+// recognized through Config.DirectivePrefixes, passing each to the handler
+// registered for its namespace.
+func (p *parser) validateDirectiveNamespaces() {
+	recognized := make(map[string]bool)
+	for _, prefix := range p.cfg.DirectivePrefixes {
+		recognized[prefix] = true
+	}
+	for _, pkg := range p.pkgs {
+		for _, file := range pkg.Files {
+			for _, cg := range file.AST.Comments {
+				for _, c := range cg.List {
+					ns, text, ok := splitDirective(c.Text)
+					if !ok || ns == "encore" || !recognized[ns] {
+						continue
+					}
+					if h := p.cfg.DirectiveHandlers[ns]; h == nil {
+						p.errf(c.Pos(), "unknown directive namespace %q (no handler is registered for //%s: directives)", ns, ns)
+					} else if err := h(text); err != nil {
+						p.errf(c.Pos(), "invalid %s directive: %v", ns, err)
+					}
+				}
+			}
+		}
+	}
+}
+
+// splitDirective splits a comment in the standard directive syntax,
+// "//namespace:directive", into its namespace and directive text.
+// It reports false if the comment is not a directive.
+func splitDirective(comment string) (ns, text string, ok bool) {
+	if !strings.HasPrefix(comment, "//") {
+		return "", "", false
+	}
+	ns, text, ok = strings.Cut(comment[2:], ":")
+	if !ok || ns == "" || text == "" || !isDirectiveChar(rune(text[0])) {
+		return "", "", false
+	}
+	for _, r := range ns {
+		if !isDirectiveChar(r) {
+			return "", "", false
+		}
+	}
+	return ns, text, true
+}
+
+// isDirectiveChar reports whether r is allowed in a directive namespace.
+func isDirectiveChar(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9')
+}
+
 // parseDirectives parses the encore:foo directives in cg.
 // It returns the parsed directive, if any, and the
 // remaining doc text after stripping the directive lines.
 //
 // If no directive was found, it reports nil, "".
-func (p *parser) parseDirectives(cg *ast.CommentGroup) (d directive, doc string) {
+func (p *parser) parseDirectives(cg *ast.CommentGroup) (d Directive, doc string) {
 	if cg == nil {
 		return nil, ""
 	}
@@ -30,7 +169,7 @@ func (p *parser) parseDirectives(cg *ast.CommentGroup) (d directive, doc string)
 	// First try the standard syntax and fall back to the legacy syntax
 	// if we don't find any directives.
 
-	var dir directive
+	var dir Directive
 
 	// Standard syntax
 	for _, c := range cg.List {
@@ -41,9 +180,9 @@ func (p *parser) parseDirectives(cg *ast.CommentGroup) (d directive, doc string)
 				continue
 			}
 			var err error
-			dir, err = parseDirective(c.Pos(), c.Text[len(prefix):])
+			dir, err = parseDirective(c.Pos(), c.Pos()+token.Pos(len(prefix)), c.Text[len(prefix):])
 			if err != nil {
-				p.err(c.Pos(), err.Error())
+				p.directiveErr(c.Pos(), err)
 			}
 			err = validateDirective(dir)
 			if err != nil {
@@ -68,9 +207,9 @@ func (p *parser) parseDirectives(cg *ast.CommentGroup) (d directive, doc string)
 				continue
 			}
 			var err error
-			dir, err = parseDirective(cg.Pos(), line[len(prefix):])
+			dir, err = parseDirective(cg.Pos(), token.NoPos, line[len(prefix):])
 			if err != nil {
-				p.err(cg.Pos(), err.Error())
+				p.directiveErr(cg.Pos(), err)
 				continue
 			}
 			err = validateDirective(dir)
@@ -91,47 +230,91 @@ func (p *parser) parseDirectives(cg *ast.CommentGroup) (d directive, doc string)
 }
 
 // parseDirective parses a single directive from line.
-func parseDirective(pos token.Pos, line string) (directive, error) {
-	fields := strings.Fields(line)
-	if len(fields) == 0 {
+// The directive is recorded as being at pos, while linePos is the position
+// of the first byte of line, or NoPos if it is not known.
+func parseDirective(pos, linePos token.Pos, line string) (Directive, error) {
+	l := directiveLine{pos: linePos}
+	fields, err := directiveFields(line)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encore directive: %v", err)
+	} else if len(fields) == 0 {
 		return nil, fmt.Errorf("invalid encore directive: %q", line)
 	}
-	switch fields[0] {
+	switch fields[0].text {
 	default:
-		return nil, fmt.Errorf("invalid encore directive: %q", fields[0])
+		return nil, l.fieldErrorf(fields[0], "invalid encore directive: %q", fields[0].text)
 
 	case "api":
-		rpc := &rpcDirective{
-			TokenPos: pos,
-			Access:   est.Private,
-		}
+		rpc := &RPCDirective{TokenPos: pos}
 		for _, field := range fields[1:] {
-			switch field {
-			case "public":
-				rpc.Access = est.Public
-			case "private":
-				rpc.Access = est.Private
-			case "auth":
-				rpc.Access = est.Auth
+			switch field.text {
+			case "public", "private", "auth":
+				if rpc.Access != "" && rpc.Access != est.AccessType(field.text) {
+					return nil, l.fieldErrorf(field, "conflicting encore:api access types %q and %q", rpc.Access, field.text)
+				}
+				rpc.Access = est.AccessType(field.text)
 			case "raw":
 				rpc.Raw = true
+			case "deprecated":
+				rpc.Deprecated = true
+			case "stream":
+				rpc.Stream = true
 			default:
-				if strings.Contains(field, "=") {
-					parts := strings.SplitN(field, "=", 2)
-					switch parts[0] {
-					case "path":
+				key, value, ok := field.keyValue()
+				if !ok {
+					return nil, l.fieldErrorf(field, "unrecognized encore:api directive field: %q", field.text)
+				}
+				switch key {
+				case "path":
+					var err error
+					rpc.Path, err = paths.Parse(pos, value)
+					if err != nil {
+						return nil, l.valueErrorf(field, "invalid API path: %v", err)
+					}
+				case "method":
+					rpc.Method = strings.Split(value, ",")
+				case "transform":
+					rpc.Transforms = strings.Split(value, ",")
+				case "maxBodySize":
+					var err error
+					rpc.MaxBodySize, err = parseByteSize(value)
+					if err != nil {
+						return nil, l.valueErrorf(field, "invalid maxBodySize %q: %v", value, err)
+					}
+				case "rateLimit":
+					var err error
+					rpc.RateLimit, err = parseRateLimit(value)
+					if err != nil {
+						return nil, l.valueErrorf(field, "invalid rateLimit %q: %v", value, err)
+					}
+				case "status":
+					code, err := strconv.Atoi(value)
+					if err != nil {
+						return nil, l.valueErrorf(field, "invalid status %q: must be an HTTP status code", value)
+					} else if code < 200 || code > 299 {
+						return nil, l.valueErrorf(field, "invalid status %d: must be a 2xx status code", code)
+					}
+					rpc.SuccessStatus = code
+				case "tag":
+					if !tagRe.MatchString(value) {
+						return nil, l.valueErrorf(field, "invalid tag %q: tags must start with a lowercase letter "+
+							"and contain only lowercase letters, digits and hyphens", value)
+					}
+					if !hasTag(rpc.Tags, value) {
+						rpc.Tags = append(rpc.Tags, value)
+					}
+				case "deprecated":
+					msg := value
+					if strings.HasPrefix(msg, `"`) {
 						var err error
-						rpc.Path, err = paths.Parse(pos, parts[1])
-						if err != nil {
-							return nil, fmt.Errorf("invalid API path: %v", err)
+						if msg, err = strconv.Unquote(msg); err != nil {
+							return nil, l.valueErrorf(field, "invalid deprecation message %s: %v", value, err)
 						}
-					case "method":
-						rpc.Method = strings.Split(parts[1], ",")
-					default:
-						return nil, fmt.Errorf("unrecognized encore:api directive field: %q", parts[0])
 					}
-				} else {
-					return nil, fmt.Errorf("unrecognized encore:api directive field: %q", field)
+					rpc.Deprecated = true
+					rpc.DeprecationMessage = msg
+				default:
+					return nil, l.keyErrorf(field, "unrecognized encore:api directive field: %q", key)
 				}
 			}
 		}
@@ -140,17 +323,242 @@ func parseDirective(pos token.Pos, line string) (directive, error) {
 
 	case "authhandler":
 		if len(fields) > 1 {
-			return nil, fmt.Errorf("unrecognized encore:authhandler directive field: %q", fields[1])
+			return nil, l.fieldErrorf(fields[1], "unrecognized encore:authhandler directive field: %q", fields[1].text)
 		}
-		return &authHandlerDirective{TokenPos: pos}, nil
+		return &AuthHandlerDirective{TokenPos: pos}, nil
+
+	case "service":
+		svc := &ServiceDirective{TokenPos: pos}
+		cors := &est.CORS{}
+		var corsOpt directiveField // the last cors-methods or cors-headers field, if any
+		for _, field := range fields[1:] {
+			key, value, ok := field.keyValue()
+			if !ok {
+				return nil, l.fieldErrorf(field, "unrecognized encore:service directive field: %q", field.text)
+			}
+			switch key {
+			case directiveParamPath:
+				var err error
+				svc.PathPrefix, err = paths.Parse(pos, value)
+				if err != nil {
+					return nil, l.valueErrorf(field, "invalid service path prefix: %v", err)
+				} else if svc.PathPrefix.NumParams() > 0 {
+					return nil, l.valueErrorf(field, "invalid service path prefix: prefix cannot contain path parameters")
+				}
+			case "cors":
+				for _, origin := range strings.Split(value, ",") {
+					if err := validateCORSOrigin(origin); err != nil {
+						return nil, l.valueErrorf(field, "invalid CORS origin %q: %v", origin, err)
+					}
+					if !hasTag(cors.AllowOrigins, origin) {
+						cors.AllowOrigins = append(cors.AllowOrigins, origin)
+					}
+				}
+			case "cors-methods":
+				for _, m := range strings.Split(value, ",") {
+					if !corsMethodRe.MatchString(m) {
+						return nil, l.valueErrorf(field, "invalid CORS method %q: methods must be uppercase, like GET or POST", m)
+					}
+					if !hasTag(cors.AllowMethods, m) {
+						cors.AllowMethods = append(cors.AllowMethods, m)
+					}
+				}
+				corsOpt = field
+			case "cors-headers":
+				for _, h := range strings.Split(value, ",") {
+					if !corsHeaderRe.MatchString(h) {
+						return nil, l.valueErrorf(field, "invalid CORS header %q: not a valid HTTP header name", h)
+					}
+					if !hasTag(cors.AllowHeaders, h) {
+						cors.AllowHeaders = append(cors.AllowHeaders, h)
+					}
+				}
+				corsOpt = field
+			case "defaultAccess":
+				switch est.AccessType(value) {
+				case est.Public, est.Private, est.Auth:
+					svc.DefaultAccess = est.AccessType(value)
+				default:
+					return nil, l.valueErrorf(field, "invalid defaultAccess %q: must be public, private or auth", value)
+				}
+			default:
+				return nil, l.keyErrorf(field, "unrecognized encore:service directive field: %q", key)
+			}
+		}
+		if len(cors.AllowOrigins) > 0 {
+			svc.CORS = cors
+		} else if key, _, ok := corsOpt.keyValue(); ok {
+			return nil, l.keyErrorf(corsOpt, "%s requires the allowed origins to be declared with cors=", key)
+		}
+		return svc, nil
+	}
+}
+
+// rateLimitWindows are the time windows accepted by parseRateLimit.
+var rateLimitWindows = map[string]time.Duration{
+	"second": time.Second,
+	"minute": time.Minute,
+	"hour":   time.Hour,
+}
+
+// parseRateLimit parses a rate limit of the form "<requests>/<unit>",
+// like "100/minute", where unit is second, minute or hour.
+func parseRateLimit(s string) (*est.RateLimit, error) {
+	num, unit, ok := strings.Cut(s, "/")
+	if !ok {
+		return nil, errors.New("must be of the form <requests>/<unit>, like 100/minute")
+	}
+	n, err := strconv.Atoi(num)
+	if err != nil || n <= 0 {
+		return nil, fmt.Errorf("the number of requests must be a positive integer, not %q", num)
+	}
+	window, ok := rateLimitWindows[unit]
+	if !ok {
+		return nil, fmt.Errorf("unknown unit %q: must be second, minute or hour", unit)
+	}
+	return &est.RateLimit{Requests: n, Window: window}, nil
+}
+
+// byteSizeUnits are the suffixes accepted by parseByteSize.
+var byteSizeUnits = []struct {
+	suffix string
+	size   int64
+}{
+	{"KB", 1 << 10},
+	{"MB", 1 << 20},
+	{"GB", 1 << 30},
+}
+
+// parseByteSize parses a positive number of bytes, like "512", "64KB" or "10MB".
+// The KB, MB and GB suffixes are powers of 1024.
+func parseByteSize(s string) (int64, error) {
+	num, unit := s, int64(1)
+	for _, u := range byteSizeUnits {
+		if strings.HasSuffix(s, u.suffix) {
+			num, unit = strings.TrimSuffix(s, u.suffix), u.size
+			break
+		}
+	}
+	n, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || num == "" || num[0] < '0' || num[0] > '9' {
+		return 0, errors.New("must be a number of bytes, optionally followed by KB, MB or GB")
+	} else if n == 0 {
+		return 0, errors.New("must be positive")
+	} else if n > math.MaxInt64/unit {
+		return 0, errors.New("size too large")
+	}
+	return n * unit, nil
+}
+
+// directiveFields splits line into fields separated by whitespace,
+// like strings.Fields, except that double-quoted strings
+// (like deprecated="use Foo instead") are kept within a single field.
+func directiveFields(line string) ([]directiveField, error) {
+	var (
+		fields  []directiveField
+		field   strings.Builder
+		start   int
+		quoted  bool
+		escaped bool
+	)
+	for i, r := range line {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && r == '\\':
+			escaped = true
+		case r == '"':
+			quoted = !quoted
+		case !quoted && unicode.IsSpace(r):
+			if field.Len() > 0 {
+				fields = append(fields, directiveField{text: field.String(), offset: start})
+				field.Reset()
+			}
+			continue
+		}
+		if field.Len() == 0 {
+			start = i
+		}
+		field.WriteRune(r)
+	}
+	if quoted {
+		return nil, errors.New("unterminated quoted string")
+	} else if field.Len() > 0 {
+		fields = append(fields, directiveField{text: field.String(), offset: start})
 	}
+	return fields, nil
+}
+
+// A directiveField is a single whitespace-separated field of a directive,
+// like "public" or "path=/foo".
+type directiveField struct {
+	text   string
+	offset int // byte offset within the directive line
 }
 
-func validateDirective(d directive) error {
+// keyValue splits the field into a key and value at the first "=".
+// It reports false if the field has no value.
+func (f directiveField) keyValue() (key, value string, ok bool) {
+	return strings.Cut(f.text, "=")
+}
+
+// A directiveError is an error in a directive that records
+// the range of the directive text it applies to, if known.
+type directiveError struct {
+	msg        string
+	start, end token.Pos // NoPos if unknown
+}
+
+func (e *directiveError) Error() string { return e.msg }
+
+// directiveLine is a directive line being parsed.
+type directiveLine struct {
+	pos token.Pos // position of the line's first byte, or NoPos if unknown
+}
+
+// errorf returns an error for the n bytes of the line starting at offset.
+func (l directiveLine) errorf(offset, n int, format string, args ...interface{}) error {
+	err := &directiveError{msg: fmt.Sprintf(format, args...)}
+	if l.pos.IsValid() {
+		err.start = l.pos + token.Pos(offset)
+		err.end = err.start + token.Pos(n)
+	}
+	return err
+}
+
+// fieldErrorf returns an error for the field f.
+func (l directiveLine) fieldErrorf(f directiveField, format string, args ...interface{}) error {
+	return l.errorf(f.offset, len(f.text), format, args...)
+}
+
+// keyErrorf returns an error for the key of the field f.
+func (l directiveLine) keyErrorf(f directiveField, format string, args ...interface{}) error {
+	key, _, _ := f.keyValue()
+	return l.errorf(f.offset, len(key), format, args...)
+}
+
+// valueErrorf returns an error for the value of the field f.
+func (l directiveLine) valueErrorf(f directiveField, format string, args ...interface{}) error {
+	key, value, _ := f.keyValue()
+	return l.errorf(f.offset+len(key)+1, len(value), format, args...)
+}
+
+// directiveErr reports an error from parsing or validating a directive at pos,
+// recording the span of the offending directive text if known.
+func (p *parser) directiveErr(pos token.Pos, err error) {
+	var de *directiveError
+	if errors.As(err, &de) && de.start.IsValid() {
+		p.errors.AddSpan(pos, de.start, de.end, err.Error())
+	} else {
+		p.err(pos, err.Error())
+	}
+}
+
+func validateDirective(d Directive) error {
 	switch td := d.(type) {
-	case *rpcDirective:
+	case *RPCDirective:
 		return validateRPCDirective(td)
-	case *authHandlerDirective:
+	case *AuthHandlerDirective, *ServiceDirective:
 		return nil
 	default:
 		return errors.New("unexpected directive type")
@@ -158,10 +566,18 @@ func validateDirective(d directive) error {
 }
 
 // validateRPCDirective ensures that the parsed RPC directive is valid.
-func validateRPCDirective(d *rpcDirective) error {
-	if d.Access == est.Private && d.Raw {
-		// We don't support private raw APIs for now
-		return errors.New("private APIs cannot be declared raw")
+// The access type is only validated if declared, as the default
+// depends on the service; see validateRPCAccess.
+func validateRPCDirective(d *RPCDirective) error {
+	if d.Access != "" {
+		if err := validateRPCAccess(d, d.Access); err != nil {
+			return err
+		}
+	}
+	if d.Raw && d.Stream {
+		return errors.New("raw APIs cannot be declared stream")
+	} else if d.MaxBodySize > 0 && !d.Raw {
+		return errors.New("maxBodySize can only be set on raw APIs")
 	}
 
 	for _, m := range d.Method {
@@ -174,30 +590,85 @@ func validateRPCDirective(d *rpcDirective) error {
 		}
 	}
 
+	for _, t := range d.Transforms {
+		if !est.IsKnownTransform(t) {
+			return fmt.Errorf("unknown API transform %q (valid transforms are: %s)",
+				t, strings.Join(est.KnownTransforms(), ", "))
+		}
+	}
+
+	return nil
+}
+
+// validateRPCAccess ensures that the RPC directive d is valid
+// for an API with the given effective access type.
+func validateRPCAccess(d *RPCDirective, access est.AccessType) error {
+	if access == est.Private && d.Raw {
+		// We don't support private raw APIs for now
+		return errors.New("private APIs cannot be declared raw")
+	} else if access == est.Private && d.RateLimit != nil {
+		return errors.New("rateLimit cannot be set on private APIs: they are not served by the API gateway enforcing it")
+	}
 	return nil
 }
 
-// The directive interface is a marker interface for the directive types we support.
-type directive interface {
+// Directive is a marker interface for the directive types we support:
+// *RPCDirective, *AuthHandlerDirective and *ServiceDirective.
+type Directive interface {
 	Pos() token.Pos
 	directive()
 }
 
-// An rpcDirective is the parsed representation of the encore:api directive.
-type rpcDirective struct {
-	TokenPos token.Pos
-	Access   est.AccessType
-	Raw      bool
-	Method   []string
-	Path     *paths.Path // nil if not specified
+// An RPCDirective is the parsed representation of the encore:api directive.
+type RPCDirective struct {
+	TokenPos   token.Pos
+	Access     est.AccessType // "" if not specified
+	Raw        bool
+	Method     []string
+	Path       *paths.Path // nil if not specified
+	Transforms []string    // transformation pipeline, in order
+	Stream     bool        // streaming API, taking an api.Stream parameter
+
+	// MaxBodySize is the max size of a raw API's request body, in bytes.
+	// It is zero if not specified.
+	MaxBodySize int64
+
+	// SuccessStatus is the HTTP status code to respond with on success.
+	// It is zero if not specified.
+	SuccessStatus int
+
+	// RateLimit is the API's rate limit, or nil if not specified.
+	RateLimit *est.RateLimit
+
+	// Tags are the tags the API is grouped under for documentation,
+	// declared with one or more tag=name fields, without duplicates.
+	Tags []string
+
+	// Deprecated is true if the API is marked as deprecated,
+	// with "deprecated" or deprecated="message".
+	Deprecated         bool
+	DeprecationMessage string // optional
 }
 
-// An authHandlerDirective is the parsed representation of the encore:authhandler directive.
-type authHandlerDirective struct {
+// An AuthHandlerDirective is the parsed representation of the encore:authhandler directive.
+type AuthHandlerDirective struct {
 	TokenPos token.Pos
 }
 
-func (d *rpcDirective) Pos() token.Pos         { return d.TokenPos }
-func (d *authHandlerDirective) Pos() token.Pos { return d.TokenPos }
-func (*rpcDirective) directive()               {}
-func (*authHandlerDirective) directive()       {}
+// A ServiceDirective is the parsed representation of the encore:service directive.
+type ServiceDirective struct {
+	TokenPos   token.Pos
+	PathPrefix *paths.Path // nil if not specified
+	CORS       *est.CORS   // nil if not specified
+
+	// DefaultAccess is the access type of the service's APIs
+	// that don't declare one, or "" if not specified.
+	DefaultAccess est.AccessType
+}
+
+func (d *RPCDirective) Pos() token.Pos         { return d.TokenPos }
+func (d *AuthHandlerDirective) Pos() token.Pos { return d.TokenPos }
+func (d *ServiceDirective) Pos() token.Pos     { return d.TokenPos }
+func (*RPCDirective) directive()               {}
+func (*AuthHandlerDirective) directive()       {}
+func (*ServiceDirective) directive()           {}
@@ -1,6 +1,7 @@
 package parser
 
 import (
+	"errors"
 	"go/token"
 	"testing"
 
@@ -9,6 +10,7 @@ import (
 	schema "encr.dev/proto/encore/parser/schema/v1"
 
 	qt "github.com/frankban/quicktest"
+	"github.com/rogpeppe/go-internal/txtar"
 )
 
 func TestParseDirectiveRPC(t *testing.T) {
@@ -17,14 +19,14 @@ func TestParseDirectiveRPC(t *testing.T) {
 	testcases := []struct {
 		desc        string
 		line        string
-		expected    *rpcDirective
+		expected    *RPCDirective
 		expectedErr string
 	}{
 		{
 			desc:        "api public endpoint",
 			line:        "api public",
 			expectedErr: "",
-			expected: &rpcDirective{
+			expected: &RPCDirective{
 				Access:   est.Public,
 				Raw:      false,
 				TokenPos: staticPos,
@@ -34,17 +36,26 @@ func TestParseDirectiveRPC(t *testing.T) {
 			desc:        "api private endpoint",
 			line:        "api private",
 			expectedErr: "",
-			expected: &rpcDirective{
+			expected: &RPCDirective{
 				Access:   est.Private,
 				Raw:      false,
 				TokenPos: staticPos,
 			},
 		},
+		{
+			desc:        "api without access type",
+			line:        "api method=GET",
+			expectedErr: "",
+			expected: &RPCDirective{
+				TokenPos: staticPos,
+				Method:   []string{"GET"},
+			},
+		},
 		{
 			desc:        "custom method",
 			line:        "api public method=FOO",
 			expectedErr: "",
-			expected: &rpcDirective{
+			expected: &RPCDirective{
 				Access:   est.Public,
 				Raw:      false,
 				TokenPos: staticPos,
@@ -55,7 +66,7 @@ func TestParseDirectiveRPC(t *testing.T) {
 			desc:        "multiple methods",
 			line:        "api public raw method=GET,POST",
 			expectedErr: "",
-			expected: &rpcDirective{
+			expected: &RPCDirective{
 				Access:   est.Public,
 				Raw:      true,
 				TokenPos: staticPos,
@@ -66,7 +77,7 @@ func TestParseDirectiveRPC(t *testing.T) {
 			desc:        "api with params, trailing =",
 			line:        "api public raw path=/bar",
 			expectedErr: "",
-			expected: &rpcDirective{
+			expected: &RPCDirective{
 				Access:   est.Public,
 				Raw:      true,
 				TokenPos: staticPos,
@@ -75,18 +86,328 @@ func TestParseDirectiveRPC(t *testing.T) {
 				}},
 			},
 		},
+		{
+			desc:        "api with transforms",
+			line:        "api public transform=trim,mask",
+			expectedErr: "",
+			expected: &RPCDirective{
+				Access:     est.Public,
+				TokenPos:   staticPos,
+				Transforms: []string{"trim", "mask"},
+			},
+		},
+		{
+			desc:        "deprecated api",
+			line:        "api public deprecated",
+			expectedErr: "",
+			expected: &RPCDirective{
+				Access:     est.Public,
+				TokenPos:   staticPos,
+				Deprecated: true,
+			},
+		},
+		{
+			desc:        "deprecated api with message",
+			line:        `api public deprecated="use \"Bar\" instead" method=GET`,
+			expectedErr: "",
+			expected: &RPCDirective{
+				Access:             est.Public,
+				TokenPos:           staticPos,
+				Method:             []string{"GET"},
+				Deprecated:         true,
+				DeprecationMessage: `use "Bar" instead`,
+			},
+		},
+		{
+			desc:        "success status",
+			line:        "api public method=POST status=201",
+			expectedErr: "",
+			expected: &RPCDirective{
+				Access:        est.Public,
+				TokenPos:      staticPos,
+				Method:        []string{"POST"},
+				SuccessStatus: 201,
+			},
+		},
+		{
+			desc:        "non-2xx success status",
+			line:        "api public status=500",
+			expectedErr: "invalid status 500: must be a 2xx status code",
+		},
+		{
+			desc:        "tags",
+			line:        "api public tag=billing tag=admin tag=billing",
+			expectedErr: "",
+			expected: &RPCDirective{
+				Access:   est.Public,
+				TokenPos: staticPos,
+				Tags:     []string{"billing", "admin"},
+			},
+		},
+		{
+			desc:        "invalid tag",
+			line:        "api public tag=9lives",
+			expectedErr: `invalid tag "9lives": tags must start with a lowercase letter and contain only lowercase letters, digits and hyphens`,
+		},
+		{
+			desc:        "unterminated deprecation message",
+			line:        `api public deprecated="use Bar`,
+			expectedErr: "invalid encore directive: unterminated quoted string",
+		},
+		{
+			desc:        "repeated access type",
+			line:        "api private private",
+			expectedErr: "",
+			expected: &RPCDirective{
+				Access:   est.Private,
+				TokenPos: staticPos,
+			},
+		},
+		{
+			desc:        "conflicting access types",
+			line:        "api private method=GET public",
+			expectedErr: `conflicting encore:api access types "private" and "public"`,
+		},
 	}
 	for _, tc := range testcases {
 		t.Run(tc.desc, func(t *testing.T) {
 			c := qt.New(t)
-			dir, err := parseDirective(staticPos, tc.line)
+			dir, err := parseDirective(staticPos, token.NoPos, tc.line)
 			if tc.expectedErr != "" || err != nil {
 				c.Assert(err, qt.ErrorMatches, tc.expectedErr)
 				return
 			}
-			rpcDir, ok := dir.(*rpcDirective)
+			rpcDir, ok := dir.(*RPCDirective)
 			c.Assert(ok, qt.IsTrue)
 			c.Assert(rpcDir, qt.DeepEquals, tc.expected)
 		})
 	}
 }
+
+func TestParseDirectives(t *testing.T) {
+	testcases := []struct {
+		desc        string
+		src         string
+		expected    []Directive
+		expectedErr string
+	}{
+		{
+			desc: "valid directives",
+			src: `package foo
+
+//encore:api public method=POST
+func Foo(ctx context.Context) error { return nil }
+
+// Bar does things.
+//encore:api private
+func Bar(ctx context.Context) error { return nil }
+
+//encore:authhandler
+func Auth(ctx context.Context, token string) (auth.UID, error) { return "", nil }
+`,
+			expected: []Directive{
+				&RPCDirective{Access: est.Public, Method: []string{"POST"}},
+				&RPCDirective{Access: est.Private},
+				&AuthHandlerDirective{},
+			},
+		},
+		{
+			desc: "no directives",
+			src: `package foo
+
+// Foo is not an API.
+func Foo() {}
+`,
+			expected: nil,
+		},
+		{
+			desc: "unknown field",
+			src: `package foo
+
+//encore:api public foo=bar
+func Foo(ctx context.Context) error { return nil }
+`,
+			expectedErr: `foo.go:3:1: unrecognized encore:api directive field: "foo"`,
+		},
+		{
+			desc: "invalid option combination",
+			src: `package foo
+
+//encore:api private raw
+func Foo(w http.ResponseWriter, req *http.Request) {}
+`,
+			expectedErr: `foo.go:3:1: private APIs cannot be declared raw`,
+		},
+		{
+			desc: "syntax error",
+			src: `package foo
+
+//encore:api public
+func Foo(ctx context.Context error { return nil }
+`,
+			expectedErr: `foo.go:4:30: .+`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := qt.New(t)
+			dirs, errs := ParseDirectives([]byte(tc.src), "foo.go")
+			c.Assert(errs, qt.IsNotNil)
+			if tc.expectedErr != "" {
+				c.Assert(errs.Err(), qt.ErrorMatches, tc.expectedErr)
+				return
+			}
+			c.Assert(errs.Err(), qt.IsNil)
+
+			// Ignore positions when comparing the directives.
+			for _, d := range dirs {
+				switch d := d.(type) {
+				case *RPCDirective:
+					d.TokenPos = token.NoPos
+				case *AuthHandlerDirective:
+					d.TokenPos = token.NoPos
+				}
+			}
+			c.Assert(dirs, qt.DeepEquals, tc.expected)
+		})
+	}
+}
+
+func TestDirectiveErrorSpans(t *testing.T) {
+	testcases := []struct {
+		desc      string
+		directive string
+		span      string // start-end, or "" for no span
+	}{
+		{
+			desc:      "unknown option",
+			directive: "//encore:api protcted",
+			span:      "foo.go:3:14-foo.go:3:22",
+		},
+		{
+			desc:      "unknown option key",
+			directive: "//encore:api public foo=bar",
+			span:      "foo.go:3:21-foo.go:3:24",
+		},
+		{
+			desc:      "invalid option value",
+			directive: "//encore:api public path=foo",
+			span:      "foo.go:3:26-foo.go:3:29",
+		},
+		{
+			desc:      "invalid max body size",
+			directive: "//encore:api public raw maxBodySize=abc",
+			span:      "foo.go:3:37-foo.go:3:40",
+		},
+		{
+			desc:      "invalid tag",
+			directive: "//encore:api public tag=Billing",
+			span:      "foo.go:3:25-foo.go:3:32",
+		},
+		{
+			desc:      "unknown directive",
+			directive: "//encore:apii public",
+			span:      "foo.go:3:10-foo.go:3:14",
+		},
+		{
+			desc:      "legacy syntax",
+			directive: "// encore:api protcted",
+			span:      "",
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := qt.New(t)
+			src := "package foo\n\n" + tc.directive + "\nfunc Foo(ctx context.Context) error { return nil }\n"
+			_, errs := ParseDirectives([]byte(src), "foo.go")
+			list := errs.Errors()
+			c.Assert(list, qt.HasLen, 1)
+
+			// The error itself is still reported at the directive.
+			c.Assert(list[0].Pos.String(), qt.Equals, "foo.go:3:1")
+			span := errs.Span(list[0])
+			if tc.span == "" {
+				c.Assert(span, qt.IsNil)
+				return
+			}
+			c.Assert(span, qt.IsNotNil)
+			c.Assert(span.Start.String()+"-"+span.End.String(), qt.Equals, tc.span)
+		})
+	}
+}
+
+func TestDirectiveNamespaces(t *testing.T) {
+	const src = `
+-- svc/svc.go --
+package svc
+
+import "context"
+
+//myorg:audit level=high
+//encore:api public
+func Foo(ctx context.Context) error { return nil }
+
+//other:thing
+type Params struct{}
+`
+	testcases := []struct {
+		desc        string
+		prefixes    []string
+		handlers    map[string]DirectiveHandler
+		expectedErr string
+		handled     []string
+	}{
+		{
+			desc: "unrecognized namespace",
+		},
+		{
+			desc:        "recognized namespace without handler",
+			prefixes:    []string{"encore", "myorg"},
+			expectedErr: `(?s).*svc/svc.go:5:1: unknown directive namespace "myorg".*`,
+		},
+		{
+			desc:     "recognized namespace with handler",
+			prefixes: []string{"encore", "myorg"},
+			handlers: map[string]DirectiveHandler{"myorg": nil},
+			handled:  []string{"audit level=high"},
+		},
+		{
+			desc:        "handler error",
+			prefixes:    []string{"encore", "myorg"},
+			handlers:    map[string]DirectiveHandler{"myorg": nil},
+			expectedErr: `(?s).*svc/svc.go:5:1: invalid myorg directive: unknown level "high".*`,
+		},
+	}
+	for _, tc := range testcases {
+		t.Run(tc.desc, func(t *testing.T) {
+			c := qt.New(t)
+			base := t.TempDir()
+			c.Assert(txtar.Write(txtar.Parse([]byte(src)), base), qt.IsNil)
+
+			var handled []string
+			for ns := range tc.handlers {
+				tc.handlers[ns] = func(directive string) error {
+					if tc.expectedErr != "" {
+						return errors.New(`unknown level "high"`)
+					}
+					handled = append(handled, directive)
+					return nil
+				}
+			}
+
+			_, err := Parse(&Config{
+				AppRoot:           base,
+				WorkingDir:        ".",
+				ModulePath:        "test",
+				DirectivePrefixes: tc.prefixes,
+				DirectiveHandlers: tc.handlers,
+			})
+			if tc.expectedErr != "" {
+				c.Assert(err, qt.ErrorMatches, tc.expectedErr)
+				return
+			}
+			c.Assert(err, qt.IsNil)
+			c.Assert(handled, qt.DeepEquals, tc.handled)
+		})
+	}
+}
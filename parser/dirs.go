@@ -1,13 +1,15 @@
 package parser
 
 import (
+	"bytes"
 	"fmt"
 	"go/ast"
 	"go/build"
+	"go/build/constraint"
 	goparser "go/parser"
 	"go/scanner"
 	"go/token"
-	"io/ioutil"
+	"io/fs"
 	"os"
 	"path"
 	"path/filepath"
@@ -22,18 +24,69 @@ import (
 // relative path from the original root dir.
 type walkFunc func(dir, relPath string, files []os.FileInfo) error
 
+// skipFunc reports whether walkDirs should skip the directory
+// with the given name, along with everything below it.
+type skipFunc func(name string) bool
+
+// osFS is the filesystem the parser reads from when Config.FS is nil.
+// Unlike os.DirFS it accepts OS-native paths, including absolute ones,
+// so file names are reported the same as when reading from disk directly.
+type osFS struct{}
+
+func (osFS) Open(name string) (fs.File, error)          { return os.Open(name) }
+func (osFS) ReadDir(name string) ([]fs.DirEntry, error) { return os.ReadDir(name) }
+func (osFS) ReadFile(name string) ([]byte, error)       { return os.ReadFile(name) }
+func (osFS) Stat(name string) (fs.FileInfo, error)      { return os.Stat(name) }
+
+// slashFS adapts a filesystem provided with Config.FS to the
+// OS-native paths used by the parser, which joins paths with filepath.Join.
+type slashFS struct {
+	fsys fs.FS
+}
+
+func (s slashFS) Open(name string) (fs.File, error) {
+	return s.fsys.Open(s.name(name))
+}
+
+func (s slashFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	return fs.ReadDir(s.fsys, s.name(name))
+}
+
+func (s slashFS) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(s.fsys, s.name(name))
+}
+
+func (s slashFS) Stat(name string) (fs.FileInfo, error) {
+	return fs.Stat(s.fsys, s.name(name))
+}
+
+// name converts an OS-native path to a path in the filesystem.
+func (s slashFS) name(name string) string {
+	return path.Clean(filepath.ToSlash(name))
+}
+
+// configFS returns the filesystem to parse the app described by cfg from.
+func configFS(cfg *Config) fs.FS {
+	if cfg.FS == nil {
+		return osFS{}
+	}
+	return slashFS{fsys: cfg.FS}
+}
+
 // walkDirs is like filepath.Walk but it calls walkFn once for each directory and not for individual files.
 // It also reports both the full path and the path relative to the given root dir.
-// It does not allow skipping directories in any way; any error returned from walkFn aborts the walk.
-func walkDirs(root string, walkFn walkFunc) error {
-	return walkDir(root, ".", walkFn)
+// Subdirectories for which skip reports true are not visited; the root is always visited.
+// If walkFn returns filepath.SkipDir the directory's subdirectories are not visited;
+// any other error returned from walkFn aborts the walk.
+func walkDirs(fsys fs.FS, root string, skip skipFunc, walkFn walkFunc) error {
+	return walkDir(fsys, root, ".", skip, walkFn)
 }
 
 // walkDir processes a single directory and recurses.
 // dir is the current directory path, and rel is the relative path from the original root.
 // rel is always in slash form, while dir uses the OS-native filepath separator.
-func walkDir(dir, rel string, walkFn walkFunc) error {
-	entries, err := ioutil.ReadDir(dir)
+func walkDir(fsys fs.FS, dir, rel string, skip skipFunc, walkFn walkFunc) error {
+	entries, err := readDir(fsys, dir)
 	if err != nil {
 		return err
 	}
@@ -42,35 +95,65 @@ func walkDir(dir, rel string, walkFn walkFunc) error {
 	var dirs, files []os.FileInfo
 	for _, entry := range entries {
 		if entry.IsDir() {
-			dirs = append(dirs, entry)
+			if skip == nil || !skip(entry.Name()) {
+				dirs = append(dirs, entry)
+			}
 		} else {
 			files = append(files, entry)
 		}
 	}
 
-	if err := walkFn(dir, rel, files); err != nil {
+	if err := walkFn(dir, rel, files); err == filepath.SkipDir {
+		return nil
+	} else if err != nil {
 		return err
 	}
 
 	for _, d := range dirs {
 		dir2 := filepath.Join(dir, d.Name())
 		rel2 := path.Join(rel, d.Name())
-		if err := walkDir(dir2, rel2, walkFn); err != nil {
+		if err := walkDir(fsys, dir2, rel2, skip, walkFn); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
-// parseDir is like go/parser.ParseDir but it constructs *est.File objects instead.
-func parseDir(buildContext build.Context, fset *token.FileSet, dir, relPath string, filter func(os.FileInfo) bool, mode goparser.Mode) (pkgs map[string]*ast.Package, files []*est.File, err error) {
-	fd, err := os.Open(dir)
+// readDir reads the directory dir in fsys,
+// returning its entries sorted by name.
+func readDir(fsys fs.FS, dir string) ([]os.FileInfo, error) {
+	entries, err := fs.ReadDir(fsys, dir)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+	infos := make([]os.FileInfo, 0, len(entries))
+	for _, e := range entries {
+		fi, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+		infos = append(infos, fi)
+	}
+	return infos, nil
+}
+
+// skipDir reports whether collectPackages skips the directory with the given name.
+// Like the go tool it ignores directories beginning with "." or "_"
+// and testdata directories. Vendor directories are skipped unless includeVendor is set.
+func skipDir(name string, includeVendor bool) bool {
+	switch {
+	case strings.HasPrefix(name, "."), strings.HasPrefix(name, "_"), name == "testdata":
+		return true
+	case name == "vendor":
+		return !includeVendor
+	default:
+		return false
 	}
-	defer fd.Close()
+}
 
-	list, err := fd.Readdir(-1)
+// parseDir is like go/parser.ParseDir but it constructs *est.File objects instead.
+func parseDir(fsys fs.FS, buildContext build.Context, fset *token.FileSet, dir, relPath string, filter func(os.FileInfo) bool, mode goparser.Mode) (pkgs map[string]*ast.Package, files []*est.File, err error) {
+	list, err := readDir(fsys, dir)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -84,7 +167,7 @@ func parseDir(buildContext build.Context, fset *token.FileSet, dir, relPath stri
 	for _, d := range list {
 		if strings.HasSuffix(d.Name(), ".go") && (filter == nil || filter(d)) {
 			filename := filepath.Join(dir, d.Name())
-			contents, err := ioutil.ReadFile(filename)
+			contents, err := fs.ReadFile(fsys, filename)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -99,7 +182,14 @@ func parseDir(buildContext build.Context, fset *token.FileSet, dir, relPath stri
 				continue
 			}
 
-			src, err := goparser.ParseFile(fset, filename, contents, mode)
+			// Encore directives and build constraints are comments, so files that
+			// may contain them are always parsed with comments. If comments were
+			// not requested, the non-directive ones are stripped afterwards.
+			fileMode := mode
+			if mode&goparser.ParseComments == 0 && mayContainDirectives(contents) {
+				fileMode |= goparser.ParseComments
+			}
+			src, err := goparser.ParseFile(fset, filename, contents, fileMode)
 			if err != nil || !src.Pos().IsValid() {
 				// Parse error or invalid file
 				if err == nil {
@@ -113,6 +203,11 @@ func parseDir(buildContext build.Context, fset *token.FileSet, dir, relPath stri
 				continue
 			}
 
+			buildTags := fileBuildTags(src)
+			if mode&goparser.ParseComments == 0 && fileMode&goparser.ParseComments != 0 {
+				stripNonDirectiveComments(src)
+			}
+
 			name := src.Name.Name
 			pkg, found := pkgs[name]
 			if !found {
@@ -131,6 +226,7 @@ func parseDir(buildContext build.Context, fset *token.FileSet, dir, relPath stri
 				Contents:   contents,
 				Path:       filename,
 				References: make(map[ast.Node]*est.Node),
+				BuildTags:  buildTags,
 				Pkg:        nil, // will be set later
 			})
 		}
@@ -138,3 +234,123 @@ func parseDir(buildContext build.Context, fset *token.FileSet, dir, relPath stri
 
 	return pkgs, files, errors.Err()
 }
+
+// fileBuildTags returns the build constraints of f, split into the
+// requirements that must all be satisfied: "//go:build linux && (debug || test)"
+// yields ["linux", "debug || test"]. The legacy "// +build" lines are used
+// if there is no //go:build line. It returns nil if f has no build constraints.
+func fileBuildTags(f *ast.File) []string {
+	var plusBuild []constraint.Expr
+	for _, cg := range f.Comments {
+		if cg.Pos() >= f.Package {
+			break
+		}
+		for _, c := range cg.List {
+			switch {
+			case constraint.IsGoBuild(c.Text):
+				if x, err := constraint.Parse(c.Text); err == nil {
+					return splitAndExpr(nil, x)
+				}
+			case constraint.IsPlusBuild(c.Text):
+				if x, err := constraint.Parse(c.Text); err == nil {
+					plusBuild = append(plusBuild, x)
+				}
+			}
+		}
+	}
+	var tags []string
+	for _, x := range plusBuild {
+		tags = splitAndExpr(tags, x)
+	}
+	return tags
+}
+
+// splitAndExpr appends the operands of the top-level conjunction x to tags.
+func splitAndExpr(tags []string, x constraint.Expr) []string {
+	if and, ok := x.(*constraint.AndExpr); ok {
+		return splitAndExpr(splitAndExpr(tags, and.X), and.Y)
+	}
+	return append(tags, x.String())
+}
+
+// mayContainDirectives reports whether the Go source src may contain
+// a comment that is needed even when comments are not requested:
+// a directive or a build constraint. It errs on the side of true.
+func mayContainDirectives(src []byte) bool {
+	if bytes.Contains(src, []byte("encore:")) || bytes.Contains(src, []byte("+build")) {
+		return true
+	}
+	for rest := src; ; {
+		i := bytes.Index(rest, []byte("//"))
+		if i < 0 {
+			return false
+		}
+		rest = rest[i:]
+		line := rest
+		if j := bytes.IndexByte(line, '\n'); j >= 0 {
+			line = line[:j]
+		}
+		if _, _, ok := splitDirective(string(line)); ok {
+			return true
+		}
+		rest = rest[2:]
+	}
+}
+
+// stripNonDirectiveComments removes all comments from f that
+// do not contain directives.
+func stripNonDirectiveComments(f *ast.File) {
+	var kept []*ast.CommentGroup
+	for _, cg := range f.Comments {
+		if isDirectiveComment(cg) {
+			kept = append(kept, cg)
+		}
+	}
+	f.Comments = kept
+	f.Doc = nil
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			if !isDirectiveComment(n.Doc) {
+				n.Doc = nil
+			}
+		case *ast.GenDecl:
+			if !isDirectiveComment(n.Doc) {
+				n.Doc = nil
+			}
+		case *ast.ValueSpec:
+			n.Doc, n.Comment = nil, nil
+		case *ast.TypeSpec:
+			if !isDirectiveComment(n.Doc) {
+				n.Doc = nil
+			}
+			n.Comment = nil
+		case *ast.ImportSpec:
+			n.Doc, n.Comment = nil, nil
+		case *ast.Field:
+			n.Doc, n.Comment = nil, nil
+		}
+		return true
+	})
+}
+
+// isDirectiveComment reports whether cg contains a directive:
+// an Encore directive in either the standard or the legacy syntax,
+// or a directive in another namespace (like //myorg:audit).
+func isDirectiveComment(cg *ast.CommentGroup) bool {
+	if cg == nil {
+		return false
+	}
+	for _, c := range cg.List {
+		if _, _, ok := splitDirective(c.Text); ok {
+			return true
+		}
+	}
+	for _, line := range strings.Split(cg.Text(), "\n") {
+		if strings.HasPrefix(line, "encore:") {
+			return true
+		}
+	}
+	return false
+}
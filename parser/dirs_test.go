@@ -24,21 +24,31 @@ func TestWalkDirs(t *testing.T) {
 		Tree string
 		// Calls are the expected calls to walkFn, in order.
 		Calls []call
+		// Skip is the skipFunc to use, if any.
+		Skip skipFunc
 	}{
-		{"a", []call{{"", ".", []string{"a"}}}},
+		{"a", []call{{"", ".", []string{"a"}}}, nil},
 		{"a/", []call{
 			{"", ".", []string{}},
 			{"a", "a", []string{}},
-		}},
+		}, nil},
 		{"a/b", []call{
 			{"", ".", []string{}},
 			{"a", "a", []string{"b"}},
-		}},
+		}, nil},
 		{"a/b a/c a/d/e", []call{
 			{"", ".", []string{}},
 			{"a", "a", []string{"b", "c"}},
 			{"a/d", "a/d", []string{"e"}},
-		}},
+		}, nil},
+		{".git/a _b/c a/testdata/d vendor/e a/f", []call{
+			{"", ".", []string{}},
+			{"a", "a", []string{"f"}},
+		}, func(name string) bool { return skipDir(name, false) }},
+		{"vendor/e .git/a", []call{
+			{"", ".", []string{}},
+			{"vendor", "vendor", []string{"e"}},
+		}, func(name string) bool { return skipDir(name, true) }},
 	}
 
 	// createTree creates the directory tree represented by tree.
@@ -72,7 +82,7 @@ func TestWalkDirs(t *testing.T) {
 	for _, test := range tests {
 		root := createTree(test.Tree)
 		var calls []call
-		walkDirs(root, func(dir, relPath string, files []os.FileInfo) error {
+		walkDirs(osFS{}, root, test.Skip, func(dir, relPath string, files []os.FileInfo) error {
 			names := make([]string, len(files))
 			for i, f := range files {
 				names[i] = f.Name()
@@ -129,7 +139,7 @@ package fo/;
 
 		fs := token.NewFileSet()
 		context := build.Default
-		pkgs, files, err := parseDir(context, fs, base, ".", nil, goparser.ParseComments)
+		pkgs, files, err := parseDir(osFS{}, context, fs, base, ".", nil, goparser.ParseComments)
 		if test.Err != "" {
 			c.Assert(err, qt.ErrorMatches, test.Err)
 			continue
@@ -143,3 +153,24 @@ package fo/;
 		c.Assert(files, qt.HasLen, test.NFiles)
 	}
 }
+
+func TestMayContainDirectives(t *testing.T) {
+	tests := []struct {
+		Src  string
+		Want bool
+	}{
+		{"package foo\n", false},
+		{"package foo\n\n// Foo does foo.\nfunc Foo() {}\n", false},
+		{"package foo\n\n// See http://example.com.\nvar x = \"a:b\"\n", false},
+		{"package foo\n\n//encore:api public\nfunc Foo() {}\n", true},
+		{"package foo\n\n// encore:api public\nfunc Foo() {}\n", true},
+		{"package foo\n\n//myorg:audit\nfunc Foo() {}\n", true},
+		{"//go:build linux\n\npackage foo\n", true},
+		{"// +build linux\n\npackage foo\n", true},
+	}
+
+	c := qt.New(t)
+	for _, test := range tests {
+		c.Assert(mayContainDirectives([]byte(test.Src)), qt.Equals, test.Want, qt.Commentf("src: %q", test.Src))
+	}
+}
@@ -549,7 +549,11 @@ func describeParam(encodingHints *encodingHints, field *schema.Field) (*Paramete
 			location = tagHint.location
 			usedOverrideTag = tag.Key
 		}
-		if tagHint.location == location {
+		if tagHint.location == location && tag.Name == "" {
+			// The tag only specifies the location (like `encore:"query"`),
+			// so use the default name for it.
+			param.Name = formatName(location, field.Name)
+		} else if tagHint.location == location {
 			if tagHint.nameFormatter != nil {
 				param.Name = tagHint.nameFormatter(tag.Name)
 			} else {
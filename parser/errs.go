@@ -12,6 +12,26 @@ func (p *parser) errf(pos token.Pos, format string, args ...interface{}) {
 	p.errors.Addf(pos, format, args...)
 }
 
+func (p *parser) warn(pos token.Pos, msg string) {
+	p.errors.AddWarning(pos, msg)
+}
+
+func (p *parser) warnf(pos token.Pos, format string, args ...interface{}) {
+	p.errors.AddWarningf(pos, format, args...)
+}
+
 func (p *parser) abort() {
 	p.errors.Abort()
 }
+
+// canceled is the panic value used to stop parsing
+// when the parse's context is canceled.
+type canceled struct{ err error }
+
+// checkCanceled stops parsing if the parse's context has been canceled.
+// The parse then fails with the context's error.
+func (p *parser) checkCanceled() {
+	if err := p.ctx.Err(); err != nil {
+		panic(canceled{err})
+	}
+}
@@ -0,0 +1,170 @@
+package est
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	schema "encr.dev/proto/encore/parser/schema/v1"
+)
+
+// APIHash returns a hash of the application's externally callable API
+// surface: its public and auth APIs with their paths, HTTP methods and
+// request and response schemas, including the types they reference.
+// It is intended for detecting changes to the API contract, such as in CI.
+//
+// The hash is computed over a canonical description of the APIs sorted
+// by service and name, so it does not depend on the order APIs and types
+// are declared in. Private APIs, documentation, source positions and the
+// implementation of the APIs do not affect it.
+func (a *Application) APIHash() string {
+	h := &apiHasher{decls: a.Decls, seen: make(map[uint32]bool)}
+
+	var rpcs []*RPC
+	for _, svc := range a.Services {
+		for _, rpc := range svc.RPCs {
+			if rpc.Access == Public || rpc.Access == Auth {
+				rpcs = append(rpcs, rpc)
+			}
+		}
+	}
+	sort.Slice(rpcs, func(i, j int) bool {
+		if x, y := rpcs[i].Svc.Name, rpcs[j].Svc.Name; x != y {
+			return x < y
+		}
+		return rpcs[i].Name < rpcs[j].Name
+	})
+
+	var endpoints []string
+	for _, rpc := range rpcs {
+		endpoints = append(endpoints, h.endpoint(rpc))
+	}
+
+	// Describe the declarations referenced by the APIs, including those
+	// referenced by other declarations, keyed by package path and name.
+	var types []string
+	for len(h.queue) > 0 {
+		id := h.queue[0]
+		h.queue = h.queue[1:]
+		types = append(types, h.decl(h.decls[id]))
+	}
+	sort.Strings(types)
+
+	sum := sha256.New()
+	for _, s := range endpoints {
+		fmt.Fprintln(sum, s)
+	}
+	for _, s := range types {
+		fmt.Fprintln(sum, s)
+	}
+	return hex.EncodeToString(sum.Sum(nil))
+}
+
+// apiHasher builds the canonical description of an application's APIs.
+type apiHasher struct {
+	decls []*schema.Decl
+	seen  map[uint32]bool // declarations queued so far
+	queue []uint32        // declarations left to describe
+}
+
+// endpoint describes rpc.
+func (h *apiHasher) endpoint(rpc *RPC) string {
+	var b strings.Builder
+	methods := append([]string(nil), rpc.HTTPMethods...)
+	sort.Strings(methods)
+	fmt.Fprintf(&b, "api %s.%s access=%s raw=%t methods=%s path=%s",
+		rpc.Svc.Name, rpc.Name, rpc.Access, rpc.Raw, strings.Join(methods, ","), rpc.Path)
+
+	if rpc.Request != nil {
+		b.WriteString(" request=")
+		h.typ(&b, rpc.Request.Type)
+		for _, f := range rpc.RequestFields {
+			fmt.Fprintf(&b, " request.%s=%s:%s", f.Name, f.Source, f.SourceName)
+		}
+	}
+	if rpc.Response != nil {
+		b.WriteString(" response=")
+		h.typ(&b, rpc.Response.Type)
+		for _, f := range rpc.ResponseFields {
+			fmt.Fprintf(&b, " response.%s=%s:%s", f.Name, f.Source, f.SourceName)
+		}
+	}
+	if rpc.Streaming && rpc.StreamMessage != nil {
+		b.WriteString(" stream=")
+		h.typ(&b, rpc.StreamMessage.Type)
+	}
+	return b.String()
+}
+
+// decl describes the declaration d.
+func (h *apiHasher) decl(d *schema.Decl) string {
+	var b strings.Builder
+	b.WriteString("type ")
+	b.WriteString(declName(d))
+	if len(d.TypeParams) > 0 {
+		params := make([]string, len(d.TypeParams))
+		for i, p := range d.TypeParams {
+			params[i] = p.Name
+		}
+		fmt.Fprintf(&b, "[%s]", strings.Join(params, ","))
+	}
+	b.WriteByte(' ')
+	h.typ(&b, d.Type)
+	if len(d.EnumValues) > 0 {
+		fmt.Fprintf(&b, " enum=%s", strings.Join(d.EnumValues, ","))
+	}
+	return b.String()
+}
+
+// typ writes a description of typ to b, queueing the
+// declarations it references to be described.
+func (h *apiHasher) typ(b *strings.Builder, typ *schema.Type) {
+	switch t := typ.GetTyp().(type) {
+	case *schema.Type_Builtin:
+		b.WriteString(t.Builtin.String())
+	case *schema.Type_Named:
+		id := t.Named.Id
+		if !h.seen[id] {
+			h.seen[id] = true
+			h.queue = append(h.queue, id)
+		}
+		b.WriteString(declName(h.decls[id]))
+		if args := t.Named.TypeArguments; len(args) > 0 {
+			b.WriteByte('[')
+			for i, arg := range args {
+				if i > 0 {
+					b.WriteByte(',')
+				}
+				h.typ(b, arg)
+			}
+			b.WriteByte(']')
+		}
+	case *schema.Type_List:
+		b.WriteString("[]")
+		h.typ(b, t.List.Elem)
+	case *schema.Type_Map:
+		b.WriteString("map[")
+		h.typ(b, t.Map.Key)
+		b.WriteByte(']')
+		h.typ(b, t.Map.Value)
+	case *schema.Type_TypeParameter:
+		b.WriteString(h.decls[t.TypeParameter.DeclId].TypeParams[t.TypeParameter.ParamIdx].Name)
+	case *schema.Type_Struct:
+		b.WriteString("struct{")
+		for _, f := range t.Struct.Fields {
+			fmt.Fprintf(b, "%s json=%q optional=%t query=%q ", f.Name, f.JsonName, f.Optional, f.QueryStringName)
+			h.typ(b, f.Typ)
+			b.WriteByte(';')
+		}
+		b.WriteByte('}')
+	default:
+		fmt.Fprintf(b, "%T", t)
+	}
+}
+
+// declName returns the name of d qualified by its package path.
+func declName(d *schema.Decl) string {
+	return d.Loc.GetPkgPath() + "." + d.Name
+}
@@ -2,17 +2,29 @@
 //
 // It is an Encore-specific syntax tree that represents the higher-level representation
 // of the application that Encore understands.
+//
+// The token.Pos values recorded in the tree are only valid
+// against the FileSet of the parse result that produced it.
 package est
 
 import (
 	"errors"
+	"fmt"
 	"go/ast"
 	"go/token"
+	"sort"
+	"strings"
+	"time"
 
 	"encr.dev/parser/paths"
 	schema "encr.dev/proto/encore/parser/schema/v1"
 )
 
+// Application is the parsed application.
+//
+// Its contents are ordered deterministically: Packages are sorted by RelPath,
+// Services by name, each service's RPCs by name, CronJobs by ID,
+// and each package's Resources by declaration position.
 type Application struct {
 	ModulePath  string
 	Packages    []*Package
@@ -22,6 +34,191 @@ type Application struct {
 	AuthHandler *AuthHandler
 }
 
+// CallGraph returns the service call graph of the application,
+// keyed by the calling service. Each service maps to the calls
+// it makes to APIs in other services.
+func (a *Application) CallGraph() map[*Service][]*RPCCall {
+	graph := make(map[*Service][]*RPCCall, len(a.Services))
+	for _, svc := range a.Services {
+		graph[svc] = svc.Calls
+	}
+	return graph
+}
+
+// ToDOT renders the service call graph of the application as a
+// Graphviz DOT digraph, with a node per service and an edge per API
+// called from another service, labeled with the API's name.
+// Repeated calls to the same API are rendered as a single edge.
+//
+// The output is deterministic: nodes are sorted by service name and
+// edges by caller, callee and API name.
+func (a *Application) ToDOT() string {
+	type edge struct{ from, to, api string }
+	nodes := make(map[string]bool)
+	edges := make(map[edge]bool)
+	for _, svc := range a.Services {
+		nodes[svc.Name] = true
+		for _, call := range svc.Calls {
+			nodes[call.Target.Svc.Name] = true
+			edges[edge{svc.Name, call.Target.Svc.Name, call.Target.Name}] = true
+		}
+	}
+
+	names := make([]string, 0, len(nodes))
+	for name := range nodes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	sorted := make([]edge, 0, len(edges))
+	for e := range edges {
+		sorted = append(sorted, e)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.from != b.from {
+			return a.from < b.from
+		} else if a.to != b.to {
+			return a.to < b.to
+		}
+		return a.api < b.api
+	})
+
+	var b strings.Builder
+	b.WriteString("digraph services {\n")
+	for _, name := range names {
+		fmt.Fprintf(&b, "\t%q;\n", name)
+	}
+	for _, e := range sorted {
+		fmt.Fprintf(&b, "\t%q -> %q [label=%q];\n", e.from, e.to, e.api)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// LibraryPackages returns the packages that are not part of any service
+// and declare no Encore resources or secrets, in the order of a.Packages.
+// Packages within vendor directories and packages consisting only
+// of test files are excluded.
+func (a *Application) LibraryPackages() []*Package {
+	var pkgs []*Package
+	for _, pkg := range a.Packages {
+		switch {
+		case pkg.Service != nil, len(pkg.Resources) > 0, len(pkg.Secrets) > 0:
+			continue
+		case len(pkg.Files) == len(pkg.TestFiles):
+			continue
+		case isVendored(pkg.RelPath):
+			continue
+		}
+		pkgs = append(pkgs, pkg)
+	}
+	return pkgs
+}
+
+// PublicServices returns the services that expose at least one
+// public API and therefore need to be reachable from outside the app,
+// in the order of a.Services.
+func (a *Application) PublicServices() []*Service {
+	var svcs []*Service
+	for _, svc := range a.Services {
+		if svc.HasPublicAPI() {
+			svcs = append(svcs, svc)
+		}
+	}
+	return svcs
+}
+
+// AppSummary summarizes the contents of an application.
+type AppSummary struct {
+	Services int
+
+	// Endpoints is the number of APIs, and PublicEndpoints,
+	// AuthEndpoints and PrivateEndpoints the number of APIs
+	// with each access type. Raw APIs are included.
+	Endpoints        int
+	PublicEndpoints  int
+	AuthEndpoints    int
+	PrivateEndpoints int
+
+	// Databases is the number of distinct databases, either
+	// referenced with sqldb.Named or defined by a service's migrations.
+	Databases int
+
+	CronJobs     int
+	PubSubTopics int
+}
+
+// Summary returns a summary of the contents of the application,
+// computed from its parsed services, cron jobs and resources.
+func (a *Application) Summary() AppSummary {
+	s := AppSummary{
+		Services: len(a.Services),
+		CronJobs: len(a.CronJobs),
+	}
+	for _, svc := range a.Services {
+		for _, rpc := range svc.RPCs {
+			s.Endpoints++
+			switch rpc.Access {
+			case Public:
+				s.PublicEndpoints++
+			case Auth:
+				s.AuthEndpoints++
+			case Private:
+				s.PrivateEndpoints++
+			}
+		}
+	}
+	dbs := make(map[string]bool)
+	for _, svc := range a.Services {
+		if svc.HasMigrations {
+			dbs[svc.Name] = true
+		}
+	}
+	for _, pkg := range a.Packages {
+		for _, res := range pkg.Resources {
+			switch res := res.(type) {
+			case *SQLDB:
+				dbs[res.DBName] = true
+			case *PubSubTopic:
+				s.PubSubTopics++
+			}
+		}
+	}
+	s.Databases = len(dbs)
+	return s
+}
+
+// MatchRoute finds the API that serves requests with the given HTTP method
+// and path (like "/users/123"), returning it along with the values of its path
+// parameters keyed by name. APIs that accept any method match all methods.
+// If several APIs match, the one with the most specific path is chosen,
+// as determined by paths.Path.MoreSpecific.
+func (a *Application) MatchRoute(method, path string) (rpc *RPC, params map[string]string, ok bool) {
+	for _, svc := range a.Services {
+		for _, candidate := range svc.RPCs {
+			if candidate.Path == nil || !candidate.acceptsMethod(method) {
+				continue
+			}
+			p, matched := candidate.Path.Match(path)
+			if matched && (rpc == nil || candidate.Path.MoreSpecific(rpc.Path)) {
+				rpc, params = candidate, p
+			}
+		}
+	}
+	return rpc, params, rpc != nil
+}
+
+// isVendored reports whether the slash-separated relPath
+// is within a vendor directory.
+func isVendored(relPath string) bool {
+	for _, elem := range strings.Split(relPath, "/") {
+		if elem == "vendor" {
+			return true
+		}
+	}
+	return false
+}
+
 type File struct {
 	Name       string   // file name ("foo.go")
 	Pkg        *Package // package it belongs to
@@ -30,6 +227,11 @@ type File struct {
 	Token      *token.File
 	Contents   []byte
 	References map[ast.Node]*Node
+
+	// BuildTags are the file's build constraints, split into
+	// requirements that must all be satisfied (as in "debug" or
+	// "linux || darwin"). It is nil if the file has none.
+	BuildTags []string
 }
 
 type Package struct {
@@ -43,6 +245,30 @@ type Package struct {
 	Service    *Service // the service this package belongs to, if any
 	Secrets    []string
 	Resources  []Resource
+
+	// SecretUsages records where each secret is read,
+	// keyed by secret name.
+	SecretUsages map[string][]token.Pos
+
+	// TypeAliases are the type aliases ("type A = B") declared in the package
+	// that are used in Encore schema definitions, in resolution order.
+	TypeAliases []*TypeAlias
+
+	// TestFiles are the package's test files, including those declaring
+	// the external test package ("package foo_test"). Test files are
+	// only parsed when the parser is configured with ParseTests,
+	// in which case they are included in Files as well.
+	TestFiles []*File
+}
+
+// A TypeAlias is a type alias declaration ("type A = B").
+// Aliases are schema declarations like other named types,
+// so references to them keep the alias name.
+type TypeAlias struct {
+	Name string
+	Doc  string
+	Spec *ast.TypeSpec
+	Decl *schema.Decl // Decl.Type is the type the alias denotes
 }
 
 // A Service is a Go package that defines one or more RPCs.
@@ -53,6 +279,160 @@ type Service struct {
 	Root *Package
 	Pkgs []*Package
 	RPCs []*RPC
+
+	// Calls are the calls made from this service
+	// to APIs defined in other services.
+	Calls []*RPCCall
+
+	// Struct is the service struct declared with //encore:service,
+	// or nil if the service doesn't define one.
+	Struct *ServiceStruct
+
+	// PathPrefix is the prefix prepended to the explicitly declared
+	// paths of the service's APIs, or nil if the service has none.
+	// It is declared with //encore:service path=/prefix.
+	PathPrefix *paths.Path
+
+	// CORS is the CORS configuration of the service's APIs, or nil
+	// if the service has none. It is declared with //encore:service cors=...
+	CORS *CORS
+
+	// DefaultAccess is the access type of the service's APIs that don't
+	// declare one, or "" if the service has none. It is declared with
+	// //encore:service defaultAccess=private.
+	DefaultAccess AccessType
+
+	// Config is the service's configuration loaded with config.Load,
+	// or nil if the service doesn't load any configuration.
+	Config *ServiceConfig
+
+	// Secrets are the names of the secrets declared by the
+	// service's packages with a secrets struct, sorted by name.
+	Secrets []string
+
+	// HasMigrations reports whether the service's migrations directory
+	// contains up migrations, in which case the service has a database
+	// with the same name as the service even if it is not declared
+	// with sqldb.Named.
+	HasMigrations bool
+
+	// InitOrder is the order in which the service's resources and
+	// service struct must be initialized, such that each comes after
+	// the resources it depends on.
+	InitOrder []InitStep
+}
+
+// CORS is the CORS configuration of a service.
+type CORS struct {
+	// AllowOrigins are the origins allowed to make cross-origin requests,
+	// as in "https://example.com". The origin "*" allows any origin.
+	AllowOrigins []string
+
+	// AllowMethods are the HTTP methods allowed in cross-origin requests,
+	// and AllowHeaders the request headers. If empty, the defaults are used.
+	AllowMethods []string
+	AllowHeaders []string
+}
+
+// An InitStep is a step in a service's initialization order.
+// Exactly one of Resource and Struct is set.
+type InitStep struct {
+	Resource Resource
+	Struct   *ServiceStruct
+}
+
+// Name returns the name of the resource or service struct initialized.
+func (s InitStep) Name() string {
+	if s.Struct != nil {
+		return s.Struct.Name
+	}
+	return s.Resource.Ident().Name
+}
+
+// A ServiceConfig is the configuration of a service,
+// loaded with a call to config.Load[T]().
+type ServiceConfig struct {
+	Name string        // name of the config struct type
+	Type *schema.Type  // the config struct type; always a named type
+	File *File         // file containing the call
+	Call *ast.CallExpr // the config.Load call
+}
+
+// A ServiceStruct is a struct type declared with the //encore:service
+// directive. It holds the dependencies of a service and is initialized once.
+// APIs can be declared as methods on it.
+type ServiceStruct struct {
+	Svc  *Service
+	Name string
+	Doc  string
+	Decl *ast.TypeSpec
+	File *File
+
+	// Init is the function initializing the struct,
+	// named "initName" for a struct type named "Name".
+	// It is nil if no such function is declared.
+	Init *ast.FuncDecl
+
+	// Deps are the resources referenced by Init,
+	// in the order they are first referenced.
+	Deps []Resource
+}
+
+// RawRPCs returns the raw endpoints defined by the service.
+func (s *Service) RawRPCs() []*RPC {
+	var rpcs []*RPC
+	for _, rpc := range s.RPCs {
+		if rpc.Raw {
+			rpcs = append(rpcs, rpc)
+		}
+	}
+	return rpcs
+}
+
+// PublicRPCs returns the endpoints defined by the service
+// with public access, in the order of s.RPCs.
+func (s *Service) PublicRPCs() []*RPC {
+	var rpcs []*RPC
+	for _, rpc := range s.RPCs {
+		if rpc.Access == Public {
+			rpcs = append(rpcs, rpc)
+		}
+	}
+	return rpcs
+}
+
+// HasPublicAPI reports whether the service defines any endpoint
+// with public access.
+func (s *Service) HasPublicAPI() bool {
+	for _, rpc := range s.RPCs {
+		if rpc.Access == Public {
+			return true
+		}
+	}
+	return false
+}
+
+// ResolveAccess returns the effective access type of an API
+// in the service declaring the access type declared, which is ""
+// if the API does not declare one. Such APIs have the service's
+// default access, or private access if the service has none.
+func (s *Service) ResolveAccess(declared AccessType) AccessType {
+	switch {
+	case declared != "":
+		return declared
+	case s.DefaultAccess != "":
+		return s.DefaultAccess
+	default:
+		return Private
+	}
+}
+
+// An RPCCall is a call from one service to an API in another service.
+type RPCCall struct {
+	Caller *Service
+	Target *RPC
+	File   *File         // file containing the call
+	Call   *ast.CallExpr // the call expression
 }
 
 type CronJob struct {
@@ -60,8 +440,9 @@ type CronJob struct {
 	Title    string
 	Doc      string
 	Schedule string
-	RPC      *RPC
+	Endpoint *RPC // the API the job calls
 	AST      *ast.ValueSpec
+	Pos      token.Pos // position of the cron.NewJob call
 }
 
 func (cj *CronJob) IsValid() (bool, error) {
@@ -70,8 +451,8 @@ func (cj *CronJob) IsValid() (bool, error) {
 		return false, errors.New("field ID is required")
 	case cj.Title == "":
 		return false, errors.New("field Title is required")
-	case cj.RPC == nil:
-		return false, errors.New("field RPC is required")
+	case cj.Endpoint == nil:
+		return false, errors.New("field Endpoint is required")
 	case cj.Schedule == "":
 		return false, errors.New("field Schedule is required")
 	}
@@ -80,8 +461,14 @@ func (cj *CronJob) IsValid() (bool, error) {
 }
 
 type Param struct {
-	IsPtr bool
+	IsPtr bool // passed by pointer, as in *T
 	Type  *schema.Type
+	Pos   token.Pos // position of the parameter's type expression
+
+	// Struct is the struct type of the parameter. For instantiated
+	// generic types (like Paginated[User]) the type arguments are
+	// substituted for the type parameters of the declaration.
+	Struct *schema.Struct
 }
 
 type AccessType string
@@ -96,15 +483,134 @@ const (
 type RPC struct {
 	Svc         *Service
 	Name        string
-	Doc         string
+	Doc         string // doc comment of the handler without the directive; empty unless comments are parsed
 	Func        *ast.FuncDecl
 	File        *File
-	Access      AccessType
+	Access      AccessType // effective access type; see DeclaredAccess
 	Raw         bool
 	Path        *paths.Path
 	HTTPMethods []string
-	Request     *Param // request data; nil for Raw RPCs
-	Response    *Param // response data; nil for Raw RPCs
+	Request     *Param    // request data; nil for Raw RPCs
+	Response    *Param    // response data; nil for Raw RPCs
+	Transforms  []string  // request/response transformation steps, in order
+	Pos         token.Pos // position of the API's name in its declaration
+
+	// RequestFields describes where each field of the request data
+	// is decoded from, in declaration order. It is nil if Request is nil.
+	RequestFields []*RequestField
+
+	// ResponseFields describes where each field of the response data
+	// is encoded to, in declaration order. It is nil if Response is nil.
+	ResponseFields []*ResponseField
+
+	// SvcStruct is the service struct the API is a method on,
+	// or nil if the API is a package-level function.
+	SvcStruct *ServiceStruct
+
+	// Streaming is true for APIs declared with the stream option,
+	// which send their responses as messages on an api.Stream parameter.
+	// StreamMessage is the message type of the stream, and is nil otherwise.
+	Streaming     bool
+	StreamMessage *Param
+
+	// Deprecated is true if the API is marked as deprecated.
+	// DeprecationMessage optionally describes what to use instead.
+	Deprecated         bool
+	DeprecationMessage string
+
+	// MaxBodySize is the max size of the request body of a raw API, in bytes.
+	// It is zero if the API does not declare one, in which case the default is used.
+	MaxBodySize int64
+
+	// SuccessStatus is the HTTP status code the API responds with on success.
+	// It is always a 2xx code, and is 200 unless the API declares otherwise.
+	SuccessStatus int
+
+	// Tags are the tags the API is grouped under for documentation.
+	Tags []string
+
+	// BuildTags are the build constraints of the file the API is declared in,
+	// which a build must satisfy for the API to exist. See File.BuildTags.
+	BuildTags []string
+
+	// RateLimit is the rate limit the gateway enforces for the API,
+	// or nil if the API does not declare one.
+	RateLimit *RateLimit
+
+	// DeclaredAccess is the access type declared by the API's directive,
+	// or "" if it inherits the access type of its service.
+	// Access is the effective access type; see Service.ResolveAccess.
+	DeclaredAccess AccessType
+}
+
+// RateLimit is a limit on the number of requests to an API in a time window,
+// declared like rateLimit=100/minute.
+type RateLimit struct {
+	Requests int           // max number of requests per window; always positive
+	Window   time.Duration // the window: a second, a minute or an hour
+}
+
+// acceptsMethod reports whether the API accepts requests with the given HTTP method.
+func (r *RPC) acceptsMethod(method string) bool {
+	for _, m := range r.HTTPMethods {
+		if m == "*" || m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// ParamSource describes the part of an HTTP request a field of an API's
+// request data is decoded from, or the part of an HTTP response a field
+// of its response data is encoded to.
+type ParamSource string
+
+const (
+	// BodySource is the default source. Fields are decoded from the JSON body,
+	// or from the query string for HTTP methods without a body.
+	BodySource   ParamSource = "body"
+	HeaderSource ParamSource = "header" // declared with `header:"Name"` or `encore:"header:Name"`
+	QuerySource  ParamSource = "query"  // declared with `query:"name"` or `encore:"query:name"`
+)
+
+// RequestField describes where a field of an API's request data is decoded from.
+type RequestField struct {
+	Name       string // Go field name
+	Source     ParamSource
+	SourceName string // header or query string parameter name; empty for BodySource
+}
+
+// ResponseField describes where a field of an API's response data is encoded to.
+// Responses have no query string, so Source is either BodySource or HeaderSource.
+type ResponseField struct {
+	Name       string // Go field name
+	Source     ParamSource
+	SourceName string // header name; empty for BodySource
+}
+
+// transforms are the request/response transformation
+// steps an API can declare, keyed by name.
+var transforms = map[string]string{
+	"trim":       "trim leading and trailing whitespace from request strings",
+	"snake_case": "rename response fields to snake_case",
+	"camel_case": "rename response fields to camelCase",
+	"mask":       "mask sensitive response fields",
+}
+
+// IsKnownTransform reports whether name is a supported transformation step.
+func IsKnownTransform(name string) bool {
+	_, ok := transforms[name]
+	return ok
+}
+
+// KnownTransforms returns the names of the supported transformation steps, sorted.
+func KnownTransforms() []string {
+	names := make([]string, 0, len(transforms))
+	for name := range transforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
 }
 
 type NodeType int
@@ -115,6 +621,8 @@ const (
 	SQLDBNode
 	RLogNode
 	SecretsNode
+	PubSubNode
+	CustomResourceNode
 )
 
 type Node struct {
@@ -149,6 +657,14 @@ type Resource interface {
 	Type() ResourceType
 	File() *File
 	Ident() *ast.Ident
+	Pos() token.Pos // where the resource is defined
+}
+
+// DependentResource is implemented by resources that require
+// other resources to be provisioned before them.
+type DependentResource interface {
+	Resource
+	Dependencies() []Resource
 }
 
 //go:generate stringer -type=ResourceType
@@ -157,14 +673,73 @@ type ResourceType int
 
 const (
 	SQLDBResource ResourceType = iota + 1
+	PubSubTopicResource
+	PubSubSubscriptionResource
+
+	// CustomResource is the type of resources recognized
+	// by the resource detectors configured for the parser.
+	CustomResource
 )
 
 type SQLDB struct {
 	DeclFile *File
 	DeclName *ast.Ident // where the resource is declared
 	DBName   string
+	CallPos  token.Pos // position of the sqldb.Named call
+
+	// Migrations are the database's up migrations, sorted by number.
+	// They are defined in the migrations directory of the service
+	// the database belongs to.
+	Migrations []MigrationFile
+}
+
+// A MigrationFile is a database migration file.
+type MigrationFile struct {
+	Number      int
+	Filename    string // file name ("1_create_table.up.sql")
+	Description string // description from the file name ("create table")
 }
 
 func (r *SQLDB) Type() ResourceType { return SQLDBResource }
 func (r *SQLDB) File() *File        { return r.DeclFile }
 func (r *SQLDB) Ident() *ast.Ident  { return r.DeclName }
+func (r *SQLDB) Pos() token.Pos     { return r.CallPos }
+
+// A PubSubTopic is a topic declared with pubsub.NewTopic.
+type PubSubTopic struct {
+	DeclFile          *File
+	DeclName          *ast.Ident // where the resource is declared
+	Name              string     // topic name
+	MessageType       *schema.Type
+	DeliveryGuarantee string    // "at-least-once" or "exactly-once"
+	CallPos           token.Pos // position of the pubsub.NewTopic call
+
+	// Subscriptions are the subscriptions to the topic.
+	Subscriptions []*PubSubSubscription
+}
+
+func (r *PubSubTopic) Type() ResourceType { return PubSubTopicResource }
+func (r *PubSubTopic) File() *File        { return r.DeclFile }
+func (r *PubSubTopic) Ident() *ast.Ident  { return r.DeclName }
+func (r *PubSubTopic) Pos() token.Pos     { return r.CallPos }
+
+// A PubSubSubscription is a subscription to a topic
+// declared with pubsub.NewSubscription.
+type PubSubSubscription struct {
+	DeclFile *File
+	DeclName *ast.Ident // where the resource is declared
+	Name     string     // subscription name
+	Topic    *PubSubTopic
+	Handler  *ast.FuncDecl // the function handling messages
+	CallPos  token.Pos     // position of the pubsub.NewSubscription call
+
+	// TopicExpr is the expression referencing the topic
+	// in the pubsub.NewSubscription call.
+	TopicExpr ast.Expr
+}
+
+func (r *PubSubSubscription) Type() ResourceType       { return PubSubSubscriptionResource }
+func (r *PubSubSubscription) File() *File              { return r.DeclFile }
+func (r *PubSubSubscription) Ident() *ast.Ident        { return r.DeclName }
+func (r *PubSubSubscription) Pos() token.Pos           { return r.CallPos }
+func (r *PubSubSubscription) Dependencies() []Resource { return []Resource{r.Topic} }
@@ -1,15 +1,16 @@
 package parser
 
 import (
+	"errors"
 	"fmt"
 	"go/ast"
-	"io/ioutil"
-	"os"
+	"io/fs"
 	"path"
 	"path/filepath"
 	"regexp"
 	"sort"
 	"strconv"
+	"time"
 
 	"encr.dev/parser/est"
 	"encr.dev/parser/paths"
@@ -21,6 +22,11 @@ type TraceNodes map[ast.Node]*meta.TraceNode
 
 // ParseMeta parses app metadata.
 func ParseMeta(appRevision string, appHasUncommittedChanges bool, appRoot string, app *est.Application) (*meta.Data, map[*est.Package]TraceNodes, error) {
+	return parseMeta(osFS{}, appRevision, appHasUncommittedChanges, appRoot, app)
+}
+
+// parseMeta is like ParseMeta but reads the migrations from fsys.
+func parseMeta(fsys fs.FS, appRevision string, appHasUncommittedChanges bool, appRoot string, app *est.Application) (*meta.Data, map[*est.Package]TraceNodes, error) {
 	data := &meta.Data{
 		ModulePath:         app.ModulePath,
 		AppRevision:        appRevision,
@@ -49,7 +55,7 @@ func ParseMeta(appRevision string, appHasUncommittedChanges bool, appRoot string
 	}
 
 	for _, svc := range app.Services {
-		s, err := parseSvc(appRoot, svc)
+		s, err := parseSvc(fsys, appRoot, svc)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -88,6 +94,17 @@ func ParseMeta(appRevision string, appHasUncommittedChanges bool, appRoot string
 		data.CronJobs = append(data.CronJobs, cj)
 	}
 
+	for _, pkg := range app.Packages {
+		for _, res := range pkg.Resources {
+			if topic, ok := res.(*est.PubSubTopic); ok {
+				data.PubsubTopics = append(data.PubsubTopics, parsePubSubTopic(topic))
+			}
+		}
+	}
+	sort.Slice(data.PubsubTopics, func(i, j int) bool {
+		return data.PubsubTopics[i].Name < data.PubsubTopics[j].Name
+	})
+
 	if app.AuthHandler != nil {
 		data.AuthHandler = parseAuthHandler(app.AuthHandler)
 	}
@@ -95,12 +112,23 @@ func ParseMeta(appRevision string, appHasUncommittedChanges bool, appRoot string
 	return data, nodes, nil
 }
 
-var migrationRe = regexp.MustCompile(`^(\d+)_([^.]+)\.up.sql$`)
+var migrationRe = regexp.MustCompile(`^(\d+)_([^.]+)\.(up|down)\.sql$`)
 
-func parseSvc(appRoot string, svc *est.Service) (*meta.Service, error) {
+func parseSvc(fsys fs.FS, appRoot string, svc *est.Service) (*meta.Service, error) {
 	s := &meta.Service{
 		Name:    svc.Name,
 		RelPath: svc.Root.RelPath,
+		Secrets: svc.Secrets,
+	}
+	if svc.Config != nil {
+		s.ConfigType = svc.Config.Name
+	}
+	if svc.CORS != nil {
+		s.Cors = &meta.CORS{
+			AllowOrigins: svc.CORS.AllowOrigins,
+			AllowMethods: svc.CORS.AllowMethods,
+			AllowHeaders: svc.CORS.AllowHeaders,
+		}
 	}
 	for _, rpc := range svc.RPCs {
 		r, err := parseRPC(rpc)
@@ -111,7 +139,7 @@ func parseSvc(appRoot string, svc *est.Service) (*meta.Service, error) {
 	}
 
 	relPath := filepath.Join(svc.Root.RelPath, "migrations")
-	migs, err := parseMigrations(appRoot, relPath)
+	migs, err := parseMigrations(fsys, appRoot, relPath)
 	if err != nil {
 		return nil, fmt.Errorf("%s: could not parse sqldb migrations: %v", svc.Root.RelPath, err)
 	}
@@ -157,13 +185,16 @@ func parseRPC(rpc *est.RPC) (*meta.RPC, error) {
 		return nil, fmt.Errorf("unhandled access type %v", rpc.Access)
 	}
 
-	var req, resp *schema.Type
+	var req, resp, streamMsg *schema.Type
 	if rpc.Request != nil {
 		req = rpc.Request.Type
 	}
 	if rpc.Response != nil {
 		resp = rpc.Response.Type
 	}
+	if rpc.StreamMessage != nil {
+		streamMsg = rpc.StreamMessage.Type
+	}
 	r := &meta.RPC{
 		Name:           rpc.Name,
 		ServiceName:    rpc.Svc.Name,
@@ -175,6 +206,19 @@ func parseRPC(rpc *est.RPC) (*meta.RPC, error) {
 		Loc:            parseLoc(rpc.File, rpc.Func),
 		Path:           parsePath(rpc.Path),
 		HttpMethods:    rpc.HTTPMethods,
+		Transforms:     rpc.Transforms,
+
+		Streaming:           rpc.Streaming,
+		StreamMessageSchema: streamMsg,
+		MaxBodySize:         rpc.MaxBodySize,
+		Tags:                rpc.Tags,
+		BuildTags:           rpc.BuildTags,
+	}
+	if rl := rpc.RateLimit; rl != nil {
+		r.RateLimit = &meta.RateLimit{
+			Requests:      int64(rl.Requests),
+			WindowSeconds: int64(rl.Window / time.Second),
+		}
 	}
 	return r, nil
 }
@@ -186,17 +230,45 @@ func parseCronJob(job *est.CronJob) (*meta.CronJob, error) {
 		Doc:      job.Doc,
 		Schedule: job.Schedule,
 		Endpoint: &meta.QualifiedName{
-			Name: job.RPC.Name,
-			Pkg:  job.RPC.Svc.Root.RelPath,
+			Name: job.Endpoint.Name,
+			Pkg:  job.Endpoint.Svc.Root.RelPath,
 		},
 	}
 	return j, nil
 }
 
-func parseMigrations(appRoot, relPath string) ([]*meta.DBMigration, error) {
+func parsePubSubTopic(topic *est.PubSubTopic) *meta.PubSubTopic {
+	t := &meta.PubSubTopic{
+		Name:        topic.Name,
+		MessageType: topic.MessageType,
+	}
+	if topic.DeliveryGuarantee == "exactly-once" {
+		t.DeliveryGuarantee = meta.PubSubTopic_EXACTLY_ONCE
+	}
+	for _, sub := range topic.Subscriptions {
+		pkg := sub.File().Pkg
+		s := &meta.PubSubSubscription{
+			Name: sub.Name,
+			Handler: &meta.QualifiedName{
+				Pkg:  pkg.RelPath,
+				Name: sub.Handler.Name.Name,
+			},
+		}
+		if pkg.Service != nil {
+			s.ServiceName = pkg.Service.Name
+		}
+		t.Subscriptions = append(t.Subscriptions, s)
+	}
+	sort.Slice(t.Subscriptions, func(i, j int) bool {
+		return t.Subscriptions[i].Name < t.Subscriptions[j].Name
+	})
+	return t
+}
+
+func parseMigrations(fsys fs.FS, appRoot, relPath string) ([]*meta.DBMigration, error) {
 	absPath := filepath.Join(appRoot, relPath)
-	fi, err := os.Stat(absPath)
-	if os.IsNotExist(err) {
+	fi, err := fs.Stat(fsys, absPath)
+	if errors.Is(err, fs.ErrNotExist) {
 		return nil, nil
 	} else if err != nil {
 		return nil, err
@@ -204,7 +276,7 @@ func parseMigrations(appRoot, relPath string) ([]*meta.DBMigration, error) {
 		return nil, fmt.Errorf("%s is not a directory", relPath)
 	}
 
-	files, err := ioutil.ReadDir(absPath)
+	files, err := fs.ReadDir(fsys, absPath)
 	if err != nil {
 		return nil, fmt.Errorf("could not read migrations: %v", err)
 	}
@@ -218,6 +290,9 @@ func parseMigrations(appRoot, relPath string) ([]*meta.DBMigration, error) {
 			return nil, fmt.Errorf("migration %s/%s has an invalid name (must be of the format '123_description_here.up.sql')",
 				relPath, f.Name())
 		}
+		if match[3] != "up" {
+			continue
+		}
 		num, _ := strconv.Atoi(match[1])
 		migrations = append(migrations, &meta.DBMigration{
 			Filename:    f.Name(),
@@ -266,8 +341,8 @@ func parceTraceNodes(app *est.Application) map[*est.Package]TraceNodes {
 		for _, file := range pkg.Files {
 			for _, r := range sortedRefs(file.References) {
 				switch r.Node.Type {
-				// Secret nodes are not relevant for tracing
-				case est.SecretsNode:
+				// Secret and pubsub nodes are not relevant for tracing
+				case est.SecretsNode, est.PubSubNode:
 					continue
 				}
 
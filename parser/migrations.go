@@ -0,0 +1,161 @@
+package parser
+
+import (
+	"errors"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"encr.dev/parser/est"
+)
+
+// validateMigrations ensures that the databases declared with sqldb.Named
+// have a migrations directory with properly numbered migration files.
+// A database is defined by the service with the same name, so the migrations
+// are looked up in that service's directory. Databases not matching any service
+// are looked up in the directory of the service declaring them, or of the
+// declaring package if it is not part of a service.
+//
+// Errors are reported at the first sqldb.Named call referencing the database.
+func (p *parser) validateMigrations() {
+	for _, svc := range p.svcs {
+		svc.HasMigrations = p.hasUpMigrations(svc.Root.Dir)
+	}
+
+	migrations := make(map[string][]est.MigrationFile) // dir -> migrations
+	for _, pkg := range p.pkgs {
+		for _, res := range pkg.Resources {
+			db, ok := res.(*est.SQLDB)
+			if !ok {
+				continue
+			}
+			svc := p.svcMap[db.DBName]
+			if svc == nil {
+				svc = pkg.Service
+			}
+			if svc == nil {
+				continue // reported by validateApp
+			}
+			owner := svc.Root
+			if migs, seen := migrations[owner.Dir]; seen {
+				db.Migrations = migs
+				continue
+			}
+			migrations[owner.Dir] = nil
+
+			relDir := path.Join(owner.RelPath, "migrations")
+			fi, err := fs.Stat(p.fsys, path.Join(owner.Dir, "migrations"))
+			if errors.Is(err, fs.ErrNotExist) || (err == nil && !fi.IsDir()) {
+				p.errf(db.Pos(), "database %s requires a migrations directory: %s does not exist", db.DBName, relDir)
+				continue
+			} else if err != nil {
+				p.errf(db.Pos(), "could not read migrations directory %s: %v", relDir, err)
+				continue
+			}
+			db.Migrations = p.scanMigrations(db.Pos(), owner.Dir, relDir)
+			migrations[owner.Dir] = db.Migrations
+		}
+	}
+}
+
+// hasUpMigrations reports whether the migrations directory of dir
+// contains up migrations. Invalid migrations are reported elsewhere.
+func (p *parser) hasUpMigrations(dir string) bool {
+	entries, err := fs.ReadDir(p.fsys, path.Join(dir, "migrations"))
+	if err != nil {
+		return false
+	}
+	for _, e := range entries {
+		if match := migrationRe.FindStringSubmatch(e.Name()); match != nil && !e.IsDir() && match[3] == "up" {
+			return true
+		}
+	}
+	return false
+}
+
+// validateMigrationDirs warns about migrations directories that are
+// not in a service's root directory, such as in a directory with no Go files.
+// Their migrations are never applied since databases are defined by services.
+func (p *parser) validateMigrationDirs() {
+	svcDirs := make(map[string]bool)
+	for _, svc := range p.svcs {
+		svcDirs[filepath.Clean(svc.Root.Dir)] = true
+	}
+	skip := func(name string) bool { return skipDir(name, p.cfg.IncludeVendor) }
+	// The directories have already been walked successfully
+	// when collecting packages, so errors can be ignored.
+	_ = walkDirs(p.fsys, p.cfg.AppRoot, skip, func(dir, relPath string, files []os.FileInfo) error {
+		if path.Base(relPath) != "migrations" || svcDirs[filepath.Dir(dir)] {
+			return nil
+		}
+		for _, f := range files {
+			if strings.HasSuffix(f.Name(), ".sql") {
+				parent := path.Dir(relPath)
+				if parent == "." {
+					parent = "the app root"
+				}
+				p.warnf(token.NoPos, "%s contains database migrations, but %s is not a service, so they are never applied: "+
+					"migrations must be in the directory of the service defining the database", relPath, parent)
+				break
+			}
+		}
+		return nil
+	})
+}
+
+// scanMigrations reads and validates the migrations in the migrations
+// directory of dir. Up migrations must be numbered sequentially starting at 1.
+// Problems are reported as errors at pos.
+//
+// It returns the up migrations, sorted by number.
+func (p *parser) scanMigrations(pos token.Pos, dir, relDir string) []est.MigrationFile {
+	entries, err := fs.ReadDir(p.fsys, path.Join(dir, "migrations"))
+	if err != nil {
+		p.errf(pos, "could not read migrations directory %s: %v", relDir, err)
+		return nil
+	}
+
+	var migrations []est.MigrationFile
+	for _, e := range entries {
+		if e.IsDir() {
+			continue
+		}
+		match := migrationRe.FindStringSubmatch(e.Name())
+		if match == nil {
+			p.errf(pos, "migration %s/%s has an invalid name (must be of the format '123_description_here.up.sql')",
+				relDir, e.Name())
+			continue
+		} else if match[3] != "up" {
+			continue
+		}
+		num, _ := strconv.Atoi(match[1])
+		migrations = append(migrations, est.MigrationFile{
+			Number:      num,
+			Filename:    e.Name(),
+			Description: strings.ReplaceAll(match[2], "_", " "),
+		})
+	}
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].Number < migrations[j].Number
+	})
+
+	for i, m := range migrations {
+		switch {
+		case m.Number <= 0:
+			p.errf(pos, "%s/%s: invalid migration number %d", relDir, m.Filename, m.Number)
+		case i > 0 && m.Number == migrations[i-1].Number:
+			p.errf(pos, "%s/%s: duplicate migration with number %d (also defined by %s)",
+				relDir, m.Filename, m.Number, migrations[i-1].Filename)
+		case i == 0 && m.Number > 1:
+			p.errf(pos, "%s/%s: missing migration with number %d", relDir, m.Filename, 1)
+		case i > 0 && m.Number > migrations[i-1].Number+1:
+			p.errf(pos, "%s/%s: missing migration with number %d", relDir, m.Filename, migrations[i-1].Number+1)
+		}
+	}
+	return migrations
+}
@@ -2,6 +2,8 @@
 package parser
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"go/ast"
 	"go/build"
@@ -9,6 +11,9 @@ import (
 	goparser "go/parser"
 	"go/scanner"
 	"go/token"
+	"go/types"
+	"io"
+	"io/fs"
 	"math/big"
 	"os"
 	"path"
@@ -17,8 +22,12 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
+	"unicode"
 
 	cronparser "github.com/robfig/cron/v3"
+	"golang.org/x/mod/modfile"
 	"golang.org/x/tools/go/ast/astutil"
 
 	"encr.dev/parser/dnsname"
@@ -32,15 +41,28 @@ import (
 )
 
 type Result struct {
+	// FileSet is the file set used while parsing the application.
+	// The positions recorded in the est types are only valid against it.
+	// It is not modified after Parse returns; Reparse parses into a copy.
 	FileSet *token.FileSet
-	App     *est.Application
-	Meta    *meta.Data
-	Nodes   map[*est.Package]TraceNodes
+
+	App   *est.Application
+	Meta  *meta.Data
+	Nodes map[*est.Package]TraceNodes
+
+	// Warnings are non-fatal issues found while parsing.
+	// They are reported even if parsing succeeded.
+	Warnings scanner.ErrorList
+
+	// cfg is the configuration the application was parsed with,
+	// used by Reparse to determine whether the parsed files can be reused.
+	cfg *Config
 }
 
 type parser struct {
 	// inputs
 	cfg *Config
+	ctx context.Context // canceling it stops the parse
 
 	// accumulated results
 	fset        *token.FileSet
@@ -55,11 +77,24 @@ type parser struct {
 	authHandler *est.AuthHandler
 	declMap     map[string]*schema.Decl // pkg/path.Name -> decl
 	decls       []*schema.Decl
-	paths       paths.Set // RPC paths
+	aliases     map[uint32]*est.TypeAlias // decl id -> alias
+	paths       paths.Set                 // RPC paths
 
 	// validRPCReferences is a set of ast nodes that are allowed to
 	// reference RPCs without calling them.
 	validRPCReferences map[ast.Node]bool
+
+	// workspace are the modules listed in the app's go.work file,
+	// or nil if the app is not a workspace.
+	workspace []workspaceModule
+
+	// fsys is the filesystem the app is read from.
+	fsys fs.FS
+
+	// reuse are the packages of a previous parse whose files
+	// can be reused rather than parsed again, keyed by directory.
+	// It is nil unless reparsing; see Reparse.
+	reuse map[string]*est.Package
 }
 
 // Config represents the configuration options for parsing.
@@ -68,75 +103,359 @@ type Config struct {
 	AppRoot                  string
 	AppRevision              string
 	AppHasUncommittedChanges bool
-	ModulePath               string
 	WorkingDir               string
-	ParseTests               bool
+
+	// ParseTests controls whether test files are parsed.
+	// If true they are included in the packages they belong to
+	// and recorded on est.Package.TestFiles.
+	ParseTests bool
+
+	// ModulePath is the Go module path of the app.
+	// If empty it is read from the go.mod file in AppRoot.
+	//
+	// If AppRoot contains a go.work file, the packages of all the modules
+	// it uses are parsed as one app, each with its own module path.
+	// ModulePath then only applies to the module in AppRoot, if it is used,
+	// and defaults to the path of that module or else the first one listed.
+	ModulePath string
+
+	// SkipDocComments causes documentation comments to be discarded,
+	// for callers that have no use for documentation. API docs are then
+	// left empty and undocumented APIs are not reported.
+	//
+	// Note that Encore directives (such as //encore:api) and build constraints
+	// are themselves comments, so they are never discarded. Files that may
+	// contain them are still parsed with comments, after which the comments
+	// that do not contain directives are discarded; only the other files are
+	// parsed without comments.
+	SkipDocComments bool
+
+	// IncludeVendor controls whether packages in vendor directories are parsed.
+	// By default they are skipped, like directories beginning with "." or "_"
+	// and testdata directories, which are always skipped.
+	IncludeVendor bool
+
+	// BuildTags are additional build tags to consider satisfied when
+	// evaluating the build constraints of source files, like the -tags
+	// flag of the go tool. Files whose constraints are not satisfied are
+	// excluded, along with the endpoints and resources they declare.
+	BuildTags []string
+
+	// DirectivePrefixes are the directive namespaces the parser recognizes,
+	// as in "//encore:api". It defaults to []string{"encore"}; Encore's own
+	// directives are always recognized.
+	//
+	// Directives in other recognized namespaces are passed to the handler
+	// registered for the namespace in DirectiveHandlers, and are reported
+	// as errors if there is none. Directives in namespaces that are not
+	// recognized (like //go:generate) are ignored.
+	DirectivePrefixes []string
+
+	// DirectiveHandlers are the handlers for directives in namespaces
+	// other than "encore", keyed by namespace.
+	DirectiveHandlers map[string]DirectiveHandler
+
+	// MaxErrors is the number of errors after which parsing is aborted.
+	// The returned error list then ends with a "too many errors" marker.
+	// If zero there is no limit.
+	MaxErrors int
+
+	// ResourceDetectors recognize custom infrastructure resources,
+	// in addition to the resources provided by Encore.
+	ResourceDetectors []ResourceDetector
+
+	// FS is the filesystem to read the app from, such as an in-memory
+	// filesystem holding unsaved editor buffers. If set, AppRoot is a path
+	// within FS (like "." for its root) and file names are reported
+	// as paths within FS. If nil the app is read from disk.
+	FS fs.FS
+
+	// WarningsAsErrors causes warnings to also be reported as errors,
+	// failing the parse. The returned *errlist.List still reports
+	// the warnings by themselves through its Warnings method.
+	WarningsAsErrors bool
+
+	// Progress, if non-nil, is called as parsing progresses through its
+	// major stages: "collecting packages", "resolving names" and
+	// "validating services", in that order. Within a stage done never
+	// decreases, and the last call has done == total. The total is zero
+	// while it is not yet known.
+	//
+	// It is called synchronously, so it should return quickly.
+	Progress func(stage string, done, total int)
+
+	// MinCronInterval is the shortest interval between two runs of a cron
+	// job that the platform supports. Cron jobs that may run more often are
+	// reported as errors. If zero it defaults to DefaultMinCronInterval.
+	// Cron jobs can never run more often than every minute, so it can
+	// only be used to require longer intervals: shorter ones have no effect.
+	MinCronInterval time.Duration
+
+	// ReservedPathPrefixes are the path prefixes reserved by the platform,
+	// such as "/__encore". APIs with paths under them are reported as errors.
+	// If nil it defaults to DefaultReservedPathPrefixes.
+	ReservedPathPrefixes []string
+
+	// ErrorFormatter, if non-nil, formats the returned *errlist.List,
+	// such as errlist.ANSI for terminals or errlist.JSON for tools.
+	// It is used by the list's Error method and by errlist.Print.
+	ErrorFormatter errlist.Formatter
 }
 
+// DefaultMinCronInterval is the default value of Config.MinCronInterval.
+const DefaultMinCronInterval = time.Minute
+
+// DefaultReservedPathPrefixes is the default value of Config.ReservedPathPrefixes.
+var DefaultReservedPathPrefixes = []string{"/__encore"}
+
+// A DirectiveHandler validates a directive in a custom namespace.
+// It is called with the directive text following the namespace,
+// like "audit level=high" for "//myorg:audit level=high".
+// A non-nil error is reported at the position of the directive.
+type DirectiveHandler func(directive string) error
+
 func Parse(cfg *Config) (*Result, error) {
-	p := &parser{
+	return ParseContext(context.Background(), cfg)
+}
+
+// ParseContext is like Parse but stops parsing when ctx is canceled,
+// in which case it returns ctx.Err() and no result. The context is checked
+// between packages and between the analysis passes, so a canceled parse
+// returns promptly even for large applications.
+func ParseContext(ctx context.Context, cfg *Config) (*Result, error) {
+	p, err := newParser(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+	return p.Parse()
+}
+
+// Validate parses and validates the application like Parse, but skips computing
+// the application metadata. It is a faster alternative to Parse for callers
+// that only need to know whether the application is valid.
+//
+// It returns the errors found, or nil if the application is valid.
+// Errors without a source position, like a missing go.mod file,
+// are reported as errors without a position.
+func Validate(cfg *Config) *errlist.List {
+	p, err := newParser(context.Background(), cfg)
+	if err == nil {
+		err = p.Validate()
+	}
+	if err == nil {
+		return nil
+	} else if l, ok := err.(*errlist.List); ok {
+		return l
+	}
+
+	fset := token.NewFileSet()
+	if p != nil && p.fset != nil {
+		fset = p.fset
+	}
+	l := errlist.New(fset)
+	l.SetMaxErrors(0) // the errors have already been collected
+	l.SetFormatter(cfg.ErrorFormatter)
+	if el, ok := err.(scanner.ErrorList); ok {
+		for _, e := range el {
+			l.AddRaw(e)
+		}
+	} else {
+		l.Add(token.NoPos, err.Error())
+	}
+	return l
+}
+
+func newParser(ctx context.Context, cfg *Config) (*parser, error) {
+	fsys := configFS(cfg)
+	workspace, err := readWorkspace(fsys, cfg.AppRoot, cfg.ModulePath)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.ModulePath == "" {
+		var modulePath string
+		if workspace != nil {
+			modulePath = mainModulePath(workspace)
+		} else if modulePath, err = readModulePath(fsys, cfg.AppRoot); err != nil {
+			return nil, err
+		}
+		cfgCopy := *cfg
+		cfgCopy.ModulePath = modulePath
+		cfg = &cfgCopy
+	}
+
+	return &parser{
 		cfg:                cfg,
+		ctx:                ctx,
+		workspace:          workspace,
+		fsys:               fsys,
 		declMap:            make(map[string]*schema.Decl),
+		aliases:            make(map[uint32]*est.TypeAlias),
 		validRPCReferences: make(map[ast.Node]bool),
+	}, nil
+}
+
+// readModulePath reads the module path from the go.mod file in appRoot.
+func readModulePath(fsys fs.FS, appRoot string) (string, error) {
+	modPath := filepath.Join(appRoot, "go.mod")
+	modData, err := fs.ReadFile(fsys, modPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return "", fmt.Errorf("could not determine module path: no go.mod file found in %s", appRoot)
+	} else if err != nil {
+		return "", err
 	}
-	return p.Parse()
+	modulePath := modfile.ModulePath(modData)
+	if modulePath == "" {
+		return "", fmt.Errorf("could not determine module path: %s has no module directive", modPath)
+	}
+	return modulePath, nil
 }
 
 const (
-	sqldbImportPath = "encore.dev/storage/sqldb"
-	rlogImportPath  = "encore.dev/rlog"
-	uuidImportPath  = "encore.dev/types/uuid"
-	authImportPath  = "encore.dev/beta/auth"
-	cronImportPath  = "encore.dev/cron"
+	sqldbImportPath  = "encore.dev/storage/sqldb"
+	rlogImportPath   = "encore.dev/rlog"
+	uuidImportPath   = "encore.dev/types/uuid"
+	authImportPath   = "encore.dev/beta/auth"
+	cronImportPath   = "encore.dev/cron"
+	configImportPath = "encore.dev/config"
+	pubsubImportPath = "encore.dev/pubsub"
+	apiImportPath    = "encore.dev/api"
 )
 
-func (p *parser) Parse() (res *Result, err error) {
+// Validate runs the same analysis as Parse but does not compute
+// the application metadata.
+func (p *parser) Validate() (err error) {
 	defer func() {
-		if e := recover(); e != nil {
-			if _, ok := e.(errlist.Bailout); !ok {
-				const size = 64 << 10
-				buf := make([]byte, size)
-				buf = buf[:runtime.Stack(buf, false)]
-				err = fmt.Errorf("parser panicked: %+v\n%s", e, buf)
-			}
+		err = p.finish(recover(), err)
+	}()
+	return p.analyze()
+}
+
+// finish computes the final error of a parse given the
+// recovered panic value, if any, and the error returned.
+func (p *parser) finish(panicVal interface{}, err error) error {
+	if panicVal != nil {
+		if c, ok := panicVal.(canceled); ok {
+			return c.err
+		} else if _, ok := panicVal.(errlist.Bailout); !ok {
+			const size = 64 << 10
+			buf := make([]byte, size)
+			buf = buf[:runtime.Stack(buf, false)]
+			return fmt.Errorf("parser panicked: %+v\n%s", panicVal, buf)
 		}
-		if err == nil {
-			p.errors.Sort()
-			p.errors.MakeRelative(p.cfg.AppRoot, p.cfg.WorkingDir)
-			err = p.errors.Err()
+	}
+	if err == nil {
+		if p.cfg.WarningsAsErrors {
+			p.errors.PromoteWarnings()
 		}
-	}()
-	p.fset = token.NewFileSet()
+		p.errors.Sort()
+		p.errors.MakeRelative(p.cfg.AppRoot, p.cfg.WorkingDir)
+		err = p.errors.Err()
+	}
+	return err
+}
+
+// analyze collects the application's packages and runs the
+// parsing and validation passes, in dependency order.
+func (p *parser) analyze() (err error) {
+	if p.fset == nil { // set by Reparse to a copy of the previous parse's file set
+		p.fset = token.NewFileSet()
+	}
 	p.errors = errlist.New(p.fset)
+	p.errors.SetMaxErrors(p.cfg.MaxErrors)
+	p.errors.SetFormatter(p.cfg.ErrorFormatter)
 
-	p.pkgs, err = collectPackages(p.fset, p.cfg.AppRoot, p.cfg.ModulePath, goparser.ParseComments, p.cfg.ParseTests)
+	var mode goparser.Mode
+	if !p.cfg.SkipDocComments {
+		mode |= goparser.ParseComments
+	}
+	p.progress("collecting packages", 0, 0)
+	if p.workspace != nil {
+		p.pkgs, err = collectWorkspacePackages(p.ctx, p.fsys, p.fset, p.cfg.AppRoot, p.workspace, mode, p.cfg.ParseTests, p.cfg.IncludeVendor, p.cfg.BuildTags, p.reuse)
+	} else {
+		p.pkgs, err = collectModulePackages(p.ctx, p.fsys, p.fset, p.cfg.AppRoot, p.cfg.ModulePath, mode, p.cfg.ParseTests, p.cfg.IncludeVendor, false, p.cfg.BuildTags, p.reuse)
+	}
 	if err != nil {
-		return nil, err
+		return err
 	}
+	p.progress("collecting packages", len(p.pkgs), len(p.pkgs))
 	p.pkgMap = make(map[string]*est.Package)
 	for _, pkg := range p.pkgs {
 		p.pkgMap[pkg.ImportPath] = pkg
 	}
 
 	track := names.TrackedPackages{
-		sqldbImportPath: "sqldb",
-		rlogImportPath:  "rlog",
-		uuidImportPath:  "uuid",
-		authImportPath:  "auth",
-		cronImportPath:  "cron",
+		sqldbImportPath:  "sqldb",
+		rlogImportPath:   "rlog",
+		uuidImportPath:   "uuid",
+		authImportPath:   "auth",
+		cronImportPath:   "cron",
+		configImportPath: "config",
+		pubsubImportPath: "pubsub",
+		apiImportPath:    "api",
 
 		"net/http":      "http",
 		"context":       "context",
 		"encoding/json": "json",
 		"time":          "time",
 	}
+	for _, d := range p.cfg.ResourceDetectors {
+		if _, ok := track[d.PkgPath()]; !ok {
+			track[d.PkgPath()] = path.Base(d.PkgPath())
+		}
+	}
 	p.resolveNames(track)
-	p.parseServices()
-	p.parseResources()
-	p.parseReferences()
-	p.parseCronJobs()
-	p.parseSecrets()
-	p.validateApp()
+
+	passes := []func(){
+		p.validateDirectiveNamespaces,
+		p.parseServices,
+		p.validatePrivateAPIs,
+		p.validateRPCNames,
+		p.validateReservedPaths,
+		p.parseResources,
+		p.parseConfigs,
+		p.validatePubSub,
+		p.validateMigrations,
+		p.validateMigrationDirs,
+		p.validateDatabaseOwners,
+		p.parseReferences,
+		p.validateDatabaseUsage,
+		p.resolveInitOrder,
+		p.parseRPCCalls,
+		p.validateCallCycles,
+		p.parseCronJobs,
+		p.validateCronSchedules,
+		p.parseSecrets,
+		p.validateApp,
+		p.validateDatabaseQueries,
+		p.validateContextPropagation,
+	}
+	for i, pass := range passes {
+		p.progress("validating services", i, len(passes))
+		p.checkCanceled()
+		pass()
+	}
+	p.progress("validating services", len(passes), len(passes))
+	return nil
+}
+
+// progress reports parsing progress to the configured Progress callback, if any.
+func (p *parser) progress(stage string, done, total int) {
+	if fn := p.cfg.Progress; fn != nil {
+		fn(stage, done, total)
+	}
+}
+
+func (p *parser) Parse() (res *Result, err error) {
+	defer func() {
+		err = p.finish(recover(), err)
+		if res != nil {
+			res.Warnings = p.errors.Warnings()
+		}
+	}()
+	if err := p.analyze(); err != nil {
+		return nil, err
+	}
+	p.checkCanceled()
 
 	sort.Slice(p.pkgs, func(i, j int) bool {
 		return p.pkgs[i].RelPath < p.pkgs[j].RelPath
@@ -149,6 +468,20 @@ func (p *parser) Parse() (res *Result, err error) {
 			return svc.RPCs[i].Name < svc.RPCs[j].Name
 		})
 	}
+	sort.Slice(p.jobs, func(i, j int) bool {
+		return p.jobs[i].ID < p.jobs[j].ID
+	})
+	for _, pkg := range p.pkgs {
+		// Files are parsed concurrently, so positions are only
+		// ordered within a file: order by file name first.
+		sort.SliceStable(pkg.Resources, func(i, j int) bool {
+			a, b := pkg.Resources[i], pkg.Resources[j]
+			if fa, fb := a.File().Path, b.File().Path; fa != fb {
+				return fa < fb
+			}
+			return a.Pos() < b.Pos()
+		})
+	}
 	app := &est.Application{
 		ModulePath:  p.cfg.ModulePath,
 		Packages:    p.pkgs,
@@ -157,9 +490,14 @@ func (p *parser) Parse() (res *Result, err error) {
 		Decls:       p.decls,
 		AuthHandler: p.authHandler,
 	}
-	md, nodes, err := ParseMeta(p.cfg.AppRevision, p.cfg.AppHasUncommittedChanges, p.cfg.AppRoot, app)
-	if err != nil {
-		return nil, err
+	md, nodes, metaErr := parseMeta(p.fsys, p.cfg.AppRevision, p.cfg.AppHasUncommittedChanges, p.cfg.AppRoot, app)
+	if metaErr != nil {
+		// Errors computing the metadata are frequently caused by problems
+		// we have already reported with a position, so prefer those.
+		if p.errors.Len() > 0 {
+			p.abort()
+		}
+		return nil, metaErr
 	}
 
 	return &Result{
@@ -167,100 +505,357 @@ func (p *parser) Parse() (res *Result, err error) {
 		App:     app,
 		Meta:    md,
 		Nodes:   nodes,
+		cfg:     p.cfg,
 	}, nil
 }
 
+// SecretUsages reports, for each secret declared in the application,
+// the source positions where the secret is read.
+// Secrets that are declared but never read map to an empty slice.
+func (r *Result) SecretUsages() map[string][]token.Position {
+	usages := make(map[string][]token.Position)
+	for _, pkg := range r.App.Packages {
+		for _, name := range pkg.Secrets {
+			positions := usages[name]
+			for _, pos := range pkg.SecretUsages[name] {
+				positions = append(positions, r.FileSet.Position(pos))
+			}
+			if positions == nil {
+				positions = []token.Position{}
+			}
+			usages[name] = positions
+		}
+	}
+	return usages
+}
+
+// Position resolves pos, as recorded on RPCs, cron jobs and resources,
+// to a file position using the result's file set.
+func (r *Result) Position(pos token.Pos) token.Position {
+	return r.FileSet.Position(pos)
+}
+
+// ProvisioningOrder returns the application's resources ordered such that
+// every resource comes after the resources it depends on.
+// The resources are in declaration order, except that the dependencies of
+// a resource that are declared after it are moved to just before it, so
+// resources that don't depend on each other may not keep their relative order.
+// It reports an error if the resource dependencies form a cycle.
+func (r *Result) ProvisioningOrder() ([]est.Resource, error) {
+	var all []est.Resource
+	index := make(map[est.Resource]int)
+	for _, pkg := range r.App.Packages {
+		for _, res := range pkg.Resources {
+			index[res] = len(all)
+			all = append(all, res)
+		}
+	}
+
+	order, cycle := topoSort(len(all), func(i int) []int {
+		dep, ok := all[i].(est.DependentResource)
+		if !ok {
+			return nil
+		}
+		var deps []int
+		for _, d := range dep.Dependencies() {
+			if j, ok := index[d]; ok {
+				deps = append(deps, j)
+			}
+		}
+		return deps
+	})
+	if cycle != nil {
+		names := make([]string, len(cycle))
+		for i, j := range cycle {
+			names[i] = all[j].Ident().Name
+		}
+		return nil, fmt.Errorf("resource dependency cycle: %s", strings.Join(names, " -> "))
+	}
+
+	resources := make([]est.Resource, len(order))
+	for i, j := range order {
+		resources[i] = all[j]
+	}
+	return resources, nil
+}
+
+// topoSort orders the nodes 0 to n-1 such that every node comes after
+// the nodes it depends on, as reported by deps. The nodes are in order,
+// except that the dependencies of a node that come after it are moved
+// to just before it.
+//
+// If the dependencies form a cycle it returns the nodes in the cycle,
+// starting and ending with the same node, instead of an order.
+func topoSort(n int, deps func(i int) []int) (order, cycle []int) {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make([]int, n)
+	order = make([]int, 0, n)
+	var path []int
+
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		switch state[i] {
+		case visited:
+			return true
+		case visiting:
+			for k := len(path) - 1; k >= 0; k-- {
+				if path[k] == i {
+					cycle = append(append(cycle, path[k:]...), i)
+					break
+				}
+			}
+			return false
+		}
+
+		state[i] = visiting
+		path = append(path, i)
+		for _, d := range deps(i) {
+			if !visit(d) {
+				return false
+			}
+		}
+		path = path[:len(path)-1]
+		state[i] = visited
+		order = append(order, i)
+		return true
+	}
+
+	for i := 0; i < n; i++ {
+		if !visit(i) {
+			return nil, cycle
+		}
+	}
+	return order, nil
+}
+
 // encoreBuildContext creates a build context that mirrors what we pass onto the go compiler once the we trigger a build
 // of the application. This allows us to ignore `go` files which would be exlcuded during the build.
 //
 // For instance if a file has the directive `//go:build !encore` in it
-func encoreBuildContext() build.Context {
+//
+// The given build tags are considered satisfied in addition to the defaults.
+func encoreBuildContext(buildTags []string) build.Context {
 	buildContext := build.Default
 	buildContext.ToolTags = append(buildContext.ToolTags, "encore")
+	buildContext.BuildTags = append(append([]string(nil), buildContext.BuildTags...), buildTags...)
 
 	return buildContext
 }
 
 // collectPackages collects and parses the regular Go AST
-// for all subdirectories in the root.
-func collectPackages(fs *token.FileSet, rootDir, rootImportPath string, mode goparser.Mode, parseTests bool) ([]*est.Package, error) {
+// for all subdirectories in the root, except those skipped by skipDir.
+func collectPackages(ctx context.Context, fsys fs.FS, fs *token.FileSet, rootDir, rootImportPath string, mode goparser.Mode, parseTests, includeVendor bool, buildTags []string) ([]*est.Package, error) {
+	return collectModulePackages(ctx, fsys, fs, rootDir, rootImportPath, mode, parseTests, includeVendor, false, buildTags, nil)
+}
+
+// collectWorkspacePackages is like collectPackages but collects the packages
+// of each module in the workspace rooted at appRoot, using the module's path
+// as the import path prefix. Like the go tool it leaves out directories
+// containing a go.mod file of their own, and package paths are made
+// relative to appRoot.
+func collectWorkspacePackages(ctx context.Context, fsys fs.FS, fs *token.FileSet, appRoot string, mods []workspaceModule, mode goparser.Mode, parseTests, includeVendor bool, buildTags []string, reuse map[string]*est.Package) ([]*est.Package, error) {
 	var pkgs []*est.Package
 	var errors scanner.ErrorList
-	filter := func(f os.FileInfo) bool {
-		return parseTests || !strings.HasSuffix(f.Name(), "_test.go")
+	for _, m := range mods {
+		rootDir := filepath.Join(appRoot, filepath.FromSlash(m.Dir))
+		modPkgs, err := collectModulePackages(ctx, fsys, fs, rootDir, m.Path, mode, parseTests, includeVendor, true, buildTags, reuse)
+		if el, ok := err.(scanner.ErrorList); ok {
+			errors = append(errors, el...)
+		} else if err != nil {
+			return nil, err
+		}
+		for _, pkg := range modPkgs {
+			pkg.RelPath = path.Join(m.Dir, pkg.RelPath)
+		}
+		pkgs = append(pkgs, modPkgs...)
 	}
+	return pkgs, errors.Err()
+}
 
-	buildContext := encoreBuildContext()
+// collectModulePackages implements collectPackages.
+// If skipNestedModules is true, subdirectories containing a go.mod file
+// are skipped along with everything below them.
+//
+// The directories are walked first, after which their files are read
+// and parsed concurrently by a bounded pool of workers. The packages and
+// errors are reported in walk order regardless of which worker parsed them.
+// If ctx is canceled the remaining directories are skipped and ctx.Err() is returned.
+//
+// The directories with a package in reuse, keyed by directory, are not
+// read again; instead a copy of the package is made with reusePackage.
+func collectModulePackages(ctx context.Context, fsys fs.FS, fs *token.FileSet, rootDir, rootImportPath string, mode goparser.Mode, parseTests, includeVendor, skipNestedModules bool, buildTags []string, reuse map[string]*est.Package) ([]*est.Package, error) {
+	type pkgDir struct {
+		dir, relPath string
+	}
+	var dirs []pkgDir
+	skip := func(name string) bool { return skipDir(name, includeVendor) }
+	err := walkDirs(fsys, rootDir, skip, func(dir, relPath string, files []os.FileInfo) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hasGo := false
+		for _, f := range files {
+			if skipNestedModules && relPath != "." && f.Name() == "go.mod" {
+				return filepath.SkipDir
+			} else if strings.HasSuffix(f.Name(), ".go") {
+				hasGo = true
+			}
+		}
+		if hasGo {
+			dirs = append(dirs, pkgDir{dir, relPath})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-	err := walkDirs(rootDir, func(dir, relPath string, files []os.FileInfo) error {
-		ps, pkgFiles, err := parseDir(buildContext, fs, dir, relPath, filter, mode)
-		if err != nil {
-			// If the error is an error list, it means we have a parsing error.
-			// Keep going with other directories in that case.
-			if el, ok := err.(scanner.ErrorList); ok {
-				errors = append(errors, el...)
-				return nil
+	filter := func(f os.FileInfo) bool {
+		return parseTests || !strings.HasSuffix(f.Name(), "_test.go")
+	}
+	buildContext := encoreBuildContext(buildTags)
+	buildContext.OpenFile = func(path string) (io.ReadCloser, error) { return fsys.Open(path) }
+
+	// Parse the directories concurrently. Each worker only writes
+	// to the result slots of the directories it processes.
+	type result struct {
+		pkg  *est.Package // nil if the directory has no package
+		errs scanner.ErrorList
+		err  error
+	}
+	results := make([]result, len(dirs))
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for i := 0; i < parseWorkers(len(dirs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for idx := range next {
+				if ctx.Err() != nil {
+					continue // drain the remaining directories
+				}
+				d := dirs[idx]
+				r := &results[idx]
+				if prev := reuse[d.dir]; prev != nil {
+					r.pkg = reusePackage(fs, prev, d.relPath, rootImportPath)
+					continue
+				}
+				r.pkg, r.errs, r.err = collectPackage(fsys, buildContext, fs, d.dir, d.relPath, rootImportPath, filter, mode)
 			}
-			return err
+		}()
+	}
+	for i := range dirs {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	var pkgs []*est.Package
+	var errors scanner.ErrorList
+	for _, r := range results {
+		if r.err != nil {
+			return nil, r.err
 		}
+		errors = append(errors, r.errs...)
+		if r.pkg != nil {
+			pkgs = append(pkgs, r.pkg)
+		}
+	}
+	return pkgs, errors.Err()
+}
+
+// maxParseWorkers is the maximum number of directories parsed concurrently.
+// If zero it is GOMAXPROCS.
+var maxParseWorkers = 0
 
-		var pkgNames []string
-		for name := range ps {
-			pkgNames = append(pkgNames, name)
+// parseWorkers returns the number of workers to use to parse n directories.
+func parseWorkers(n int) int {
+	workers := maxParseWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if n < workers {
+		workers = n
+	}
+	return workers
+}
+
+// collectPackage parses the package in dir. It returns a nil package if
+// the directory contains no Go files to parse. Parse errors are returned
+// as an error list so the caller can continue with other directories.
+func collectPackage(fsys fs.FS, buildContext build.Context, fs *token.FileSet, dir, relPath, rootImportPath string, filter func(os.FileInfo) bool, mode goparser.Mode) (*est.Package, scanner.ErrorList, error) {
+	ps, pkgFiles, err := parseDir(fsys, buildContext, fs, dir, relPath, filter, mode)
+	if err != nil {
+		// If the error is an error list, it means we have a parsing error.
+		// Keep going with other directories in that case.
+		if el, ok := err.(scanner.ErrorList); ok {
+			return nil, el, nil
 		}
-		if n := len(ps); n > 1 {
-			// We only support a single package for now
-			sort.Strings(pkgNames)
-			first := ps[pkgNames[0]]
-			if n == 2 && pkgNames[1] == (pkgNames[0]+"_test") {
-				// It's just a "_test" package; we're good.
-			} else {
-				namestr := strings.Join(pkgNames[:n-1], ", ") + " and " + pkgNames[n-1]
-				errors.Add(fs.Position(first.Pos()), "got multiple package names in directory: "+namestr)
-				return nil
-			}
-		} else if n == 0 {
-			// No Go files; ignore directory
-			return nil
+		return nil, nil, err
+	}
+
+	var pkgNames []string
+	for name := range ps {
+		pkgNames = append(pkgNames, name)
+	}
+	if n := len(ps); n > 1 {
+		// We only support a single package for now
+		sort.Strings(pkgNames)
+		first := ps[pkgNames[0]]
+		if n == 2 && pkgNames[1] == (pkgNames[0]+"_test") {
+			// It's just a "_test" package; we're good.
+		} else {
+			var errors scanner.ErrorList
+			namestr := strings.Join(pkgNames[:n-1], ", ") + " and " + pkgNames[n-1]
+			errors.Add(fs.Position(first.Pos()), "got multiple package names in directory: "+namestr)
+			return nil, errors, nil
 		}
+	} else if n == 0 {
+		// No Go files; ignore directory
+		return nil, nil, nil
+	}
 
-		p := ps[pkgNames[0]]
+	p := ps[pkgNames[0]]
 
-		var doc string
-		for _, astFile := range p.Files {
-			// HACK: getting package comments is not at all easy
-			// because of the quirks of go/ast. This seems to work.
-			cm := ast.NewCommentMap(fs, astFile, astFile.Comments)
-			for _, cg := range cm[astFile] {
-				if text := strings.TrimSpace(cg.Text()); text != "" {
-					doc = text
-				}
-				break
-			}
-			if doc != "" {
-				break
+	var doc string
+	for _, astFile := range p.Files {
+		// HACK: getting package comments is not at all easy
+		// because of the quirks of go/ast. This seems to work.
+		cm := ast.NewCommentMap(fs, astFile, astFile.Comments)
+		for _, cg := range cm[astFile] {
+			if text := strings.TrimSpace(cg.Text()); text != "" {
+				doc = text
 			}
+			break
 		}
-
-		pkg := &est.Package{
-			AST:        p,
-			Name:       p.Name,
-			Doc:        doc,
-			ImportPath: path.Clean(path.Join(rootImportPath, relPath)),
-			RelPath:    path.Clean(relPath),
-			Dir:        dir,
-			Files:      pkgFiles,
+		if doc != "" {
+			break
 		}
-		for _, f := range pkgFiles {
-			f.Pkg = pkg
+	}
+
+	pkg := &est.Package{
+		AST:        p,
+		Name:       p.Name,
+		Doc:        doc,
+		ImportPath: path.Clean(path.Join(rootImportPath, relPath)),
+		RelPath:    path.Clean(relPath),
+		Dir:        dir,
+		Files:      pkgFiles,
+	}
+	for _, f := range pkgFiles {
+		f.Pkg = pkg
+		if strings.HasSuffix(f.Name, "_test.go") {
+			pkg.TestFiles = append(pkg.TestFiles, f)
 		}
-		pkgs = append(pkgs, pkg)
-		return nil
-	})
-	if err != nil {
-		return nil, err
 	}
-	return pkgs, errors.Err()
+	return pkg, nil, nil
 }
 
 // resolveNames resolves identifiers for the application's packages.
@@ -271,7 +866,9 @@ func (p *parser) resolveNames(track names.TrackedPackages) {
 	for _, pkg := range p.pkgs {
 		track[pkg.ImportPath] = pkg.Name
 	}
-	for _, pkg := range p.pkgs {
+	for i, pkg := range p.pkgs {
+		p.progress("resolving names", i, len(p.pkgs))
+		p.checkCanceled()
 		res, err := names.Resolve(p.fset, track, pkg)
 		if err != nil {
 			if el, ok := err.(*errlist.List); ok {
@@ -283,11 +880,208 @@ func (p *parser) resolveNames(track names.TrackedPackages) {
 		}
 		p.names[pkg] = res
 	}
+	p.progress("resolving names", len(p.pkgs), len(p.pkgs))
 	if p.errors.Len() > 0 {
 		p.errors.Abort()
 	}
 }
 
+// validateDatabaseUsage warns about databases declared with sqldb.Named
+// that are never referenced, which usually indicates a copy-paste mistake.
+// The service's implicit database, used through the package-level
+// sqldb functions, is not a declared resource and is never reported.
+func (p *parser) validateDatabaseUsage() {
+	// References from other packages are recorded by parseReferences.
+	used := make(map[est.Resource]bool)
+	for _, pkg := range p.pkgs {
+		for _, file := range pkg.Files {
+			for _, ref := range file.References {
+				if ref.Type == est.SQLDBNode && ref.Res != nil {
+					used[ref.Res] = true
+				}
+			}
+		}
+	}
+
+	for _, pkg := range p.pkgs {
+		dbs := make(map[string]*est.SQLDB)
+		for _, res := range pkg.Resources {
+			if db, ok := res.(*est.SQLDB); ok && db.DeclName.Name != "_" && !used[db] {
+				dbs[db.DeclName.Name] = db
+			}
+		}
+		if len(dbs) == 0 {
+			continue
+		}
+
+		// Find package-local references to the databases.
+		for _, file := range pkg.Files {
+			for id, ri := range p.names[pkg].Files[file].Idents {
+				if db := dbs[id.Name]; db != nil && ri.Package && id != db.DeclName {
+					used[db] = true
+				}
+			}
+		}
+
+		for _, res := range pkg.Resources {
+			if db, ok := res.(*est.SQLDB); ok && dbs[db.DeclName.Name] == db && !used[db] {
+				p.warnf(db.DeclName.Pos(), "database %s is declared as %s but never used", db.DBName, db.DeclName.Name)
+			}
+		}
+	}
+}
+
+// validateDatabaseOwners ensures that each database is declared with
+// sqldb.Named in only one service, the service that owns it. Other services
+// must use the owning service's APIs rather than declaring the database too.
+//
+// The owner is the service with the same name as the database if it
+// declares it, and otherwise the first service declaring it. Errors are
+// reported at the sqldb.Named calls in the other services.
+func (p *parser) validateDatabaseOwners() {
+	decls := make(map[string][]*est.SQLDB)
+	var dbNames []string
+	for _, pkg := range p.pkgs {
+		if pkg.Service == nil {
+			continue
+		}
+		for _, res := range pkg.Resources {
+			if db, ok := res.(*est.SQLDB); ok {
+				if _, seen := decls[db.DBName]; !seen {
+					dbNames = append(dbNames, db.DBName)
+				}
+				decls[db.DBName] = append(decls[db.DBName], db)
+			}
+		}
+	}
+
+	for _, name := range dbNames {
+		dbs := decls[name]
+		owner := dbs[0]
+		for _, db := range dbs {
+			if db.DeclFile.Pkg.Service.Name == name {
+				owner = db
+				break
+			}
+		}
+		ownerSvc := owner.DeclFile.Pkg.Service
+		for _, db := range dbs {
+			if svc := db.DeclFile.Pkg.Service; svc != ownerSvc {
+				p.errf(db.Pos(), "database %s is declared by both service %s and service %s: "+
+					"a database must be declared in the service that owns it, and other services "+
+					"must use that service's APIs to access its data\n\t%s is declared at %s",
+					name, ownerSvc.Name, svc.Name, owner.DeclName.Name, p.fset.Position(owner.DeclName.Pos()))
+			}
+		}
+	}
+}
+
+// sqldbQueryMethods are the methods of *sqldb.Database that query the database.
+var sqldbQueryMethods = map[string]bool{
+	"Exec":     true,
+	"Query":    true,
+	"QueryRow": true,
+	"Begin":    true,
+}
+
+// validateDatabaseQueries ensures that the databases queried through
+// handles declared with sqldb.Named are defined. A database is defined
+// by the service with the same name, so a handle whose name matches
+// no service is usually a misspelling.
+//
+// Errors are reported at the first query using the database.
+func (p *parser) validateDatabaseQueries() {
+	reported := make(map[string]bool)
+	for _, pkg := range p.pkgs {
+		// Package-local handles, by the name they are declared as.
+		local := make(map[string]*est.SQLDB)
+		for _, res := range pkg.Resources {
+			if db, ok := res.(*est.SQLDB); ok && db.DeclName.Name != "_" {
+				local[db.DeclName.Name] = db
+			}
+		}
+
+		for _, file := range pkg.Files {
+			info := p.names[pkg].Files[file]
+			ast.Inspect(file.AST, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				sel, ok := call.Fun.(*ast.SelectorExpr)
+				if !ok || !sqldbQueryMethods[sel.Sel.Name] {
+					return true
+				}
+
+				// Resolve the handle the query is made on, either a package-local
+				// identifier or a reference to a handle in another package.
+				var db *est.SQLDB
+				switch x := sel.X.(type) {
+				case *ast.Ident:
+					if ri := info.Idents[x]; ri != nil && ri.Package {
+						db = local[x.Name]
+					}
+				case *ast.SelectorExpr:
+					if ref := file.References[x]; ref != nil && ref.Type == est.SQLDBNode {
+						db, _ = ref.Res.(*est.SQLDB)
+					}
+				}
+				if db == nil || p.svcMap[db.DBName] != nil || reported[db.DBName] {
+					return true
+				}
+				reported[db.DBName] = true
+
+				msg := fmt.Sprintf("database %s is not defined: no service is named %s", db.DBName, db.DBName)
+				if name := p.closestServiceName(db.DBName); name != "" {
+					msg += fmt.Sprintf(" (did you mean %q?)", name)
+				}
+				p.errf(sel.X.Pos(), "%s\n\t%s is declared at %s", msg, db.DeclName.Name, p.fset.Position(db.DeclName.Pos()))
+				return true
+			})
+		}
+	}
+}
+
+// closestServiceName returns the name of the service closest to name,
+// if it is close enough to likely be a misspelling, or "" otherwise.
+// Names are close enough if they differ in at most a third of the
+// characters of name, rounded up.
+func (p *parser) closestServiceName(name string) string {
+	best, bestDist := "", (len(name)+2)/3+1
+	for _, svc := range p.svcs {
+		if d := editDistance(name, svc.Name); d < bestDist {
+			best, bestDist = svc.Name, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	cur := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cur[j] = prev[j-1]
+			if a[i-1] != b[j-1] {
+				cur[j]++
+			}
+			if d := prev[j] + 1; d < cur[j] {
+				cur[j] = d
+			}
+			if d := cur[j-1] + 1; d < cur[j] {
+				cur[j] = d
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(b)]
+}
+
 func (p *parser) parseReferences() {
 	// For all RPCs defined, store them in a map per package for faster lookup
 	rpcMap := make(map[string]map[string]*est.RPC, len(p.pkgs)) // path -> name -> RPC
@@ -295,11 +1089,15 @@ func (p *parser) parseReferences() {
 		rpcs := make(map[string]*est.RPC, len(svc.RPCs))
 		rpcMap[svc.Root.ImportPath] = rpcs
 		for _, rpc := range svc.RPCs {
-			rpcs[rpc.Func.Name.Name] = rpc
 			rpc.File.References[rpc.Func] = &est.Node{
 				Type: est.RPCDefNode,
 				RPC:  rpc,
 			}
+			// APIs declared as methods on the service struct
+			// cannot be referenced as package-level objects.
+			if rpc.SvcStruct == nil {
+				rpcs[rpc.Func.Name.Name] = rpc
+			}
 		}
 	}
 
@@ -335,8 +1133,15 @@ func (p *parser) parseReferences() {
 						}
 						return true
 					} else if res := resourceMap[path][obj]; res != nil {
+						typ := est.PubSubNode
+						switch res.Type() {
+						case est.SQLDBResource:
+							typ = est.SQLDBNode
+						case est.CustomResource:
+							typ = est.CustomResourceNode
+						}
 						file.References[node] = &est.Node{
-							Type: est.SQLDBNode,
+							Type: typ,
 							Res:  res,
 						}
 					}
@@ -409,6 +1214,8 @@ func (p *parser) parseReferences() {
 						p.errf(astNode.Pos(), "cannot reference API %s.%s outside of a service\n\tpackage %s is not considered a service (it has no APIs defined)", rpc.Svc.Name, rpc.Name, pkg.Name)
 					case est.SQLDBNode:
 						// sqldb calls are allowed outside of services
+					case est.PubSubNode:
+						// topics can be published to outside of services
 					case est.RLogNode:
 						// rlog calls are allowed outside of services
 					default:
@@ -421,15 +1228,145 @@ func (p *parser) parseReferences() {
 	}
 }
 
+// parseRPCCalls validates the calls made to APIs and records
+// the calls that cross service boundaries.
+func (p *parser) parseRPCCalls() {
+	for _, pkg := range p.pkgs {
+		for _, file := range pkg.Files {
+			ast.Inspect(file.AST, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				ref := file.References[call.Fun]
+				if ref == nil || ref.Type != est.RPCRefNode {
+					return true
+				}
+
+				rpc := ref.RPC
+				if want := rpc.Func.Type.Params.NumFields(); len(call.Args) != want && call.Ellipsis == token.NoPos {
+					p.errf(call.Pos(), "invalid call to API %s.%s: got %d arguments, expected %d",
+						rpc.Svc.Name, rpc.Name, len(call.Args), want)
+					return true
+				}
+
+				// Calls outside of a service are reported by parseReferences.
+				caller := pkg.Service
+				if caller == nil || caller == rpc.Svc {
+					return true
+				}
+				if rpc.Access == est.Private {
+					p.errf(call.Pos(), "cannot call private API %s.%s from service %s", rpc.Svc.Name, rpc.Name, caller.Name)
+					return true
+				}
+				if rpc.Deprecated {
+					msg := ""
+					if rpc.DeprecationMessage != "" {
+						msg = ": " + rpc.DeprecationMessage
+					}
+					p.warnf(call.Pos(), "service %s calls deprecated API %s.%s%s", caller.Name, rpc.Svc.Name, rpc.Name, msg)
+				}
+				caller.Calls = append(caller.Calls, &est.RPCCall{
+					Caller: caller,
+					Target: rpc,
+					File:   file,
+					Call:   call,
+				})
+				return true
+			})
+		}
+	}
+}
+
+// validateCallCycles reports cycles in the service call graph,
+// such as service A calling service B which in turn calls service A.
+// Only actual API calls are considered; importing another service's
+// package for its types does not form an edge.
+func (p *parser) validateCallCycles() {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[*est.Service]int, len(p.svcs))
+	var path []*est.RPCCall
+
+	var visit func(svc *est.Service)
+	visit = func(svc *est.Service) {
+		state[svc] = visiting
+		seen := make(map[*est.Service]bool)
+		for _, call := range svc.Calls {
+			target := call.Target.Svc
+			if seen[target] {
+				continue
+			}
+			seen[target] = true
+
+			switch state[target] {
+			case visiting:
+				// Find where the cycle starts and report it.
+				for i := len(path) - 1; i >= 0; i-- {
+					if path[i].Caller == target {
+						p.reportCallCycle(append(path[i:len(path):len(path)], call))
+						break
+					}
+				}
+			case unvisited:
+				path = append(path, call)
+				visit(target)
+				path = path[:len(path)-1]
+			}
+		}
+		state[svc] = visited
+	}
+
+	for _, svc := range p.svcs {
+		if state[svc] == unvisited {
+			visit(svc)
+		}
+	}
+}
+
+func (p *parser) reportCallCycle(cycle []*est.RPCCall) {
+	names := []string{cycle[0].Caller.Name}
+	var calls strings.Builder
+	wdroot := filepath.Join(p.cfg.AppRoot, p.cfg.WorkingDir)
+	for _, call := range cycle {
+		names = append(names, call.Target.Svc.Name)
+		pos := p.fset.Position(call.Call.Pos())
+		if rel, err := filepath.Rel(wdroot, pos.Filename); err == nil {
+			pos.Filename = rel
+		}
+		fmt.Fprintf(&calls, "\n\t%s calls %s.%s at %s", call.Caller.Name, call.Target.Svc.Name, call.Target.Name, pos)
+	}
+	p.errf(cycle[0].Call.Pos(), "service call cycle detected: %s%s", strings.Join(names, " -> "), calls.String())
+}
+
 func (p *parser) parseSecrets() {
 	for _, pkg := range p.pkgs {
 		p.parsePackageSecrets(pkg)
+		if svc := pkg.Service; svc != nil {
+			svc.Secrets = append(svc.Secrets, pkg.Secrets...)
+		}
+	}
+	// Packages of the same service may declare the same secret.
+	for _, svc := range p.svcs {
+		sort.Strings(svc.Secrets)
+		unique := svc.Secrets[:0]
+		for i, name := range svc.Secrets {
+			if i == 0 || name != svc.Secrets[i-1] {
+				unique = append(unique, name)
+			}
+		}
+		svc.Secrets = unique
 	}
 }
 
 func (p *parser) parsePackageSecrets(pkg *est.Package) {
-	var secretsDecl *ast.StructType
-SpecLoop:
+	var (
+		secretsDecl *ast.StructType
+		secretsSpec *ast.ValueSpec
+	)
 	for _, f := range pkg.Files {
 		for _, decl := range f.AST.Decls {
 			gd, ok := decl.(*ast.GenDecl)
@@ -450,10 +1387,13 @@ SpecLoop:
 						} else if len(spec.Values) != 0 {
 							p.err(spec.Pos(), "secrets var must not be given a value")
 							return
+						} else if secretsSpec != nil {
+							p.errf(spec.Pos(), "secrets var declared multiple times in package %s (previous declaration at %s)",
+								pkg.Name, p.fset.Position(secretsSpec.Pos()))
+							return
 						}
-						secretsDecl = typ
+						secretsDecl, secretsSpec = typ, spec
 						f.References[spec] = &est.Node{Type: est.SecretsNode}
-						break SpecLoop
 					}
 				}
 			}
@@ -467,9 +1407,13 @@ SpecLoop:
 
 	names := p.names[pkg]
 	var secretNames []string
+	secretIdents := make(map[string]*ast.Ident)
 	for _, field := range secretsDecl.Fields.List {
-		if typ, ok := field.Type.(*ast.Ident); !ok || typ.Name != "string" {
-			p.errf(typ.Pos(), "field %s is not of type string", field.Names[0].Name)
+		if len(field.Names) == 0 {
+			p.errf(field.Pos(), "secrets struct cannot contain embedded field %s", types.ExprString(field.Type))
+			return
+		} else if typ, ok := field.Type.(*ast.Ident); !ok || typ.Name != "string" {
+			p.errf(field.Type.Pos(), "field %s is not of type string", field.Names[0].Name)
 			return
 		} else if decl := names.Decls["string"]; decl != nil {
 			pp := p.fset.Position(decl.Pos)
@@ -479,10 +1423,39 @@ SpecLoop:
 		}
 		for _, name := range field.Names {
 			secretNames = append(secretNames, name.Name)
+			secretIdents[name.Name] = name
 		}
 	}
 	sort.Strings(secretNames)
 	pkg.Secrets = secretNames
+
+	// Record all the places the secrets are read.
+	pkg.SecretUsages = make(map[string][]token.Pos)
+	for _, f := range pkg.Files {
+		info := names.Files[f]
+		ast.Inspect(f.AST, func(node ast.Node) bool {
+			sel, ok := node.(*ast.SelectorExpr)
+			if !ok {
+				return true
+			}
+			if id, ok := sel.X.(*ast.Ident); ok && id.Name == "secrets" {
+				if ri := info.Idents[id]; ri != nil && ri.Package {
+					if secretIdents[sel.Sel.Name] == nil {
+						p.errf(sel.Sel.Pos(), "secret %s is not declared in the secrets struct", sel.Sel.Name)
+						return true
+					}
+					pkg.SecretUsages[sel.Sel.Name] = append(pkg.SecretUsages[sel.Sel.Name], sel.Pos())
+				}
+			}
+			return true
+		})
+	}
+
+	for _, name := range secretNames {
+		if len(pkg.SecretUsages[name]) == 0 {
+			p.warnf(secretIdents[name].Pos(), "secret %s is declared but never used", name)
+		}
+	}
 }
 
 func (p *parser) parseCronJobs() {
@@ -506,6 +1479,9 @@ func (p *parser) parseCronJobs() {
 					for _, x := range vs.Values {
 						if ce, ok := x.(*ast.CallExpr); ok {
 							seenCalls[ce] = true
+							if p.checkShadowedImport(file.AST, info, ce.Fun, cronImportPath) {
+								continue
+							}
 							if cronJob := p.parseCronJobStruct(cp, ce, file, info); cronJob != nil {
 								cronJob.Doc = gd.Doc.Text()
 								if cronJob2 := p.jobsMap[cronJob.ID]; cronJob2 != nil {
@@ -534,10 +1510,37 @@ func (p *parser) parseCronJobs() {
 }
 
 const (
-	minute int64 = 60
+	second int64 = 1
+	minute int64 = 60 * second
 	hour   int64 = 60 * minute
+	day    int64 = 24 * hour
 )
 
+// CronDurationUnits are the duration units defined by the cron package
+// that can be used in cron duration expressions, in seconds.
+var CronDurationUnits = map[string]int64{
+	"Second": second,
+	"Minute": minute,
+	"Hour":   hour,
+	"Day":    day,
+}
+
+// cronDurationUnitNames returns the qualified names of the
+// cron duration units, ordered by increasing duration.
+func cronDurationUnitNames() []string {
+	names := make([]string, 0, len(CronDurationUnits))
+	for name := range CronDurationUnits {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return CronDurationUnits[names[i]] < CronDurationUnits[names[j]]
+	})
+	for i, name := range names {
+		names[i] = "cron." + name
+	}
+	return names
+}
+
 func (p *parser) parseCronJobStruct(cp cronparser.Parser, ce *ast.CallExpr, file *est.File, info *names.File) *est.CronJob {
 	if imp, obj := pkgObj(info, ce.Fun); imp == cronImportPath && obj == "NewJob" {
 		if len(ce.Args) != 2 {
@@ -545,7 +1548,7 @@ func (p *parser) parseCronJobStruct(cp cronparser.Parser, ce *ast.CallExpr, file
 			return nil
 		}
 
-		cj := &est.CronJob{}
+		cj := &est.CronJob{Pos: ce.Pos()}
 		if bl, ok := ce.Args[0].(*ast.BasicLit); ok && bl.Kind == token.STRING {
 			cronJobID, _ := strconv.Unquote(bl.Value)
 			if cronJobID == "" {
@@ -566,6 +1569,9 @@ func (p *parser) parseCronJobStruct(cp cronparser.Parser, ce *ast.CallExpr, file
 		}
 
 		if cl, ok := ce.Args[1].(*ast.CompositeLit); ok {
+			if p.checkShadowedImport(file.AST, info, cl.Type, cronImportPath) {
+				return nil
+			}
 			if imp, obj := pkgObj(info, cl.Type); imp == cronImportPath && obj == "JobConfig" {
 				hasSchedule := false
 				for _, e := range cl.Elts {
@@ -589,7 +1595,12 @@ func (p *parser) parseCronJobStruct(cp cronparser.Parser, ce *ast.CallExpr, file
 							p.errf(kv.Pos(), "Every: cron execution schedule was already defined using the Schedule field, at least one must be set but not both")
 							return nil
 						}
-						if dur, ok := p.parseCronLiteral(info, kv.Value); ok {
+						if dur, ok := p.parseCronLiteral(file.AST, info, kv.Value); ok {
+							if interval, minInterval := time.Duration(dur)*time.Second, p.minCronInterval(); interval < minInterval {
+								p.errf(kv.Value.Pos(), "Every: cron jobs must not run more often than every %s, got %s", minInterval, interval)
+								return nil
+							}
+
 							// We only support intervals that are a positive integer number of minutes.
 							if rem := dur % minute; rem != 0 {
 								p.errf(kv.Value.Pos(), "Every: must be an integer number of minutes, got %d", dur)
@@ -628,6 +1639,11 @@ func (p *parser) parseCronJobStruct(cp cronparser.Parser, ce *ast.CallExpr, file
 								return nil
 							}
 							cj.Schedule = fmt.Sprintf("schedule:%s", parsed)
+							if interval, ok := cronInterval(cp, cj.Schedule); ok && interval < p.minCronInterval() {
+								p.errf(v.Pos(), "Schedule: cron jobs must not run more often than every %s, but %q runs every %s",
+									p.minCronInterval(), parsed, interval)
+								return nil
+							}
 							hasSchedule = true
 						} else {
 							p.errf(v.Pos(), "Schedule must be a string literal")
@@ -637,12 +1653,18 @@ func (p *parser) parseCronJobStruct(cp cronparser.Parser, ce *ast.CallExpr, file
 						// This is one of the places where it's fine to reference an RPC endpoint.
 						p.validRPCReferences[kv.Value] = true
 
-						if ref, ok := file.References[kv.Value]; ok && ref.Type == est.RPCRefNode {
-							cj.RPC = ref.RPC
-						} else {
+						ref, ok := file.References[kv.Value]
+						if !ok || ref.Type != est.RPCRefNode {
 							p.errf(kv.Value.Pos(), "Endpoint does not reference an Encore API")
 							return nil
 						}
+						rpc := ref.RPC
+						if rpc.Raw || rpc.Request != nil || rpc.Func.Type.Params.NumFields() > 1 {
+							p.errf(kv.Value.Pos(), "Endpoint %s.%s cannot be called by a cron job: "+
+								"cron jobs can only call APIs that take no parameters other than context.Context", rpc.Svc.Name, rpc.Name)
+							return nil
+						}
+						cj.Endpoint = rpc
 					default:
 						p.errf(key.Pos(), "cron.JobConfig has unknown key %s", key.Name)
 						return nil
@@ -659,6 +1681,85 @@ func (p *parser) parseCronJobStruct(cp cronparser.Parser, ce *ast.CallExpr, file
 	return nil
 }
 
+// minCronInterval returns the configured minimum interval between cron job runs.
+// Cron jobs cannot run more often than every minute, so shorter intervals
+// are raised to one minute.
+func (p *parser) minCronInterval() time.Duration {
+	if d := p.cfg.MinCronInterval; d > time.Minute {
+		return d
+	}
+	return DefaultMinCronInterval
+}
+
+// frequentCronInterval is the interval at or below which
+// a cron job is considered to run frequently.
+const frequentCronInterval = 5 * time.Minute
+
+// validateCronSchedules warns about multiple cron jobs that frequently
+// call the same endpoint, which is usually unintended.
+func (p *parser) validateCronSchedules() {
+	cp := cronparser.NewParser(cronparser.Minute | cronparser.Hour | cronparser.Dom | cronparser.Month | cronparser.Dow)
+	byEndpoint := make(map[*est.RPC][]*est.CronJob)
+	var endpoints []*est.RPC
+	for _, job := range p.jobs {
+		if job.Endpoint == nil {
+			continue
+		}
+		interval, ok := cronInterval(cp, job.Schedule)
+		if !ok || interval > frequentCronInterval {
+			continue
+		}
+		if byEndpoint[job.Endpoint] == nil {
+			endpoints = append(endpoints, job.Endpoint)
+		}
+		byEndpoint[job.Endpoint] = append(byEndpoint[job.Endpoint], job)
+	}
+
+	for _, rpc := range endpoints {
+		jobs := byEndpoint[rpc]
+		if len(jobs) < 2 {
+			continue
+		}
+		ids := make([]string, len(jobs))
+		for i, job := range jobs {
+			ids[i] = job.ID
+		}
+		p.warnf(jobs[0].Pos, "cron jobs %s all call %s.%s at least every %d minutes: consider combining them into a single job",
+			strings.Join(ids, ", "), rpc.Svc.Name, rpc.Name, int(frequentCronInterval/time.Minute))
+	}
+}
+
+// cronInterval reports the shortest interval between two runs of a cron job
+// with the given schedule, as stored in est.CronJob.Schedule.
+func cronInterval(cp cronparser.Parser, schedule string) (time.Duration, bool) {
+	if s := strings.TrimPrefix(schedule, "every:"); s != schedule {
+		minutes, err := strconv.Atoi(s)
+		return time.Duration(minutes) * time.Minute, err == nil
+	}
+	sched, err := cp.Parse(strings.TrimPrefix(schedule, "schedule:"))
+	if err != nil {
+		return 0, false
+	}
+
+	// Sample the first runs within a year, stopping early
+	// once we've found the shortest possible interval.
+	const maxRuns = 10000
+	prev := sched.Next(time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC))
+	end := prev.AddDate(1, 0, 0)
+	var shortest time.Duration
+	for i := 0; i < maxRuns && shortest != time.Minute; i++ {
+		next := sched.Next(prev)
+		if next.IsZero() || next.After(end) {
+			break
+		}
+		if d := next.Sub(prev); shortest == 0 || d < shortest {
+			shortest = d
+		}
+		prev = next
+	}
+	return shortest, shortest > 0
+}
+
 // validateApp performs full-app validation after everything has been parsed.
 func (p *parser) validateApp() {
 	// Error if we have auth endpoints without an auth handlers
@@ -682,6 +1783,14 @@ func (p *parser) validateApp() {
 				switch res.Type() {
 				case est.SQLDBResource:
 					resType = "SQL Database"
+				case est.PubSubTopicResource:
+					// Topics can be declared outside of services
+					continue
+				case est.PubSubSubscriptionResource:
+					resType = "PubSub Subscription"
+				case est.CustomResource:
+					// Where custom resources can be declared is up to their detectors.
+					continue
 				default:
 					panic(fmt.Sprintf("unsupported resource type %v", res.Type()))
 				}
@@ -690,7 +1799,8 @@ func (p *parser) validateApp() {
 		}
 		for _, f := range pkg.Files {
 			for node, ref := range f.References {
-				if res := ref.Res; ref.Res != nil {
+				if res := ref.Res; ref.Res != nil && res.Type() != est.PubSubTopicResource {
+					// Topics can be referenced from any service to publish to them.
 					if ff := res.File(); ff.Pkg.Service != nil && (pkg.Service == nil || pkg.Service.Name != ff.Pkg.Service.Name) {
 						p.errf(node.Pos(), "cannot reference resource %s.%s outside the service", ff.Pkg.Name, res.Ident().Name)
 					}
@@ -756,7 +1866,7 @@ func (p *parser) isCronIntervalAllowed(val int) (suggestion int, ok bool) {
 // parseCronLiteral parses an expression representing a cron duration constant.
 // It uses go/constant to perform arbitrary-precision arithmetic according
 // to the rules of the Go compiler.
-func (p *parser) parseCronLiteral(info *names.File, durationExpr ast.Expr) (dur int64, ok bool) {
+func (p *parser) parseCronLiteral(f *ast.File, info *names.File, durationExpr ast.Expr) (dur int64, ok bool) {
 	zero := constant.MakeInt64(0)
 	var parse func(expr ast.Expr) constant.Value
 	parse = func(expr ast.Expr) constant.Value {
@@ -774,6 +1884,15 @@ func (p *parser) parseCronLiteral(info *names.File, durationExpr ast.Expr) (dur
 					return constant.MakeUnknown()
 				}
 
+				// Like Go, divide integers using integer division,
+				// and warn if that discards a remainder.
+				if lhs.Kind() == constant.Int && rhs.Kind() == constant.Int {
+					quo := constant.BinaryOp(lhs, token.QUO_ASSIGN, rhs)
+					if rem := constant.BinaryOp(lhs, token.REM, rhs); !constant.Compare(rem, token.EQL, zero) {
+						p.warnf(x.Pos(), "integer division truncates %s to %s (remainder %s)", types.ExprString(x), quo, rem)
+					}
+					return quo
+				}
 				return constant.BinaryOp(lhs, x.Op, rhs)
 			default:
 				p.errf(x.Pos(), "unsupported operation: %s", x.Op)
@@ -811,24 +1930,24 @@ func (p *parser) parseCronLiteral(info *names.File, durationExpr ast.Expr) (dur
 					}
 				}
 			}
-			p.errf(x.Pos(), "unsupported call expression in duration expression")
+			if !p.checkShadowedImport(f, info, x.Fun, cronImportPath) {
+				p.errf(x.Pos(), "unsupported call expression in duration expression")
+			}
 			return constant.MakeUnknown()
 
 		case *ast.SelectorExpr:
 			if pkg, obj := pkgObj(info, x); pkg == cronImportPath {
-				var d int64
-				switch obj {
-				case "Minute":
-					d = minute
-				case "Hour":
-					d = hour
-				default:
-					p.errf(x.Pos(), "unsupported duration value: %s.%s (expected cron.Minute or cron.Hour)", pkg, obj)
+				d, ok := CronDurationUnits[obj]
+				if !ok {
+					p.errf(x.Pos(), "unsupported duration value: cron.%s (expected one of %s)",
+						obj, strings.Join(cronDurationUnitNames(), ", "))
 					return constant.MakeUnknown()
 				}
 				return constant.MakeInt64(d)
 			}
-			p.errf(x.Pos(), "unexpected value in duration literal")
+			if !p.checkShadowedImport(f, info, x, cronImportPath) {
+				p.errf(x.Pos(), "unexpected value in duration literal")
+			}
 			return constant.MakeUnknown()
 
 		case *ast.ParenExpr:
@@ -881,3 +2000,89 @@ func pkgObj(info *names.File, node ast.Node) (pkgPath, objName string) {
 	}
 	return "", ""
 }
+
+// checkShadowedImport reports an error if node is a selector that looks like a
+// reference to the package with the given import path, which the file imports,
+// but whose qualifier resolves to something else. This happens when the import
+// is shadowed by a declaration of the same name, or when the package is imported
+// under a different name than the one used. It also reports an error if the
+// qualifier is unresolved and no import in f binds its name.
+// It reports whether an error was reported.
+func (p *parser) checkShadowedImport(f *ast.File, info *names.File, node ast.Node, importPath string) bool {
+	sel, ok := node.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	id, ok := sel.X.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	ri := info.Idents[id]
+	localName, imported := info.PathToName[importPath]
+	if !imported {
+		if ri == nil && id.Name == path.Base(importPath) && !importsName(f, id.Name) {
+			p.errf(sel.Pos(), "%s used but %s not imported", types.ExprString(sel), importPath)
+			return true
+		}
+		return false
+	}
+
+	switch {
+	case ri != nil && ri.ImportPath == importPath:
+		return false
+	case id.Name == localName && ri != nil && ri.Local:
+		p.errf(id.Pos(), "%s: %s refers to a local variable that shadows the import of %s",
+			types.ExprString(sel), id.Name, importPath)
+	case id.Name == localName && ri != nil && ri.Package:
+		p.errf(id.Pos(), "%s: %s refers to a package-level declaration that shadows the import of %s",
+			types.ExprString(sel), id.Name, importPath)
+	case id.Name == path.Base(importPath) && id.Name != localName:
+		p.errf(id.Pos(), "%s: %s does not refer to %s, which is imported as %s",
+			types.ExprString(sel), id.Name, importPath, localName)
+	default:
+		return false
+	}
+	return true
+}
+
+// importsName reports whether an import in f may bind name, including
+// imports of packages that are not tracked by the name resolver.
+// The name of a package imported without an explicit name is assumed
+// from its import path, since the package itself is not loaded.
+func importsName(f *ast.File, name string) bool {
+	if f == nil {
+		return false
+	}
+	for _, is := range f.Imports {
+		if is.Name != nil {
+			if is.Name.Name == name || is.Name.Name == "." {
+				return true
+			}
+			continue
+		}
+		if importPath, err := strconv.Unquote(is.Path.Value); err == nil && assumedPackageName(importPath) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// assumedPackageName returns the package name assumed for importPath:
+// its last element, skipping major version suffixes like "v3",
+// without a "go-" prefix and cut at the first character that
+// is not valid in an identifier (as in "yaml.v2").
+func assumedPackageName(importPath string) string {
+	base := path.Base(importPath)
+	if len(base) > 1 && base[0] == 'v' && strings.Trim(base[1:], "0123456789") == "" {
+		if dir := path.Dir(importPath); dir != "." {
+			base = path.Base(dir)
+		}
+	}
+	base = strings.TrimPrefix(base, "go-")
+	if i := strings.IndexFunc(base, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_'
+	}); i >= 0 {
+		base = base[:i]
+	}
+	return base
+}
@@ -13,6 +13,7 @@ import (
 
 func init() {
 	additionalTypeResolver = go118ResolveType
+	funcTypeParams = func(fd *ast.FuncDecl) *ast.FieldList { return fd.Type.TypeParams }
 }
 
 func go118ResolveType(p *parser, pkg *est.Package, file *est.File, expr ast.Expr) *schema.Type {
@@ -1,6 +1,9 @@
 package parser
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
 	"fmt"
 	"go/ast"
 	goparser "go/parser"
@@ -9,18 +12,24 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"testing"
+	"testing/fstest"
+	"time"
 
 	qt "github.com/frankban/quicktest"
 	"github.com/rogpeppe/go-internal/testscript"
 	"github.com/rogpeppe/go-internal/txtar"
-	"golang.org/x/mod/modfile"
+	"google.golang.org/protobuf/proto"
 
 	"encr.dev/pkg/errlist"
 
 	"encr.dev/parser/est"
 	"encr.dev/parser/internal/names"
+	"encr.dev/parser/paths"
+	meta "encr.dev/proto/encore/parser/meta/v1"
+	schema "encr.dev/proto/encore/parser/schema/v1"
 )
 
 func TestCollectPackages(t *testing.T) {
@@ -84,7 +93,7 @@ package b;
 		c.Assert(err, qt.IsNil, qt.Commentf("test #%d", i))
 
 		fs := token.NewFileSet()
-		pkgs, err := collectPackages(fs, base, modulePath, goparser.ParseComments, true)
+		pkgs, err := collectPackages(context.Background(), osFS{}, fs, base, modulePath, goparser.ParseComments, true, false, nil)
 		if test.Err != "" {
 			c.Assert(err, qt.ErrorMatches, test.Err, qt.Commentf("test #%d", i))
 			continue
@@ -101,6 +110,561 @@ package b;
 	}
 }
 
+func TestCollectPackagesWithTests(t *testing.T) {
+	const archive = `
+-- a/a.go --
+package foo
+-- a/a_test.go --
+package foo
+-- a/ext_test.go --
+package foo_test
+-- b/b.go --
+package bar
+`
+	c := qt.New(t)
+	a := txtar.Parse([]byte(archive))
+	base := t.TempDir()
+	err := txtar.Write(a, base)
+	c.Assert(err, qt.IsNil)
+
+	fileNames := func(files []*est.File) []string {
+		var names []string
+		for _, f := range files {
+			names = append(names, f.Name)
+		}
+		return names
+	}
+
+	// Test files are excluded by default.
+	pkgs, err := collectPackages(context.Background(), osFS{}, token.NewFileSet(), base, "test.path", goparser.ParseComments, false, false, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(pkgs, qt.HasLen, 2)
+	c.Assert(fileNames(pkgs[0].Files), qt.DeepEquals, []string{"a.go"})
+	c.Assert(pkgs[0].TestFiles, qt.IsNil)
+
+	pkgs, err = collectPackages(context.Background(), osFS{}, token.NewFileSet(), base, "test.path", goparser.ParseComments, true, false, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(pkgs, qt.HasLen, 2)
+	c.Assert(pkgs[0].Name, qt.Equals, "foo")
+	c.Assert(fileNames(pkgs[0].Files), qt.DeepEquals, []string{"a.go", "a_test.go", "ext_test.go"})
+	c.Assert(fileNames(pkgs[0].TestFiles), qt.DeepEquals, []string{"a_test.go", "ext_test.go"})
+	for _, f := range pkgs[0].TestFiles {
+		c.Assert(f.Pkg, qt.Equals, pkgs[0])
+	}
+	c.Assert(pkgs[1].Name, qt.Equals, "bar")
+	c.Assert(pkgs[1].TestFiles, qt.IsNil)
+}
+
+// writeSyntheticTree writes a module with the given number of packages,
+// each with the given number of files, to a temporary directory.
+// Every tenth package has a file with a syntax error if withErrors is set.
+func writeSyntheticTree(tb testing.TB, numPkgs, numFiles int, withErrors bool) string {
+	base := tb.TempDir()
+	for i := 0; i < numPkgs; i++ {
+		dir := filepath.Join(base, fmt.Sprintf("pkg%d", i/10), fmt.Sprintf("sub%d", i))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			tb.Fatal(err)
+		}
+		for j := 0; j < numFiles; j++ {
+			src := fmt.Sprintf("// Package sub%d is synthetic.\npackage sub%d\n\nfunc F%d(x int) int { return x * %d }\n", i, i, j, j)
+			if withErrors && i%10 == 0 && j == 0 {
+				src = fmt.Sprintf("package sub%d\n\nfunc broken( {\n", i)
+			}
+			if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("f%d.go", j)), []byte(src), 0644); err != nil {
+				tb.Fatal(err)
+			}
+		}
+	}
+	return base
+}
+
+func TestCollectPackagesConcurrent(t *testing.T) {
+	c := qt.New(t)
+	base := writeSyntheticTree(t, 200, 5, true)
+
+	// collect parses the tree with the given number of workers,
+	// summarizing the packages and errors in the order reported.
+	collect := func(workers int) (pkgs, errs []string) {
+		defer func(prev int) { maxParseWorkers = prev }(maxParseWorkers)
+		maxParseWorkers = workers
+
+		fset := token.NewFileSet()
+		result, err := collectPackages(context.Background(), osFS{}, fset, base, "test", goparser.ParseComments, false, false, nil)
+		for _, pkg := range result {
+			var files []string
+			for _, f := range pkg.Files {
+				files = append(files, f.Name)
+				c.Assert(f.Pkg, qt.Equals, pkg)
+			}
+			pkgs = append(pkgs, fmt.Sprintf("%s %s %q %v", pkg.ImportPath, pkg.Name, pkg.Doc, files))
+		}
+		if el, ok := err.(scanner.ErrorList); ok {
+			for _, e := range el {
+				errs = append(errs, e.Error())
+			}
+		} else {
+			c.Assert(err, qt.IsNil)
+		}
+		return pkgs, errs
+	}
+
+	wantPkgs, wantErrs := collect(1)
+	c.Assert(wantPkgs, qt.HasLen, 180)
+	c.Assert(wantErrs, qt.HasLen, 20)
+	for _, workers := range []int{2, 8, 32} {
+		gotPkgs, gotErrs := collect(workers)
+		c.Assert(gotPkgs, qt.DeepEquals, wantPkgs, qt.Commentf("workers=%d", workers))
+		c.Assert(gotErrs, qt.DeepEquals, wantErrs, qt.Commentf("workers=%d", workers))
+	}
+}
+
+func TestParseContextCanceled(t *testing.T) {
+	c := qt.New(t)
+	base := writeSyntheticTree(t, 200, 5, false)
+
+	// Cancel the parse from the progress callback once it reaches
+	// the given stage, and check that it stops with context.Canceled.
+	for _, stage := range []string{"collecting packages", "resolving names", "validating services"} {
+		ctx, cancel := context.WithCancel(context.Background())
+		var stagesAfterCancel []string
+		progress := func(s string, done, total int) {
+			if ctx.Err() != nil {
+				stagesAfterCancel = append(stagesAfterCancel, s)
+			} else if s == stage && done == total/2 {
+				cancel()
+			}
+		}
+		start := time.Now()
+		res, err := ParseContext(ctx, &Config{AppRoot: base, WorkingDir: ".", ModulePath: "test", Progress: progress})
+		c.Assert(err, qt.Equals, context.Canceled, qt.Commentf("stage %s", stage))
+		c.Assert(res, qt.IsNil, qt.Commentf("stage %s", stage))
+		c.Assert(time.Since(start) < 10*time.Second, qt.IsTrue, qt.Commentf("stage %s", stage))
+
+		// Apart from completing the stage the cancellation was
+		// noticed in, no further progress is made.
+		for _, s := range stagesAfterCancel {
+			c.Assert(s, qt.Equals, stage, qt.Commentf("stage %s", stage))
+		}
+		cancel()
+	}
+
+	// An uncanceled context parses the app as usual.
+	res, err := ParseContext(context.Background(), &Config{AppRoot: base, WorkingDir: ".", ModulePath: "test"})
+	c.Assert(err, qt.IsNil)
+	c.Assert(res.App.Packages, qt.HasLen, 200)
+}
+
+func BenchmarkCollectPackages(b *testing.B) {
+	base := writeSyntheticTree(b, 500, 10, false)
+	for _, workers := range []int{1, 0} {
+		name := "serial"
+		if workers == 0 {
+			name = "parallel"
+		}
+		b.Run(name, func(b *testing.B) {
+			defer func(prev int) { maxParseWorkers = prev }(maxParseWorkers)
+			maxParseWorkers = workers
+			for i := 0; i < b.N; i++ {
+				if _, err := collectPackages(context.Background(), osFS{}, token.NewFileSet(), base, "test", goparser.ParseComments, false, false, nil); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestCollectPackagesWithoutComments(t *testing.T) {
+	c := qt.New(t)
+	a := txtar.Parse([]byte(`
+-- svc/svc.go --
+// Package svc is a service.
+package svc
+
+import "context"
+
+// Params are the parameters.
+type Params struct {
+	// Name is the name.
+	Name string
+}
+
+// Foo is an API.
+//encore:api public
+func Foo(ctx context.Context, p *Params) error { return nil }
+
+// bar is a helper.
+func bar() {}
+`))
+	base := t.TempDir()
+	err := txtar.Write(a, base)
+	c.Assert(err, qt.IsNil)
+
+	fs := token.NewFileSet()
+	pkgs, err := collectPackages(context.Background(), osFS{}, fs, base, "test", 0, false, false, nil)
+	c.Assert(err, qt.IsNil)
+	c.Assert(pkgs, qt.HasLen, 1)
+	c.Assert(pkgs[0].Doc, qt.Equals, "")
+
+	f := pkgs[0].Files[0].AST
+	c.Assert(f.Comments, qt.HasLen, 1)
+	for _, decl := range f.Decls {
+		switch decl := decl.(type) {
+		case *ast.FuncDecl:
+			if decl.Name.Name == "Foo" {
+				c.Assert(decl.Doc, qt.IsNotNil)
+				c.Assert(isDirectiveComment(decl.Doc), qt.IsTrue)
+			} else {
+				c.Assert(decl.Doc, qt.IsNil)
+			}
+		case *ast.GenDecl:
+			c.Assert(decl.Doc, qt.IsNil)
+		}
+	}
+}
+
+func TestParseDeterministicOrder(t *testing.T) {
+	c := qt.New(t)
+	a := txtar.Parse([]byte(`
+-- zeta/zeta.go --
+package zeta
+
+import "context"
+
+//encore:api public
+func Zulu(ctx context.Context) error { return nil }
+
+//encore:api public
+func Alpha(ctx context.Context) error { return nil }
+
+//encore:api public
+func Mike(ctx context.Context) error { return nil }
+-- alpha/alpha.go --
+package alpha
+
+import (
+	"context"
+
+	"encore.dev/cron"
+	"encore.dev/pubsub"
+)
+
+type Event struct {
+	ID string
+}
+
+var Second = pubsub.NewTopic[Event]("second", pubsub.TopicConfig{})
+
+var First = pubsub.NewTopic[Event]("first", pubsub.TopicConfig{})
+
+var _ = cron.NewJob("nightly", cron.JobConfig{Title: "Nightly", Schedule: "0 0 * * *", Endpoint: Tick})
+
+var _ = cron.NewJob("hourly", cron.JobConfig{Title: "Hourly", Schedule: "0 * * * *", Endpoint: Tick})
+
+//encore:api private
+func Tick(ctx context.Context) error { return nil }
+
+//encore:api public
+func Hello(ctx context.Context) error { return nil }
+-- mid/mid.go --
+package mid
+
+import "context"
+
+//encore:api public
+func Ping(ctx context.Context) error { return nil }
+`))
+	base := t.TempDir()
+	err := txtar.Write(a, base)
+	c.Assert(err, qt.IsNil)
+
+	// summarize describes the order of the parsed application's contents.
+	summarize := func(app *est.Application) []string {
+		var out []string
+		for _, svc := range app.Services {
+			for _, rpc := range svc.RPCs {
+				out = append(out, "rpc "+svc.Name+"."+rpc.Name)
+			}
+		}
+		for _, job := range app.CronJobs {
+			out = append(out, "cronJob "+job.ID)
+		}
+		for _, pkg := range app.Packages {
+			for _, res := range pkg.Resources {
+				out = append(out, "resource "+pkg.Name+"."+res.Ident().Name)
+			}
+		}
+		return out
+	}
+
+	var prev []string
+	for i := 0; i < 2; i++ {
+		res, err := Parse(&Config{AppRoot: base, WorkingDir: ".", ModulePath: "test"})
+		c.Assert(err, qt.IsNil)
+		got := summarize(res.App)
+		c.Assert(got, qt.DeepEquals, []string{
+			"rpc alpha.Hello",
+			"rpc alpha.Tick",
+			"rpc mid.Ping",
+			"rpc zeta.Alpha",
+			"rpc zeta.Mike",
+			"rpc zeta.Zulu",
+			"cronJob hourly",
+			"cronJob nightly",
+			"resource alpha.Second",
+			"resource alpha.First",
+		})
+		if prev != nil {
+			c.Assert(got, qt.DeepEquals, prev)
+		}
+		prev = got
+	}
+}
+
+func TestParseMaxErrors(t *testing.T) {
+	c := qt.New(t)
+	// Declare many APIs with invalid results, each reported as an error.
+	var src strings.Builder
+	src.WriteString("package svc\n\nimport \"context\"\n")
+	for i := 0; i < 30; i++ {
+		fmt.Fprintf(&src, "\n//encore:api public\nfunc API%d(ctx context.Context) string { return \"\" }\n", i)
+	}
+	base := t.TempDir()
+	err := txtar.Write(&txtar.Archive{Files: []txtar.File{{Name: "svc/svc.go", Data: []byte(src.String())}}}, base)
+	c.Assert(err, qt.IsNil)
+
+	for _, max := range []int{0, 5} {
+		_, err := Parse(&Config{AppRoot: base, WorkingDir: ".", ModulePath: "test", MaxErrors: max})
+		list, ok := err.(*errlist.List)
+		c.Assert(ok, qt.IsTrue, qt.Commentf("got err %v", err))
+		if max == 0 {
+			c.Assert(list.Bailed(), qt.IsFalse)
+			c.Assert(list.Len(), qt.Equals, 30)
+		} else {
+			c.Assert(list.Bailed(), qt.IsTrue)
+			c.Assert(list.Len(), qt.Equals, max)
+			var buf bytes.Buffer
+			errlist.Print(&buf, list)
+			c.Assert(strings.HasSuffix(buf.String(), "too many errors\n"), qt.IsTrue, qt.Commentf("got output:\n%s", buf.String()))
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	c := qt.New(t)
+	a := txtar.Parse([]byte(`
+-- valid/valid.go --
+package valid
+
+import "context"
+
+//encore:api public
+func Ping(ctx context.Context) error { return nil }
+-- invalid/invalid.go --
+package invalid
+
+import "context"
+
+//encore:api public
+func Ping(ctx context.Context) string { return "" }
+`))
+	base := t.TempDir()
+	err := txtar.Write(a, base)
+	c.Assert(err, qt.IsNil)
+
+	cfg := &Config{AppRoot: base, WorkingDir: ".", ModulePath: "test"}
+	errs := Validate(cfg)
+	c.Assert(errs, qt.IsNotNil)
+	c.Assert(errs.Error(), qt.Matches, `(?s)invalid/invalid.go:6:1: API endpoints must return \(response, error\) or error.*`)
+
+	// The errors must be the same as those reported by Parse.
+	_, err = Parse(cfg)
+	c.Assert(err, qt.Not(qt.IsNil))
+	c.Assert(errs.Error(), qt.Equals, err.Error())
+
+	err = os.RemoveAll(filepath.Join(base, "invalid"))
+	c.Assert(err, qt.IsNil)
+	c.Assert(Validate(cfg) == nil, qt.IsTrue)
+
+	// Errors without a position are reported too.
+	errs = Validate(&Config{AppRoot: t.TempDir(), WorkingDir: "."})
+	c.Assert(errs, qt.IsNotNil)
+	c.Assert(errs.Error(), qt.Matches, `could not determine module path: no go.mod file found in .*`)
+}
+
+func TestWarningsAsErrors(t *testing.T) {
+	c := qt.New(t)
+	a := txtar.Parse([]byte(`
+-- svc/svc.go --
+package svc
+
+import "context"
+
+// ping pings the service.
+//encore:api public
+func ping(ctx context.Context) error { return nil }
+`))
+	base := t.TempDir()
+	err := txtar.Write(a, base)
+	c.Assert(err, qt.IsNil)
+
+	const warning = "svc/svc.go:7:6: API endpoint ping is not exported and cannot be called from other services"
+	cfg := &Config{AppRoot: base, WorkingDir: ".", ModulePath: "test"}
+	res, err := Parse(cfg)
+	c.Assert(err, qt.IsNil)
+	c.Assert(res.Warnings, qt.HasLen, 1)
+	c.Assert(res.Warnings[0].Error(), qt.Equals, warning)
+
+	cfg.WarningsAsErrors = true
+	_, err = Parse(cfg)
+	list, ok := err.(*errlist.List)
+	c.Assert(ok, qt.IsTrue, qt.Commentf("got err %v", err))
+	c.Assert(list.Error(), qt.Equals, warning)
+	c.Assert(list.Warnings(), qt.HasLen, 1)
+	c.Assert(list.Warnings()[0].Error(), qt.Equals, warning)
+}
+
+func TestErrorFormatter(t *testing.T) {
+	c := qt.New(t)
+	a := txtar.Parse([]byte(`
+-- svc/svc.go --
+package svc
+
+import "context"
+
+//encore:api public
+func Ping(ctx context.Context) string { return "" }
+`))
+	base := t.TempDir()
+	err := txtar.Write(a, base)
+	c.Assert(err, qt.IsNil)
+
+	cfg := &Config{AppRoot: base, WorkingDir: ".", ModulePath: "test", ErrorFormatter: errlist.JSON}
+	_, err = Parse(cfg)
+	c.Assert(err, qt.Not(qt.IsNil))
+	c.Assert(err.Error(), qt.Matches, `\{"filename":"svc/svc.go","line":6,"column":1,"message":"API endpoints must return \(response, error\) or error.*"\}`)
+
+	// Validate formats its errors the same way.
+	c.Assert(Validate(cfg).Error(), qt.Equals, err.Error())
+}
+
+func TestProgress(t *testing.T) {
+	c := qt.New(t)
+	a := txtar.Parse([]byte(`
+-- svc/svc.go --
+package svc
+
+import "context"
+
+//encore:api public
+func Ping(ctx context.Context) error { return nil }
+-- lib/lib.go --
+package lib
+`))
+	base := t.TempDir()
+	err := txtar.Write(a, base)
+	c.Assert(err, qt.IsNil)
+
+	type call struct {
+		stage       string
+		done, total int
+	}
+	var calls []call
+	cfg := &Config{
+		AppRoot:    base,
+		WorkingDir: ".",
+		ModulePath: "test",
+		Progress: func(stage string, done, total int) {
+			calls = append(calls, call{stage, done, total})
+		},
+	}
+	_, err = Parse(cfg)
+	c.Assert(err, qt.IsNil)
+
+	// The stages are reported in order, with monotonic progress
+	// ending with done == total.
+	var stages []string
+	for i, cl := range calls {
+		if i == 0 || calls[i-1].stage != cl.stage {
+			stages = append(stages, cl.stage)
+		} else {
+			prev := calls[i-1]
+			c.Assert(cl.done >= prev.done, qt.IsTrue, qt.Commentf("call %d: %+v after %+v", i, cl, prev))
+		}
+		if cl.total > 0 {
+			c.Assert(cl.done <= cl.total, qt.IsTrue, qt.Commentf("call %d: %+v", i, cl))
+		}
+		if i == len(calls)-1 || calls[i+1].stage != cl.stage {
+			c.Assert(cl.done, qt.Equals, cl.total, qt.Commentf("last call of stage %s", cl.stage))
+		}
+	}
+	c.Assert(stages, qt.DeepEquals, []string{"collecting packages", "resolving names", "validating services"})
+	c.Assert(calls[0], qt.Equals, call{"collecting packages", 0, 0})
+	c.Assert(calls[1], qt.Equals, call{"collecting packages", 2, 2})
+	c.Assert(calls[2], qt.Equals, call{"resolving names", 0, 2})
+
+	// Parsing works the same without a callback.
+	cfg.Progress = nil
+	_, err = Parse(cfg)
+	c.Assert(err, qt.IsNil)
+}
+
+// writeLargeApp writes an app with many services and APIs to a temporary directory.
+func writeLargeApp(b *testing.B) string {
+	const numSvcs, numAPIs = 50, 20
+	var a txtar.Archive
+	for i := 0; i < numSvcs; i++ {
+		var src strings.Builder
+		fmt.Fprintf(&src, "package svc%d\n\nimport \"context\"\n", i)
+		for j := 0; j < numAPIs; j++ {
+			fmt.Fprintf(&src, `
+type Params%[1]d struct {
+	Name  string
+	Count int
+	Tags  []string
+}
+
+type Response%[1]d struct {
+	Message string
+}
+
+//encore:api public
+func API%[1]d(ctx context.Context, p *Params%[1]d) (*Response%[1]d, error) {
+	return &Response%[1]d{Message: p.Name}, nil
+}
+`, j)
+		}
+		a.Files = append(a.Files, txtar.File{
+			Name: fmt.Sprintf("svc%d/svc.go", i),
+			Data: []byte(src.String()),
+		})
+	}
+	base := b.TempDir()
+	if err := txtar.Write(&a, base); err != nil {
+		b.Fatal(err)
+	}
+	return base
+}
+
+func BenchmarkParse(b *testing.B) {
+	cfg := &Config{AppRoot: writeLargeApp(b), WorkingDir: ".", ModulePath: "test"}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := Parse(cfg); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkValidate(b *testing.B) {
+	cfg := &Config{AppRoot: writeLargeApp(b), WorkingDir: ".", ModulePath: "test"}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if errs := Validate(cfg); errs != nil {
+			b.Fatal(errs)
+		}
+	}
+}
+
 func TestCompile(t *testing.T) {
 	testscript.Run(t, testscript.Params{
 		Dir: "testdata",
@@ -119,51 +683,241 @@ func TestMain(m *testing.M) {
 				os.Stderr.WriteString(err.Error())
 				return 1
 			}
-			modPath := filepath.Join(wd, "go.mod")
-			modData, err := ioutil.ReadFile(modPath)
-			if err != nil {
-				os.Stderr.WriteString(err.Error())
-				return 1
-			}
-			modFile, err := modfile.Parse(modPath, modData, nil)
-			if err != nil {
-				os.Stderr.WriteString(err.Error())
-				return 1
-			}
-
 			cfg := &Config{
 				AppRoot:    wd,
 				WorkingDir: ".",
-				ModulePath: modFile.Module.Mod.Path,
+			}
+			jsonOutput := false
+			for _, arg := range os.Args[1:] {
+				switch {
+				case arg == "-json":
+					jsonOutput = true
+				case arg == "-tests":
+					cfg.ParseTests = true
+				case arg == "-nocomments":
+					cfg.SkipDocComments = true
+				case strings.HasPrefix(arg, "-tags="):
+					cfg.BuildTags = strings.Split(strings.TrimPrefix(arg, "-tags="), ",")
+				case arg == "-detectors":
+					cfg.ResourceDetectors = []ResourceDetector{newKeyspaceDetector()}
+				case strings.HasPrefix(arg, "-mincron="):
+					if cfg.MinCronInterval, err = time.ParseDuration(strings.TrimPrefix(arg, "-mincron=")); err != nil {
+						os.Stderr.WriteString(err.Error())
+						return 1
+					}
+				}
 			}
 			res, err := Parse(cfg)
+			if res != nil {
+				for _, w := range res.Warnings {
+					fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+				}
+			}
 			if err != nil {
 				if list, ok := err.(scanner.ErrorList); ok {
 					for _, e := range list {
 						os.Stderr.WriteString(e.Error())
 					}
 					return 1
+				} else if _, ok := err.(*errlist.List); ok {
+					errlist.Print(os.Stderr, err)
+					return 1
 				}
 				os.Stderr.WriteString(err.Error())
 				return 1
 			}
 
+			// With -json, print the metadata's JSON encoding instead.
+			if jsonOutput {
+				data, err := json.Marshal(res.Meta)
+				if err == nil {
+					// The encoding must round-trip.
+					var md meta.Data
+					if err = json.Unmarshal(data, &md); err == nil && !proto.Equal(&md, res.Meta) {
+						err = fmt.Errorf("metadata does not round-trip through JSON")
+					}
+				}
+				if err == nil {
+					var buf bytes.Buffer
+					if err = json.Indent(&buf, data, "", "  "); err == nil {
+						buf.WriteByte('\n')
+						_, err = buf.WriteTo(os.Stdout)
+					}
+				}
+				if err != nil {
+					os.Stderr.WriteString(err.Error())
+					return 1
+				}
+				return 0
+			}
+
 			for _, svc := range res.Meta.Svcs {
 				fmt.Fprintf(os.Stdout, "svc %s dbs=%s\n", svc.Name, strings.Join(svc.Databases, ","))
+				if svc.ConfigType != "" {
+					fmt.Fprintf(os.Stdout, "svc %s config=%s\n", svc.Name, svc.ConfigType)
+				}
+				if len(svc.Secrets) > 0 {
+					fmt.Fprintf(os.Stdout, "svc %s secrets=%s\n", svc.Name, strings.Join(svc.Secrets, ","))
+				}
+				if c := svc.Cors; c != nil {
+					fmt.Fprintf(os.Stdout, "svc %s cors origins=%s methods=%s headers=%s\n", svc.Name,
+						strings.Join(c.AllowOrigins, ","), strings.Join(c.AllowMethods, ","), strings.Join(c.AllowHeaders, ","))
+				}
+				for _, rpc := range svc.Rpcs {
+					if rpc.Doc != "" {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s doc=%q\n", svc.Name, rpc.Name, rpc.Doc)
+					}
+					if rpc.Streaming {
+						msg := res.Meta.Decls[rpc.StreamMessageSchema.GetNamed().Id].Name
+						fmt.Fprintf(os.Stdout, "rpc %s.%s stream=true msg=%s\n", svc.Name, rpc.Name, msg)
+					}
+					if rpc.MaxBodySize > 0 {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s maxBodySize=%d\n", svc.Name, rpc.Name, rpc.MaxBodySize)
+					}
+					if len(rpc.Tags) > 0 {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s tags=%s\n", svc.Name, rpc.Name, strings.Join(rpc.Tags, ","))
+					}
+					if rl := rpc.RateLimit; rl != nil {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s rateLimit=%d/%ds\n", svc.Name, rpc.Name, rl.Requests, rl.WindowSeconds)
+					}
+					if len(rpc.BuildTags) > 0 {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s buildTags=%s\n", svc.Name, rpc.Name, strings.Join(rpc.BuildTags, ","))
+					}
+				}
 			}
 			for _, svc := range res.App.Services {
 				for _, rpc := range svc.RPCs {
 					fmt.Fprintf(os.Stdout, "rpc %s.%s access=%v raw=%v path=%v\n", svc.Name, rpc.Name, rpc.Access, rpc.Raw, rpc.Path)
+					if rpc.DeclaredAccess != rpc.Access {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s declaredAccess=%q\n", svc.Name, rpc.Name, rpc.DeclaredAccess)
+					}
+					if rpc.Request != nil {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s request ptr=%v\n", svc.Name, rpc.Name, rpc.Request.IsPtr)
+					}
+					if rpc.Response != nil {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s response ptr=%v\n", svc.Name, rpc.Name, rpc.Response.IsPtr)
+					}
+					if rpc.SuccessStatus != 200 {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s status=%d\n", svc.Name, rpc.Name, rpc.SuccessStatus)
+					}
+					if len(rpc.Transforms) > 0 {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s transforms=%s\n", svc.Name, rpc.Name, strings.Join(rpc.Transforms, ","))
+					}
+					if rpc.SvcStruct != nil {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s svcStruct=%s\n", svc.Name, rpc.Name, rpc.SvcStruct.Name)
+					}
+					if rpc.Deprecated {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s deprecated=%q\n", svc.Name, rpc.Name, rpc.DeprecationMessage)
+					}
+					for _, f := range rpc.RequestFields {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s field %s source=%s name=%s\n", svc.Name, rpc.Name, f.Name, f.Source, f.SourceName)
+					}
+					for _, f := range rpc.ResponseFields {
+						fmt.Fprintf(os.Stdout, "rpc %s.%s response field %s source=%s name=%s\n", svc.Name, rpc.Name, f.Name, f.Source, f.SourceName)
+					}
+					for _, param := range []*est.Param{rpc.Request, rpc.Response} {
+						if param == nil {
+							continue
+						}
+						for _, f := range param.Struct.GetFields() {
+							if f.Sensitive {
+								fmt.Fprintf(os.Stdout, "rpc %s.%s sensitive field %s\n", svc.Name, rpc.Name, f.Name)
+							}
+						}
+					}
+					if req := rpc.Request; req != nil && len(req.Type.GetNamed().GetTypeArguments()) > 0 {
+						for _, f := range req.Struct.GetFields() {
+							fmt.Fprintf(os.Stdout, "rpc %s.%s request field %s type=%s\n", svc.Name, rpc.Name, f.Name, typeString(res.App.Decls, f.Typ))
+						}
+					}
+				}
+				if ss := svc.Struct; ss != nil {
+					init := ""
+					if ss.Init != nil {
+						init = ss.Init.Name.Name
+					}
+					var deps []string
+					for _, d := range ss.Deps {
+						deps = append(deps, d.Ident().Name)
+					}
+					fmt.Fprintf(os.Stdout, "svcStruct %s.%s init=%s deps=%s\n", svc.Name, ss.Name, init, strings.Join(deps, ","))
+				}
+				if len(svc.InitOrder) > 0 {
+					var names []string
+					for _, step := range svc.InitOrder {
+						names = append(names, step.Name())
+					}
+					fmt.Fprintf(os.Stdout, "initOrder %s %s\n", svc.Name, strings.Join(names, ","))
+				}
+			}
+			for _, pkg := range res.App.Packages {
+				for _, alias := range pkg.TypeAliases {
+					fmt.Fprintf(os.Stdout, "alias %s.%s\n", pkg.Name, alias.Name)
+				}
+			}
+			for _, svc := range res.App.Services {
+				var names []string
+				for _, rpc := range svc.PublicRPCs() {
+					names = append(names, rpc.Name)
+				}
+				fmt.Fprintf(os.Stdout, "svc %s public=%v rpcs=%s\n", svc.Name, svc.HasPublicAPI(), strings.Join(names, ","))
+			}
+			for _, svc := range res.App.PublicServices() {
+				fmt.Fprintf(os.Stdout, "publicSvc %s\n", svc.Name)
+			}
+			for _, pkg := range res.App.LibraryPackages() {
+				fmt.Fprintf(os.Stdout, "library %s\n", pkg.RelPath)
+			}
+			position := func(pos token.Pos) string {
+				p := res.Position(pos)
+				if rel, err := filepath.Rel(wd, p.Filename); err == nil {
+					p.Filename = filepath.ToSlash(rel)
+				}
+				return p.String()
+			}
+			for _, svc := range res.App.Services {
+				for _, rpc := range svc.RPCs {
+					fmt.Fprintf(os.Stdout, "pos rpc %s.%s %s\n", svc.Name, rpc.Name, position(rpc.Pos))
+				}
+			}
+			for _, job := range res.App.CronJobs {
+				fmt.Fprintf(os.Stdout, "pos cronJob %s %s\n", job.ID, position(job.Pos))
+			}
+			for _, pkg := range res.App.Packages {
+				for _, r := range pkg.Resources {
+					fmt.Fprintf(os.Stdout, "pos resource %s.%s %s\n", pkg.Name, r.Ident().Name, position(r.Pos()))
+				}
+			}
+			for _, svc := range res.App.Services {
+				for _, call := range svc.Calls {
+					fmt.Fprintf(os.Stdout, "call %s -> %s.%s\n", svc.Name, call.Target.Svc.Name, call.Target.Name)
 				}
 			}
 			for _, job := range res.App.CronJobs {
 				fmt.Fprintf(os.Stdout, "cronJob %s title=%q\n", job.ID, job.Title)
 			}
+			usages := res.SecretUsages()
+			var secretNames []string
+			for name := range usages {
+				secretNames = append(secretNames, name)
+			}
+			sort.Strings(secretNames)
+			for _, name := range secretNames {
+				fmt.Fprintf(os.Stdout, "secret %s usages=%d\n", name, len(usages[name]))
+			}
+			for _, topic := range res.Meta.PubsubTopics {
+				msg := res.Meta.Decls[topic.MessageType.GetNamed().Id].Name
+				fmt.Fprintf(os.Stdout, "topic %s msg=%s\n", topic.Name, msg)
+				for _, sub := range topic.Subscriptions {
+					fmt.Fprintf(os.Stdout, "subscription %s.%s\n", topic.Name, sub.Name)
+				}
+			}
 			for _, pkg := range res.App.Packages {
 				for _, res := range pkg.Resources {
 					switch res := res.(type) {
 					case *est.SQLDB:
-						fmt.Fprintf(os.Stdout, "resource %s %s.%s db=%s", res.Type(), pkg.Name, res.Ident().Name, res.DBName)
+						fmt.Fprintf(os.Stdout, "resource %s %s.%s db=%s\n", res.Type(), pkg.Name, res.Ident().Name, res.DBName)
+						fmt.Fprintf(os.Stdout, "db %s migrations=%d\n", res.DBName, len(res.Migrations))
 					default:
 						fmt.Fprintf(os.Stdout, "resource %s %s.%s\n", res.Type(), pkg.Name, res.Ident().Name)
 					}
@@ -171,15 +925,73 @@ func TestMain(m *testing.M) {
 			}
 			return 0
 		},
+		"schema": func() int {
+			wd, err := os.Getwd()
+			if err != nil {
+				os.Stderr.WriteString(err.Error())
+				return 1
+			}
+			res, err := Parse(&Config{AppRoot: wd, WorkingDir: "."})
+			if err != nil {
+				os.Stderr.WriteString(err.Error())
+				return 1
+			}
+			out, err := ExportSchema(res, nil)
+			if err != nil {
+				os.Stderr.WriteString(err.Error())
+				return 1
+			}
+			os.Stdout.Write(append(out, '\n'))
+			return 0
+		},
 	}))
 }
 
+func TestParseFS(t *testing.T) {
+	c := qt.New(t)
+	fsys := fstest.MapFS{
+		"app/go.mod": {Data: []byte("module example.com/app\n")},
+		"app/svc/svc.go": {Data: []byte(`package svc
+
+import (
+	"context"
+
+	"encore.dev/storage/sqldb"
+)
+
+var db = sqldb.Named("svc")
+
+//encore:api public
+func Ping(ctx context.Context) error {
+	return db.QueryRow(ctx, "SELECT 1").Scan(new(int))
+}
+`)},
+		"app/svc/migrations/1_init.up.sql": {Data: []byte("CREATE TABLE foo (id INT);")},
+	}
+
+	res, err := Parse(&Config{AppRoot: "app", WorkingDir: ".", FS: fsys})
+	c.Assert(err, qt.IsNil)
+	c.Assert(res.Meta.ModulePath, qt.Equals, "example.com/app")
+	c.Assert(res.App.Services, qt.HasLen, 1)
+	svc := res.App.Services[0]
+	c.Assert(svc.Name, qt.Equals, "svc")
+	c.Assert(svc.RPCs, qt.HasLen, 1)
+	c.Assert(svc.Root.Files[0].Path, qt.Equals, filepath.Join("app", "svc", "svc.go"))
+	c.Assert(res.Meta.Svcs[0].Migrations, qt.HasLen, 1)
+
+	// Errors are reported with paths in the filesystem.
+	fsys["app/svc/svc.go"].Data = []byte("package svc\n\nfunc Ping(\n")
+	_, err = Parse(&Config{AppRoot: "app", WorkingDir: ".", FS: fsys})
+	c.Assert(err, qt.ErrorMatches, `app/svc/svc.go:3:12: expected '\)', found 'EOF'`)
+}
+
 func TestParseDurationLiteral(t *testing.T) {
 	c := qt.New(t)
 	var tests = []struct {
 		Expr string
 		Want int64
 		Err  string
+		Warn string
 	}{
 		{
 			Expr: "1*cron.Minute",
@@ -189,10 +1001,36 @@ func TestParseDurationLiteral(t *testing.T) {
 			Expr: "(4/2)*cron.Minute",
 			Want: 2 * minute,
 		},
+		{
+			Expr: "5/2*cron.Minute",
+			Want: 2 * minute,
+			Warn: `.+ integer division truncates 5 / 2 to 2 \(remainder 1\)`,
+		},
+		{
+			Expr: "cron.Hour/7",
+			Want: 514,
+			Warn: `.+ integer division truncates cron.Hour / 7 to 514 \(remainder 2\)`,
+		},
+		{
+			Expr: "cron.Hour/4 + cron.Minute/2",
+			Want: 15*minute + 30,
+		},
 		{
 			Expr: "(4-2)*cron.Minute + cron.Hour",
 			Want: 2*minute + hour,
 		},
+		{
+			Expr: "2*cron.Day",
+			Want: 2 * day,
+		},
+		{
+			Expr: "cron.Day/2 - 30*cron.Second",
+			Want: 12*hour - 30,
+		},
+		{
+			Expr: "2*cron.Fortnight",
+			Err:  `.+ unsupported duration value: cron.Fortnight \(expected one of cron.Second, cron.Minute, cron.Hour, cron.Day\)`,
+		},
 		{
 			Expr: "2.3 * 2",
 			Err:  `.+ floating point numbers are not supported .+`,
@@ -228,7 +1066,7 @@ func TestParseDurationLiteral(t *testing.T) {
 			})
 
 			p := &parser{fset: fset, errors: errlist.New(fset)}
-			dur, ok := p.parseCronLiteral(info, x)
+			dur, ok := p.parseCronLiteral(nil, info, x)
 			if test.Err != "" {
 				c.Check(ok, qt.IsFalse)
 				c.Check(p.errors.Err(), qt.IsNotNil)
@@ -238,6 +1076,454 @@ func TestParseDurationLiteral(t *testing.T) {
 				c.Check(dur, qt.Equals, test.Want)
 				c.Check(p.errors.Err(), qt.IsNil)
 			}
+			if test.Warn != "" {
+				c.Check(p.errors.Warnings().Err(), qt.ErrorMatches, test.Warn)
+			} else {
+				c.Check(p.errors.Warnings(), qt.HasLen, 0)
+			}
 		})
 	}
 }
+
+type testResource struct {
+	name string
+	deps []est.Resource
+}
+
+func (r *testResource) Type() est.ResourceType       { return est.SQLDBResource }
+func (r *testResource) File() *est.File              { return nil }
+func (r *testResource) Ident() *ast.Ident            { return ast.NewIdent(r.name) }
+func (r *testResource) Pos() token.Pos               { return token.NoPos }
+func (r *testResource) Dependencies() []est.Resource { return r.deps }
+
+func TestMatchRoute(t *testing.T) {
+	c := qt.New(t)
+
+	svc := &est.Service{Name: "svc"}
+	rpc := func(name, path string, methods ...string) *est.RPC {
+		p, err := paths.Parse(token.NoPos, path)
+		c.Assert(err, qt.IsNil)
+		r := &est.RPC{Svc: svc, Name: name, Path: p, HTTPMethods: methods}
+		svc.RPCs = append(svc.RPCs, r)
+		return r
+	}
+	files := rpc("Files", "/files/*path", "GET")
+	getUser := rpc("GetUser", "/users/:id", "GET")
+	me := rpc("Me", "/users/me", "GET")
+	createUser := rpc("CreateUser", "/users", "POST")
+	webhook := rpc("Webhook", "/webhook/:provider", "*")
+	fallback := rpc("Fallback", "/*rest", "GET")
+	app := &est.Application{Services: []*est.Service{svc}}
+
+	tests := []struct {
+		Method, Path string
+		Want         *est.RPC
+		Params       map[string]string
+	}{
+		{"GET", "/users/123", getUser, map[string]string{"id": "123"}},
+		{"GET", "/users/me", me, map[string]string{}},
+		{"POST", "/users", createUser, map[string]string{}},
+		{"GET", "/files/a/b.txt", files, map[string]string{"path": "a/b.txt"}},
+		{"PUT", "/webhook/github", webhook, map[string]string{"provider": "github"}},
+		{"GET", "/users", fallback, map[string]string{"rest": "users"}},
+		{"DELETE", "/users/123", nil, nil},
+		{"POST", "/users/123", nil, nil},
+	}
+	for _, test := range tests {
+		got, params, ok := app.MatchRoute(test.Method, test.Path)
+		c.Assert(ok, qt.Equals, test.Want != nil, qt.Commentf("%s %s", test.Method, test.Path))
+		c.Assert(got, qt.Equals, test.Want, qt.Commentf("%s %s", test.Method, test.Path))
+		c.Assert(params, qt.DeepEquals, test.Params, qt.Commentf("%s %s", test.Method, test.Path))
+	}
+}
+
+func TestToDOT(t *testing.T) {
+	c := qt.New(t)
+	fsys := fstest.MapFS{
+		"app/go.mod": {Data: []byte("module example.com/app\n")},
+		"app/users/users.go": {Data: []byte(`package users
+
+import "context"
+
+//encore:api public
+func Get(ctx context.Context) error { return nil }
+
+//encore:api public
+func Charged(ctx context.Context) error { return nil }
+`)},
+		"app/billing/billing.go": {Data: []byte(`package billing
+
+import (
+	"context"
+
+	"example.com/app/users"
+)
+
+//encore:api public
+func Charge(ctx context.Context) error {
+	if err := users.Get(ctx); err != nil {
+		return err
+	}
+	if err := users.Get(ctx); err != nil {
+		return err
+	}
+	return users.Charged(ctx)
+}
+`)},
+		"app/orders/orders.go": {Data: []byte(`package orders
+
+import (
+	"context"
+
+	"example.com/app/billing"
+	"example.com/app/users"
+)
+
+//encore:api public
+func Place(ctx context.Context) error {
+	if err := users.Get(ctx); err != nil {
+		return err
+	}
+	return billing.Charge(ctx)
+}
+`)},
+		"app/health/health.go": {Data: []byte(`package health
+
+import "context"
+
+//encore:api public
+func Check(ctx context.Context) error { return nil }
+`)},
+	}
+
+	const want = `digraph services {
+	"billing";
+	"health";
+	"orders";
+	"users";
+	"billing" -> "users" [label="Charged"];
+	"billing" -> "users" [label="Get"];
+	"orders" -> "billing" [label="Charge"];
+	"orders" -> "users" [label="Get"];
+}
+`
+	res, err := Parse(&Config{AppRoot: "app", WorkingDir: ".", FS: fsys})
+	c.Assert(err, qt.IsNil)
+	c.Assert(res.App.ToDOT(), qt.Equals, want)
+
+	// The output does not depend on the order of the calls.
+	for _, svc := range res.App.Services {
+		for i, j := 0, len(svc.Calls)-1; i < j; i, j = i+1, j-1 {
+			svc.Calls[i], svc.Calls[j] = svc.Calls[j], svc.Calls[i]
+		}
+	}
+	c.Assert(res.App.ToDOT(), qt.Equals, want)
+}
+
+func TestSummary(t *testing.T) {
+	c := qt.New(t)
+	fsys := fstest.MapFS{
+		"app/go.mod": {Data: []byte("module example.com/app\n")},
+		"app/users/users.go": {Data: []byte(`package users
+
+import (
+	"context"
+
+	"encore.dev/beta/auth"
+	"encore.dev/cron"
+)
+
+//encore:authhandler
+func Authenticate(ctx context.Context, token string) (auth.UID, error) { return "", nil }
+
+//encore:api public
+func Get(ctx context.Context) error { return nil }
+
+//encore:api auth
+func Update(ctx context.Context) error { return nil }
+
+//encore:api private
+func Cleanup(ctx context.Context) error { return nil }
+
+var _ = cron.NewJob("cleanup", cron.JobConfig{
+	Every:    cron.Hour,
+	Endpoint: Cleanup,
+})
+`)},
+		"app/users/migrations/1_create.up.sql":   {Data: []byte("CREATE TABLE users (id TEXT);\n")},
+		"app/billing/migrations/1_create.up.sql": {Data: []byte("CREATE TABLE invoices (id TEXT);\n")},
+		"app/billing/billing.go": {Data: []byte(`package billing
+
+import (
+	"net/http"
+
+	"encore.dev/pubsub"
+	"encore.dev/storage/sqldb"
+)
+
+var usersDB = sqldb.Named("users")
+var accountsDB = sqldb.Named("users")
+
+type Invoice struct {
+	ID string
+}
+
+var Invoices = pubsub.NewTopic[*Invoice]("invoices", pubsub.TopicConfig{
+	DeliveryGuarantee: pubsub.AtLeastOnce,
+})
+
+//encore:api public raw
+func Webhook(w http.ResponseWriter, req *http.Request) {}
+`)},
+		"app/report/report.go": {Data: []byte(`package report
+
+import "context"
+
+//encore:api private
+func Daily(ctx context.Context) error { return nil }
+`)},
+	}
+
+	res, err := Parse(&Config{AppRoot: "app", WorkingDir: ".", FS: fsys})
+	c.Assert(err, qt.IsNil)
+	c.Assert(res.App.Summary(), qt.Equals, est.AppSummary{
+		Services:         3,
+		Endpoints:        5,
+		PublicEndpoints:  2,
+		AuthEndpoints:    1,
+		PrivateEndpoints: 2,
+		Databases:        2,
+		CronJobs:         1,
+		PubSubTopics:     1,
+	})
+}
+
+func TestAPIHash(t *testing.T) {
+	c := qt.New(t)
+	hash := func(src string) string {
+		fsys := fstest.MapFS{
+			"app/go.mod":         {Data: []byte("module example.com/app\n")},
+			"app/users/users.go": {Data: []byte(src)},
+		}
+		res, err := Parse(&Config{AppRoot: "app", WorkingDir: ".", FS: fsys})
+		c.Assert(err, qt.IsNil)
+		return res.App.APIHash()
+	}
+
+	base := hash(`package users
+
+import "context"
+
+type User struct {
+	ID   string
+	Name string
+}
+
+//encore:api public path=/users/:id
+func Get(ctx context.Context, id string) (*User, error) { return &User{ID: id}, nil }
+`)
+
+	// Documentation, formatting, declaration order, implementation
+	// changes and private APIs do not affect the hash.
+	c.Assert(hash(`package users
+
+import (
+	"context"
+	"strings"
+)
+
+// Get returns the user with the given id.
+//encore:api public path=/users/:id
+func Get(ctx context.Context, id string) (*User, error) {
+	return &User{ID: strings.TrimSpace(id)}, nil
+}
+
+//encore:api private
+func Purge(ctx context.Context) error { return nil }
+
+// User is a user.
+type User struct {
+	ID   string // the user's id
+	Name string
+}
+`), qt.Equals, base)
+
+	// Adding a field to the response changes it.
+	c.Assert(hash(`package users
+
+import "context"
+
+type User struct {
+	ID    string
+	Name  string
+	Email string
+}
+
+//encore:api public path=/users/:id
+func Get(ctx context.Context, id string) (*User, error) { return &User{ID: id}, nil }
+`), qt.Not(qt.Equals), base)
+
+	// So does changing the path.
+	c.Assert(hash(`package users
+
+import "context"
+
+type User struct {
+	ID   string
+	Name string
+}
+
+//encore:api public path=/user/:id
+func Get(ctx context.Context, id string) (*User, error) { return &User{ID: id}, nil }
+`), qt.Not(qt.Equals), base)
+}
+
+func TestProvisioningOrder(t *testing.T) {
+	c := qt.New(t)
+
+	topic := &testResource{name: "topic"}
+	sub := &testResource{name: "sub", deps: []est.Resource{topic}}
+	cluster := &testResource{name: "cluster"}
+	keyspace := &testResource{name: "keyspace", deps: []est.Resource{cluster}}
+	db := &est.SQLDB{DeclName: ast.NewIdent("db"), DBName: "db"}
+
+	res := &Result{App: &est.Application{Packages: []*est.Package{
+		{Resources: []est.Resource{sub, keyspace}},
+		{Resources: []est.Resource{db, cluster, topic}},
+	}}}
+	order, err := res.ProvisioningOrder()
+	c.Assert(err, qt.IsNil)
+
+	var names []string
+	for _, r := range order {
+		names = append(names, r.Ident().Name)
+	}
+	c.Assert(names, qt.DeepEquals, []string{"topic", "sub", "cluster", "keyspace", "db"})
+
+	// Introduce a cycle: topic -> sub -> topic.
+	topic.deps = []est.Resource{sub}
+	_, err = res.ProvisioningOrder()
+	c.Assert(err, qt.ErrorMatches, `resource dependency cycle: sub -> topic -> sub`)
+}
+
+func TestReadModulePath(t *testing.T) {
+	c := qt.New(t)
+	tests := []struct {
+		GoMod string // empty means no go.mod file
+		Want  string
+		Err   string
+	}{
+		{
+			GoMod: "module example.com/app\n\nrequire encore.dev v0.0.6\n",
+			Want:  "example.com/app",
+		},
+		{
+			Err: "could not determine module path: no go.mod file found in .+",
+		},
+		{
+			GoMod: "require encore.dev v0.0.6\n",
+			Err:   "could not determine module path: .+go.mod has no module directive",
+		},
+	}
+
+	for i, test := range tests {
+		dir := t.TempDir()
+		if test.GoMod != "" {
+			err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(test.GoMod), 0644)
+			c.Assert(err, qt.IsNil)
+		}
+		got, err := readModulePath(osFS{}, dir)
+		if test.Err != "" {
+			c.Assert(err, qt.ErrorMatches, test.Err, qt.Commentf("test #%d", i))
+			continue
+		}
+		c.Assert(err, qt.IsNil, qt.Commentf("test #%d", i))
+		c.Assert(got, qt.Equals, test.Want, qt.Commentf("test #%d", i))
+	}
+}
+
+func TestReadWorkspace(t *testing.T) {
+	c := qt.New(t)
+	tests := []struct {
+		Files      map[string]string // empty means no go.work file
+		ModulePath string
+		Want       []workspaceModule
+		Err        string
+	}{
+		{
+			Files: map[string]string{"go.mod": "module example.com/app\n"},
+		},
+		{
+			Files: map[string]string{
+				"go.work":    "go 1.18\n\nuse (\n\t.\n\t./lib\n)\n",
+				"go.mod":     "module example.com/app\n",
+				"lib/go.mod": "module example.com/lib\n",
+			},
+			Want: []workspaceModule{{Dir: ".", Path: "example.com/app"}, {Dir: "lib", Path: "example.com/lib"}},
+		},
+		{
+			Files: map[string]string{
+				"go.work":    "go 1.18\n\nuse (\n\t.\n\t./lib\n)\n",
+				"lib/go.mod": "module example.com/lib\n",
+			},
+			ModulePath: "override",
+			Want:       []workspaceModule{{Dir: ".", Path: "override"}, {Dir: "lib", Path: "example.com/lib"}},
+		},
+		{
+			Files: map[string]string{"go.work": "go 1.18\n\nuse ../other\n"},
+			Err:   `.+go.work: workspace module ../other is outside the app root`,
+		},
+		{
+			Files: map[string]string{"go.work": "go 1.18\n\nuse ./lib\n"},
+			Err:   "could not determine module path: no go.mod file found in .+",
+		},
+		{
+			Files: map[string]string{"go.work": "go 1.18\n"},
+			Err:   `.+go.work: workspace does not use any modules`,
+		},
+	}
+
+	for i, test := range tests {
+		dir := t.TempDir()
+		for name, data := range test.Files {
+			path := filepath.Join(dir, filepath.FromSlash(name))
+			c.Assert(os.MkdirAll(filepath.Dir(path), 0755), qt.IsNil)
+			c.Assert(os.WriteFile(path, []byte(data), 0644), qt.IsNil)
+		}
+		got, err := readWorkspace(osFS{}, dir, test.ModulePath)
+		if test.Err != "" {
+			c.Assert(err, qt.ErrorMatches, test.Err, qt.Commentf("test #%d", i))
+			continue
+		}
+		c.Assert(err, qt.IsNil, qt.Commentf("test #%d", i))
+		c.Assert(got, qt.DeepEquals, test.Want, qt.Commentf("test #%d", i))
+	}
+}
+
+// typeString formats typ for test output, like Go type expressions.
+func typeString(decls []*schema.Decl, typ *schema.Type) string {
+	switch t := typ.Typ.(type) {
+	case *schema.Type_Builtin:
+		return strings.ToLower(t.Builtin.String())
+	case *schema.Type_Named:
+		name := decls[t.Named.Id].Name
+		if args := t.Named.TypeArguments; len(args) > 0 {
+			strs := make([]string, len(args))
+			for i, arg := range args {
+				strs[i] = typeString(decls, arg)
+			}
+			name += "[" + strings.Join(strs, ", ") + "]"
+		}
+		return name
+	case *schema.Type_List:
+		return "[]" + typeString(decls, t.List.Elem)
+	case *schema.Type_Map:
+		return "map[" + typeString(decls, t.Map.Key) + "]" + typeString(decls, t.Map.Value)
+	case *schema.Type_TypeParameter:
+		return decls[t.TypeParameter.DeclId].TypeParams[t.TypeParameter.ParamIdx].Name
+	case *schema.Type_Struct:
+		return "struct"
+	default:
+		return fmt.Sprintf("%T", t)
+	}
+}
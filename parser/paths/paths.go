@@ -44,11 +44,71 @@ func (p *Path) NumParams() int {
 	return n
 }
 
+// Match reports whether the request path (like "/users/123") matches p,
+// and if so returns the values of p's parameters and wildcards keyed by name.
+// A wildcard matches the rest of the path, which may be empty or contain slashes.
+// Values are neither unescaped nor validated against the parameter's type.
+func (p *Path) Match(path string) (params map[string]string, ok bool) {
+	if path == "" || path[0] != '/' {
+		return nil, false
+	}
+	rest := path[1:]
+	params = make(map[string]string, p.NumParams())
+	for i, seg := range p.Segments {
+		if seg.Type == Wildcard {
+			params[seg.Value] = strings.TrimPrefix(rest, "/")
+			return params, true
+		}
+		if i > 0 {
+			if rest == "" || rest[0] != '/' {
+				return nil, false
+			}
+			rest = rest[1:]
+		}
+		val := rest
+		if idx := strings.IndexByte(rest, '/'); idx >= 0 {
+			val = rest[:idx]
+		}
+		rest = rest[len(val):]
+		switch seg.Type {
+		case Literal:
+			if val != seg.Value {
+				return nil, false
+			}
+		case Param:
+			if val == "" {
+				return nil, false
+			}
+			params[seg.Value] = val
+		}
+	}
+	if rest != "" {
+		return nil, false
+	}
+	return params, true
+}
+
+// MoreSpecific reports whether p is more specific than other, for choosing
+// between paths that match the same request path. Comparing their segments
+// in order, a literal is more specific than a parameter, which is more
+// specific than a wildcard. If one path is a prefix of the other in that
+// regard, the shorter path is more specific as the longer one can only match
+// the same request with an empty wildcard.
+func (p *Path) MoreSpecific(other *Path) bool {
+	for i := 0; i < len(p.Segments) && i < len(other.Segments); i++ {
+		if a, b := p.Segments[i].Type, other.Segments[i].Type; a != b {
+			return a < b
+		}
+	}
+	return len(p.Segments) < len(other.Segments)
+}
+
 // Segment represents a parsed path segment.
 type Segment struct {
-	Type      SegmentType
-	Value     string // literal if Type == Literal; name of parameter otherwise
-	ValueType schema.Builtin
+	Type       SegmentType
+	Value      string // literal if Type == Literal; name of parameter otherwise
+	ValueType  schema.Builtin
+	Constraint string // type constraint declared in the path (e.g. "int"); empty if none
 }
 
 func (s *Segment) String() string {
@@ -62,6 +122,15 @@ func (s *Segment) String() string {
 	}
 }
 
+// constraintTypes maps the supported path parameter
+// type constraints to the builtin type they represent.
+var constraintTypes = map[string]schema.Builtin{
+	"int":    schema.Builtin_INT,
+	"bool":   schema.Builtin_BOOL,
+	"uuid":   schema.Builtin_UUID,
+	"string": schema.Builtin_STRING,
+}
+
 // SegmentType represents the different types of path segments recognized by the parser.
 type SegmentType int
 
@@ -101,14 +170,36 @@ func Parse(pos token.Pos, path string) (*Path, error) {
 		}
 
 		typ := Literal
+		valueType := schema.Builtin_STRING
+		var constraint string
 		if val != "" && val[0] == ':' {
 			typ = Param
 			val = val[1:]
+
+			// Parse the optional type constraint (":id<int>" or ":id:int").
+			hasConstraint := false
+			if idx := strings.IndexByte(val, '<'); idx != -1 {
+				if !strings.HasSuffix(val, ">") {
+					return nil, fmt.Errorf("path parameter constraint must be terminated by '>'")
+				}
+				val, constraint = val[:idx], val[idx+1:len(val)-1]
+				hasConstraint = true
+			} else if idx := strings.IndexByte(val, ':'); idx != -1 {
+				val, constraint = val[:idx], val[idx+1:]
+				hasConstraint = true
+			}
+			if hasConstraint {
+				b, ok := constraintTypes[constraint]
+				if !ok {
+					return nil, fmt.Errorf("unsupported path parameter constraint %q (expected int, bool, uuid, or string)", constraint)
+				}
+				valueType = b
+			}
 		} else if val != "" && val[0] == '*' {
 			typ = Wildcard
 			val = val[1:]
 		}
-		segs = append(segs, Segment{Type: typ, Value: val, ValueType: schema.Builtin_STRING})
+		segs = append(segs, Segment{Type: typ, Value: val, ValueType: valueType, Constraint: constraint})
 	}
 
 	// Validate the segments
@@ -121,12 +212,16 @@ func Parse(pos token.Pos, path string) (*Path, error) {
 		case Param:
 			if s.Value == "" {
 				return nil, fmt.Errorf("path parameter must have a name")
+			} else if token.IsKeyword(s.Value) {
+				return nil, fmt.Errorf("path parameter name %q is a reserved Go keyword", s.Value)
 			} else if !token.IsIdentifier(s.Value) {
 				return nil, fmt.Errorf("path parameter must be a valid Go identifier name")
 			}
 		case Wildcard:
 			if s.Value == "" {
 				return nil, fmt.Errorf("wildcard parameter must have a name")
+			} else if token.IsKeyword(s.Value) {
+				return nil, fmt.Errorf("wildcard parameter name %q is a reserved Go keyword", s.Value)
 			} else if !token.IsIdentifier(s.Value) {
 				return nil, fmt.Errorf("wildcard parameter must be a valid Go identifier name")
 			} else if len(segs) > (i + 1) {
@@ -142,6 +237,10 @@ func Parse(pos token.Pos, path string) (*Path, error) {
 // The zero value is ready to use.
 type Set struct {
 	methods map[string]*node
+
+	// shapes maps the shape of each path in the set, with its
+	// parameter and wildcard names removed, to the first path added with it.
+	shapes map[string]*Path
 }
 
 // Add adds a path to the set of paths.
@@ -149,6 +248,20 @@ type Set struct {
 func (s *Set) Add(method string, path *Path) error {
 	if s.methods == nil {
 		s.methods = make(map[string]*node)
+		s.shapes = make(map[string]*Path)
+	}
+
+	// Structurally identical paths must name their parameters the same,
+	// regardless of method, or it is ambiguous which name applies.
+	shape := pathShape(path)
+	if other := s.shapes[shape]; other != nil {
+		for i, seg := range path.Segments {
+			if o := other.Segments[i]; seg.Type == Param && seg.Value != o.Value {
+				return &ConflictError{Path: path, Other: other, Context: fmt.Sprintf(
+					"parameter '%s' is named '%s' in path '%s': structurally identical paths must use the same parameter names",
+					seg.String(), o.String(), other)}
+			}
+		}
 	}
 
 	var candidates []string
@@ -198,9 +311,30 @@ CandidateLoop:
 		}
 	}
 
+	if s.shapes[shape] == nil {
+		s.shapes[shape] = path
+	}
 	return nil
 }
 
+// pathShape returns the string representation of path
+// with the names of its parameters and wildcards removed.
+func pathShape(path *Path) string {
+	var b strings.Builder
+	for _, s := range path.Segments {
+		b.WriteByte('/')
+		switch s.Type {
+		case Param:
+			b.WriteByte(':')
+		case Wildcard:
+			b.WriteByte('*')
+		default:
+			b.WriteString(s.Value)
+		}
+	}
+	return b.String()
+}
+
 func (s *Set) match(path *Path, seg Segment, curr *node) (next *node, err error) {
 	for _, ch := range curr.children {
 		switch ch.s.Type {
@@ -17,18 +17,30 @@ func TestParse(t *testing.T) {
 		Want []Segment
 		Err  string
 	}{
-		{"/foo", []Segment{{Literal, "foo", str}}, ""},
+		{"/foo", []Segment{{Literal, "foo", str, ""}}, ""},
 		{"/foo/", nil, "path cannot contain trailing slash"},
-		{"/foo/bar", []Segment{{Literal, "foo", str}, {Literal, "bar", str}}, ""},
+		{"/foo/bar", []Segment{{Literal, "foo", str, ""}, {Literal, "bar", str, ""}}, ""},
 		{"/foo//bar", nil, "path cannot contain double slash"},
-		{"/:foo/*bar", []Segment{{Param, "foo", str}, {Wildcard, "bar", str}}, ""},
+		{"/:foo/*bar", []Segment{{Param, "foo", str, ""}, {Wildcard, "bar", str, ""}}, ""},
 		{"/:foo/*", nil, "wildcard parameter must have a name"},
 		{"/:foo/*/bar", nil, "wildcard parameter must have a name"},
 		{"/:foo/*bar/baz", nil, "wildcard parameter must be the last path segment"},
 		{"/:foo/*;", nil, "wildcard parameter must be a valid Go identifier name"},
 		{"/:;", nil, "path parameter must be a valid Go identifier name"},
+		{"/:func/:type", nil, `path parameter name "func" is a reserved Go keyword`},
+		{"/foo/:type", nil, `path parameter name "type" is a reserved Go keyword`},
+		{"/foo/*range", nil, `wildcard parameter name "range" is a reserved Go keyword`},
+		{"/foo/:typ", []Segment{{Literal, "foo", str, ""}, {Param, "typ", str, ""}}, ""},
 		{"/\u0000", nil, "invalid path: .+ invalid control character in URL"},
 		{"/foo?bar=baz", nil, `path cannot contain '\?'`},
+		{"/:foo<int>", []Segment{{Param, "foo", schema.Builtin_INT, "int"}}, ""},
+		{"/:foo<uuid>/bar", []Segment{{Param, "foo", schema.Builtin_UUID, "uuid"}, {Literal, "bar", str, ""}}, ""},
+		{"/:foo<float>", nil, `unsupported path parameter constraint "float" \(expected int, bool, uuid, or string\)`},
+		{"/:foo<int", nil, `path parameter constraint must be terminated by '>'`},
+		{"/user/:id:int", []Segment{{Literal, "user", str, ""}, {Param, "id", schema.Builtin_INT, "int"}}, ""},
+		{"/:flag:bool/bar", []Segment{{Param, "flag", schema.Builtin_BOOL, "bool"}, {Literal, "bar", str, ""}}, ""},
+		{"/:foo:float", nil, `unsupported path parameter constraint "float" \(expected int, bool, uuid, or string\)`},
+		{"/:foo:", nil, `unsupported path parameter constraint "" \(expected int, bool, uuid, or string\)`},
 	}
 
 	for _, test := range tests {
@@ -62,7 +74,9 @@ func TestAdd(t *testing.T) {
 		{"POST", "/foo/bar", ``},
 		{"POST", "/foo/:bar", `.+ /foo/:bar and /foo/bar: cannot combine parameter ':bar' with path '/foo/bar'`},
 		{"POST", "/moo/:bar", ``},
-		{"POST", "/moo/:baz", `.+ /moo/:baz and /moo/:bar: duplicate path`},
+		{"POST", "/moo/:baz", `.+ /moo/:baz and /moo/:bar: parameter ':baz' is named ':bar' in path '/moo/:bar': structurally identical paths must use the same parameter names`},
+		{"GET", "/moo/:baz", `.+ /moo/:baz and /moo/:bar: parameter ':baz' is named ':bar' in path '/moo/:bar': .+`},
+		{"GET", "/moo/:bar", ``},
 		{"POST", "/moo/:baz/test", ``},
 		{"POST", "/moo/:baa/*wild", `.+ /moo/:baa/\*wild and /moo/:baz/test: cannot combine wildcard '\*wild' with path '/moo/:baz/test'`},
 		{"GET", "/moo/:baa/*wild", ``},
@@ -83,3 +97,63 @@ func TestAdd(t *testing.T) {
 		}
 	}
 }
+
+func TestMatch(t *testing.T) {
+	c := qt.New(t)
+
+	tests := []struct {
+		Path   string
+		Req    string
+		Params map[string]string // nil if no match
+	}{
+		{"/foo", "/foo", map[string]string{}},
+		{"/foo", "/bar", nil},
+		{"/foo", "/foo/", nil},
+		{"/foo", "/foo/bar", nil},
+		{"/foo/bar", "/foo", nil},
+		{"/users/:id", "/users/123", map[string]string{"id": "123"}},
+		{"/users/:id", "/users/", nil},
+		{"/users/:id", "/users/123/posts", nil},
+		{"/users/:id<int>/posts/:post", "/users/1/posts/hello", map[string]string{"id": "1", "post": "hello"}},
+		{"/files/*path", "/files/a/b/c.txt", map[string]string{"path": "a/b/c.txt"}},
+		{"/files/*path", "/files/", map[string]string{"path": ""}},
+		{"/files/*path", "/files", map[string]string{"path": ""}},
+		{"/files/*path", "/other/a", nil},
+		{"/:id/*rest", "/1/x/y", map[string]string{"id": "1", "rest": "x/y"}},
+		{"/foo", "foo", nil},
+	}
+	for _, test := range tests {
+		p, err := Parse(0, test.Path)
+		c.Assert(err, qt.IsNil)
+		params, ok := p.Match(test.Req)
+		c.Assert(ok, qt.Equals, test.Params != nil, qt.Commentf("%s %s", test.Path, test.Req))
+		if ok {
+			c.Assert(params, qt.DeepEquals, test.Params, qt.Commentf("%s %s", test.Path, test.Req))
+		}
+	}
+}
+
+func TestMoreSpecific(t *testing.T) {
+	c := qt.New(t)
+
+	tests := []struct {
+		A, B string
+		Want bool
+	}{
+		{"/foo/bar", "/foo/:id", true},
+		{"/foo/:id", "/foo/bar", false},
+		{"/foo/:id", "/foo/*rest", true},
+		{"/foo/bar", "/*rest", true},
+		{"/*rest", "/foo/bar", false},
+		{"/files", "/files/*path", true},
+		{"/files/*path", "/files", false},
+		{"/foo", "/foo", false},
+	}
+	for _, test := range tests {
+		a, err := Parse(0, test.A)
+		c.Assert(err, qt.IsNil)
+		b, err := Parse(0, test.B)
+		c.Assert(err, qt.IsNil)
+		c.Assert(a.MoreSpecific(b), qt.Equals, test.Want, qt.Commentf("%s vs %s", test.A, test.B))
+	}
+}
@@ -0,0 +1,286 @@
+package parser
+
+import (
+	"go/ast"
+	"go/token"
+	"go/types"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+
+	"encr.dev/parser/dnsname"
+	"encr.dev/parser/est"
+)
+
+// deliveryGuarantees maps the pubsub delivery guarantee
+// constants to their names.
+var deliveryGuarantees = map[string]string{
+	"AtLeastOnce": "at-least-once",
+	"ExactlyOnce": "exactly-once",
+}
+
+// parsePubSubTopic parses a "var x = pubsub.NewTopic[T](name, cfg)" declaration.
+func (p *parser) parsePubSubTopic(pkg *est.Package, file *est.File, decl *ast.Ident, call *ast.CallExpr, typeArg ast.Expr) {
+	if typeArg == nil {
+		p.errf(call.Pos(), "pubsub.NewTopic must be called with the message type as type argument (like pubsub.NewTopic[Event](...))")
+		return
+	} else if len(call.Args) != 2 {
+		p.errf(call.Pos(), "pubsub.NewTopic must be called with a topic name and a pubsub.TopicConfig")
+		return
+	}
+
+	name, ok := p.parseResourceName(call.Args[0], "pubsub.NewTopic", "topic name")
+	if !ok {
+		return
+	}
+
+	info := p.names[pkg].Files[file]
+	topic := &est.PubSubTopic{
+		DeclFile:          file,
+		DeclName:          decl,
+		Name:              name,
+		DeliveryGuarantee: deliveryGuarantees["AtLeastOnce"],
+		CallPos:           call.Pos(),
+	}
+
+	cfg, ok := call.Args[1].(*ast.CompositeLit)
+	if !ok {
+		p.errf(call.Args[1].Pos(), "pubsub.NewTopic must be called with a pubsub.TopicConfig literal")
+		return
+	}
+	for _, elt := range cfg.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			p.errf(elt.Pos(), "pubsub.TopicConfig must be declared with field names")
+			return
+		}
+		key, _ := kv.Key.(*ast.Ident)
+		if key == nil || key.Name != "DeliveryGuarantee" {
+			p.errf(kv.Key.Pos(), "unknown pubsub.TopicConfig field %s", types.ExprString(kv.Key))
+			return
+		}
+		imp, obj := pkgObj(info, kv.Value)
+		dg, ok := deliveryGuarantees[obj]
+		if imp != pubsubImportPath || !ok {
+			p.errf(kv.Value.Pos(), "pubsub.TopicConfig: DeliveryGuarantee must be one of pubsub.AtLeastOnce and pubsub.ExactlyOnce")
+			return
+		}
+		topic.DeliveryGuarantee = dg
+	}
+
+	topic.MessageType = p.resolveType(pkg, file, typeArg, nil)
+	if named := p.unalias(topic.MessageType).GetNamed(); named == nil || p.decls[named.Id].Type.GetStruct() == nil {
+		p.errf(typeArg.Pos(), "pubsub topic message type must be a named struct type, got %s", types.ExprString(typeArg))
+		return
+	}
+
+	pkg.Resources = append(pkg.Resources, topic)
+}
+
+// parsePubSubSubscription parses a "var x = pubsub.NewSubscription(topic, name, cfg)" declaration.
+// The topic and the handler are validated by validatePubSub.
+func (p *parser) parsePubSubSubscription(pkg *est.Package, file *est.File, decl *ast.Ident, call *ast.CallExpr) {
+	if len(call.Args) != 3 {
+		p.errf(call.Pos(), "pubsub.NewSubscription must be called with a topic, a subscription name and a pubsub.SubscriptionConfig")
+		return
+	}
+
+	name, ok := p.parseResourceName(call.Args[1], "pubsub.NewSubscription", "subscription name")
+	if !ok {
+		return
+	}
+
+	sub := &est.PubSubSubscription{
+		DeclFile:  file,
+		DeclName:  decl,
+		Name:      name,
+		CallPos:   call.Pos(),
+		TopicExpr: call.Args[0],
+	}
+
+	cfg, ok := call.Args[2].(*ast.CompositeLit)
+	if !ok {
+		p.errf(call.Args[2].Pos(), "pubsub.NewSubscription must be called with a pubsub.SubscriptionConfig literal")
+		return
+	}
+	for _, elt := range cfg.Elts {
+		kv, ok := elt.(*ast.KeyValueExpr)
+		if !ok {
+			p.errf(elt.Pos(), "pubsub.SubscriptionConfig must be declared with field names")
+			return
+		}
+		key, _ := kv.Key.(*ast.Ident)
+		if key == nil || key.Name != "Handler" {
+			p.errf(kv.Key.Pos(), "unknown pubsub.SubscriptionConfig field %s", types.ExprString(kv.Key))
+			return
+		}
+		id, _ := kv.Value.(*ast.Ident)
+		if id == nil {
+			p.errf(kv.Value.Pos(), "pubsub.SubscriptionConfig: Handler must be a function declared in the same package")
+			return
+		}
+		if d := p.names[pkg].Decls[id.Name]; d != nil && d.Type == token.FUNC {
+			sub.Handler = d.Func
+		} else {
+			p.errf(kv.Value.Pos(), "pubsub.SubscriptionConfig: Handler must be a function declared in the same package")
+			return
+		}
+	}
+	if sub.Handler == nil {
+		p.errf(cfg.Pos(), "pubsub.SubscriptionConfig: Handler is required")
+		return
+	}
+
+	pkg.Resources = append(pkg.Resources, sub)
+}
+
+// parseResourceName parses the name of a resource, which must be
+// a string literal that is a valid DNS label.
+func (p *parser) parseResourceName(expr ast.Expr, fn, what string) (name string, ok bool) {
+	if bl, ok := expr.(*ast.BasicLit); ok && bl.Kind == token.STRING {
+		name, _ = strconv.Unquote(bl.Value)
+	}
+	if name == "" {
+		p.errf(expr.Pos(), "%s: %s must be a non-empty string literal", fn, what)
+		return "", false
+	} else if err := dnsname.DNS1035Label(name); err != nil {
+		p.errf(expr.Pos(), "%s: %s must consist of lower case alphanumeric characters or '-', "+
+			"start with an alphabetic character, and end with an alphanumeric character", fn, what)
+		return "", false
+	}
+	return name, true
+}
+
+// validatePubSub ensures topic names are unique, resolves the topic each
+// subscription refers to and validates the subscription handlers.
+func (p *parser) validatePubSub() {
+	// Calls not recorded as resources are made at invalid call sites.
+	declared := make(map[token.Pos]bool)
+	for _, pkg := range p.pkgs {
+		for _, res := range pkg.Resources {
+			declared[res.Pos()] = true
+		}
+	}
+	for _, pkg := range p.pkgs {
+		for _, file := range pkg.Files {
+			info := p.names[pkg].Files[file]
+			ast.Inspect(file.AST, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok || declared[call.Pos()] {
+					return true
+				}
+				fun := call.Fun
+				if idx, ok := fun.(*ast.IndexExpr); ok {
+					fun = idx.X
+				}
+				if imp, obj := pkgObj(info, fun); imp == pubsubImportPath && (obj == "NewTopic" || obj == "NewSubscription") {
+					p.errf(call.Pos(), "pubsub.%s must be called as a package-level variable declaration", obj)
+				}
+				return true
+			})
+		}
+	}
+
+	topics := make(map[string]*est.PubSubTopic)
+	for _, pkg := range p.pkgs {
+		for _, res := range pkg.Resources {
+			if topic, ok := res.(*est.PubSubTopic); ok {
+				if prev := topics[topic.Name]; prev != nil {
+					p.errf(topic.Pos(), "pubsub topic %s declared twice (previous declaration at %s)",
+						topic.Name, p.fset.Position(prev.Pos()))
+					continue
+				}
+				topics[topic.Name] = topic
+			}
+		}
+	}
+
+	for _, pkg := range p.pkgs {
+		for _, res := range pkg.Resources {
+			sub, ok := res.(*est.PubSubSubscription)
+			if !ok {
+				continue
+			}
+			sub.Topic = p.resolveTopic(pkg, sub)
+			if sub.Topic == nil {
+				p.errf(sub.TopicExpr.Pos(), "pubsub.NewSubscription must be called with a topic declared with pubsub.NewTopic, got %s",
+					types.ExprString(sub.TopicExpr))
+				continue
+			}
+
+			topic := sub.Topic
+			dup := false
+			for _, sub2 := range topic.Subscriptions {
+				if sub2.Name == sub.Name {
+					p.errf(sub.Pos(), "subscription %s to pubsub topic %s declared twice (previous declaration at %s)",
+						sub.Name, topic.Name, p.fset.Position(sub2.Pos()))
+					dup = true
+				}
+			}
+			if dup {
+				continue
+			}
+			topic.Subscriptions = append(topic.Subscriptions, sub)
+			p.validateSubscriptionHandler(pkg, sub)
+		}
+	}
+}
+
+// resolveTopic resolves the topic a subscription refers to,
+// either by name within the same package or by pkg.Name.
+func (p *parser) resolveTopic(pkg *est.Package, sub *est.PubSubSubscription) *est.PubSubTopic {
+	info := p.names[pkg].Files[sub.DeclFile]
+	var (
+		topicPkg *est.Package
+		name     string
+	)
+	switch x := sub.TopicExpr.(type) {
+	case *ast.Ident:
+		if ri := info.Idents[x]; ri != nil && ri.Package {
+			topicPkg, name = pkg, x.Name
+		}
+	case *ast.SelectorExpr:
+		if path, obj := pkgObj(info, x); path != "" {
+			topicPkg, name = p.pkgMap[path], obj
+		}
+	}
+	if topicPkg == nil {
+		return nil
+	}
+	for _, res := range topicPkg.Resources {
+		if topic, ok := res.(*est.PubSubTopic); ok && topic.Ident().Name == name {
+			return topic
+		}
+	}
+	return nil
+}
+
+// validateSubscriptionHandler ensures a subscription's handler has the signature
+// func(context.Context, *T) error where T is the topic's message type.
+func (p *parser) validateSubscriptionHandler(pkg *est.Package, sub *est.PubSubSubscription) {
+	fd := sub.Handler
+	file := p.names[pkg].Decls[fd.Name.Name].File
+	info := p.names[pkg].Files[file]
+
+	valid := func() bool {
+		params, results := fd.Type.Params.List, fd.Type.Results
+		if len(params) != 2 || len(params[0].Names) > 1 || len(params[1].Names) > 1 {
+			return false
+		} else if imp, obj := pkgObj(info, params[0].Type); imp != "context" || obj != "Context" {
+			return false
+		} else if _, ok := params[1].Type.(*ast.StarExpr); !ok {
+			return false
+		} else if results == nil || len(results.List) != 1 || len(results.List[0].Names) > 1 {
+			return false
+		} else if id, ok := results.List[0].Type.(*ast.Ident); !ok || id.Name != "error" {
+			return false
+		}
+		msgType := p.resolveType(pkg, file, params[1].Type, nil)
+		return proto.Equal(msgType, sub.Topic.MessageType)
+	}
+	if !valid() {
+		msgName := p.decls[sub.Topic.MessageType.GetNamed().Id].Name
+		p.errf(fd.Name.Pos(), "subscription %s handler %s must have the signature func(context.Context, *%s) error",
+			sub.Name, fd.Name.Name, msgName)
+	}
+}
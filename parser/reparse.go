@@ -0,0 +1,135 @@
+package parser
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"go/ast"
+	"go/token"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"encr.dev/parser/est"
+)
+
+// Reparse parses the application like Parse, after the files at
+// changedPaths have been added, modified or removed since prev was parsed.
+// It is intended for editors and other tools that parse an application
+// repeatedly as it is being edited.
+//
+// The packages in directories without changes are not read and parsed
+// again: their syntax trees are reused from prev. Everything else,
+// including resolving names and validating the application, is done for
+// all packages like Parse does, so that changes affecting other packages,
+// such as to a type used by another service's API, are taken into account.
+//
+// The paths are the paths of the changed files in the same form as
+// est.File.Path, or of changed directories. Changes to a go.mod or go.work
+// file cause the whole application to be parsed again, as do differences
+// between cfg and the configuration prev was parsed with that affect how
+// files are parsed. If prev is nil Reparse is equivalent to Parse.
+//
+// The returned result has a copy of prev's file set, with the files that
+// were parsed again added to it, so positions of reused syntax trees remain
+// valid while prev's file set is left unchanged.
+func Reparse(prev *Result, changedPaths []string, cfg *Config) (*Result, error) {
+	p, err := newParser(context.Background(), cfg)
+	if err != nil {
+		return nil, err
+	}
+	if prev != nil && prev.cfg != nil && canReuseFiles(prev.cfg, p.cfg) {
+		if reuse := reusablePackages(prev, changedPaths); reuse != nil {
+			fset, err := copyFileSet(prev.FileSet)
+			if err != nil {
+				return nil, err
+			}
+			p.fset = fset
+			p.reuse = reuse
+		}
+	}
+	return p.Parse()
+}
+
+// canReuseFiles reports whether the files parsed with the configuration
+// prev can be reused when parsing with cfg.
+func canReuseFiles(prev, cfg *Config) bool {
+	return prev.AppRoot == cfg.AppRoot &&
+		prev.ModulePath == cfg.ModulePath &&
+		prev.SkipDocComments == cfg.SkipDocComments &&
+		prev.ParseTests == cfg.ParseTests &&
+		prev.IncludeVendor == cfg.IncludeVendor &&
+		strings.Join(prev.BuildTags, ",") == strings.Join(cfg.BuildTags, ",")
+}
+
+// reusablePackages returns the packages of prev whose directories are
+// not affected by changedPaths, keyed by directory. It returns nil if
+// no package can be reused.
+func reusablePackages(prev *Result, changedPaths []string) map[string]*est.Package {
+	changed := make(map[string]bool, 2*len(changedPaths))
+	for _, p := range changedPaths {
+		switch filepath.Base(p) {
+		case "go.mod", "go.work":
+			return nil
+		}
+		p = filepath.Clean(p)
+		changed[p] = true
+		changed[filepath.Dir(p)] = true
+	}
+
+	reuse := make(map[string]*est.Package, len(prev.App.Packages))
+	for _, pkg := range prev.App.Packages {
+		if !changed[filepath.Clean(pkg.Dir)] {
+			reuse[pkg.Dir] = pkg
+		}
+	}
+	if len(reuse) == 0 {
+		return nil
+	}
+	return reuse
+}
+
+// copyFileSet returns a file set with the same files as fset.
+func copyFileSet(fset *token.FileSet) (*token.FileSet, error) {
+	var buf bytes.Buffer
+	if err := fset.Write(gob.NewEncoder(&buf).Encode); err != nil {
+		return nil, err
+	}
+	cp := token.NewFileSet()
+	if err := cp.Read(gob.NewDecoder(&buf).Decode); err != nil {
+		return nil, err
+	}
+	return cp, nil
+}
+
+// reusePackage returns a package with the files of pkg, parsed by
+// a previous parse, for the package at relPath in the module with the
+// given import path. The files refer to their copies in fset, the copy
+// of the previous parse's file set. The results of analyzing pkg are not copied.
+func reusePackage(fset *token.FileSet, pkg *est.Package, relPath, rootImportPath string) *est.Package {
+	cp := &est.Package{
+		AST:        pkg.AST,
+		Name:       pkg.Name,
+		Doc:        pkg.Doc,
+		ImportPath: path.Clean(path.Join(rootImportPath, relPath)),
+		RelPath:    path.Clean(relPath),
+		Dir:        pkg.Dir,
+	}
+	for _, f := range pkg.Files {
+		file := &est.File{
+			Name:       f.Name,
+			Pkg:        cp,
+			Path:       f.Path,
+			AST:        f.AST,
+			Token:      fset.File(token.Pos(f.Token.Base())),
+			Contents:   f.Contents,
+			References: make(map[ast.Node]*est.Node),
+			BuildTags:  f.BuildTags,
+		}
+		cp.Files = append(cp.Files, file)
+		if strings.HasSuffix(f.Name, "_test.go") {
+			cp.TestFiles = append(cp.TestFiles, file)
+		}
+	}
+	return cp
+}
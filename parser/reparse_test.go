@@ -0,0 +1,123 @@
+package parser
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/rogpeppe/go-internal/txtar"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestReparse(t *testing.T) {
+	const base = `
+-- go.mod --
+module test
+-- shared/shared.go --
+package shared
+
+type User struct {
+	Name string
+}
+-- svc/svc.go --
+package svc
+
+import (
+	"context"
+
+	"test/shared"
+)
+
+//encore:api public
+func Create(ctx context.Context, u *shared.User) error { return nil }
+-- other/other.go --
+package other
+
+import "context"
+
+//encore:api public
+func Ping(ctx context.Context) error { return nil }
+`
+
+	write := func(c *qt.C, dir, archive string) (changed []string) {
+		for _, f := range txtar.Parse([]byte(archive)).Files {
+			path := filepath.Join(dir, f.Name)
+			c.Assert(os.MkdirAll(filepath.Dir(path), 0755), qt.IsNil)
+			c.Assert(os.WriteFile(path, f.Data, 0644), qt.IsNil)
+			changed = append(changed, path)
+		}
+		return changed
+	}
+	setup := func(c *qt.C) (*Config, *Result) {
+		dir := c.TempDir()
+		write(c, dir, base)
+		cfg := &Config{AppRoot: dir, WorkingDir: ".", ModulePath: "test"}
+		res, err := Parse(cfg)
+		c.Assert(err, qt.IsNil)
+		return cfg, res
+	}
+	fileAST := func(res *Result, pkgPath string) interface{} {
+		for _, pkg := range res.App.Packages {
+			if pkg.RelPath == pkgPath {
+				return pkg.Files[0].AST
+			}
+		}
+		return nil
+	}
+
+	c := qt.New(t)
+
+	c.Run("valid change", func(c *qt.C) {
+		cfg, prev := setup(c)
+		changed := write(c, cfg.AppRoot, `
+-- svc/svc.go --
+package svc
+
+import (
+	"context"
+
+	"test/shared"
+)
+
+//encore:api public
+func Create(ctx context.Context, u *shared.User) error { return nil }
+
+//encore:api public
+func Delete(ctx context.Context, u *shared.User) error { return nil }
+`)
+		base := prev.FileSet.Base()
+		res, err := Reparse(prev, changed, cfg)
+		c.Assert(err, qt.IsNil)
+		c.Assert(prev.FileSet.Base(), qt.Equals, base)
+		c.Assert(res.FileSet, qt.Not(qt.Equals), prev.FileSet)
+		c.Assert(fileAST(res, "other"), qt.Equals, fileAST(prev, "other"))
+		c.Assert(fileAST(res, "svc"), qt.Not(qt.Equals), fileAST(prev, "svc"))
+
+		full, err := Parse(cfg)
+		c.Assert(err, qt.IsNil)
+		c.Assert(proto.Equal(res.Meta, full.Meta), qt.IsTrue)
+	})
+
+	c.Run("dependents are validated", func(c *qt.C) {
+		cfg, prev := setup(c)
+		changed := write(c, cfg.AppRoot, `
+-- shared/shared.go --
+package shared
+
+type User struct {
+	Name   string
+	Events chan string
+}
+`)
+		_, err := Reparse(prev, changed, cfg)
+		c.Assert(err, qt.ErrorMatches, `(?s).*cannot use channel types in Encore schema definitions.*`)
+	})
+
+	c.Run("go.mod changes", func(c *qt.C) {
+		cfg, prev := setup(c)
+		res, err := Reparse(prev, []string{filepath.Join(cfg.AppRoot, "go.mod")}, cfg)
+		c.Assert(err, qt.IsNil)
+		c.Assert(fileAST(res, "other"), qt.Not(qt.Equals), fileAST(prev, "other"))
+	})
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/fatih/structtag"
 
 	"encr.dev/parser/est"
+	"encr.dev/parser/internal/names"
 	"encr.dev/pkg/errlist"
 	schema "encr.dev/proto/encore/parser/schema/v1"
 )
@@ -85,6 +86,7 @@ func (p *parser) resolveType(pkg *est.Package, file *est.File, expr ast.Expr, ty
 				p.err(field.Pos(), "cannot use anonymous fields in Encore struct types")
 			}
 			opts := p.parseStructTag(field.Tag, typ)
+			_, isPtr := field.Type.(*ast.StarExpr)
 
 			// Validate the names to make sure we don't have any name collisions
 			if js := opts.JSONName; js != "" {
@@ -120,6 +122,8 @@ func (p *parser) resolveType(pkg *est.Package, file *est.File, expr ast.Expr, ty
 					QueryStringName: opts.QueryStringName,
 					Tags:            schemaTags(opts.Tags),
 					RawTag:          opts.RawTag,
+					Pointer:         isPtr,
+					Sensitive:       opts.Sensitive,
 				}
 				if f.QueryStringName == "" {
 					f.QueryStringName = SnakeCase(f.Name)
@@ -163,6 +167,40 @@ func (p *parser) resolveType(pkg *est.Package, file *est.File, expr ast.Expr, ty
 	panic(errlist.Bailout{})
 }
 
+// declareAlias records decl as the declaration of a type alias ("type A = B")
+// if spec declares one. Like other named types, aliases are kept as declarations
+// in the schema so that documentation and generated code refer to them by name.
+// Checks that depend on the type an alias denotes see through it with unalias.
+func (p *parser) declareAlias(pkg *est.Package, d *names.PkgDecl, spec *ast.TypeSpec, decl *schema.Decl) {
+	if !spec.Assign.IsValid() {
+		return
+	}
+	alias := &est.TypeAlias{Name: d.Name, Doc: d.Doc, Spec: spec, Decl: decl}
+	p.aliases[decl.Id] = alias
+	pkg.TypeAliases = append(pkg.TypeAliases, alias)
+}
+
+// checkRecursiveAlias reports an error if decl, whose type is being resolved,
+// is the declaration of a type alias. Unlike other named types,
+// aliases cannot refer to themselves.
+func (p *parser) checkRecursiveAlias(decl *schema.Decl) {
+	if decl.Type == nil && p.aliases[decl.Id] != nil {
+		p.errf(p.aliases[decl.Id].Spec.Pos(), "invalid recursive type alias %s", decl.Name)
+		p.abort()
+	}
+}
+
+// unalias returns the type denoted by typ, following references to type aliases.
+func (p *parser) unalias(typ *schema.Type) *schema.Type {
+	for {
+		named := typ.GetNamed()
+		if named == nil || p.aliases[named.Id] == nil {
+			return typ
+		}
+		typ = p.decls[named.Id].Type
+	}
+}
+
 func (p *parser) parseEncoreBuiltin(pos token.Pos, pkgPath, name string) *schema.Type {
 	switch {
 	case pkgPath == uuidImportPath && name == "UUID":
@@ -189,6 +227,13 @@ type structFieldOptions struct {
 	QueryStringName string
 	// Optional is true if there is an `encore:"optional"` tag
 	Optional bool
+	// Sensitive is true if there is an `encore:"sensitive"` tag,
+	// marking the field's value to be redacted when logged or traced.
+	Sensitive bool
+	// Source is "header" or "query" if the field declares its source
+	// with an `encore:"header:Name"` or `encore:"query:name"` tag.
+	// The equivalent header or query tag is added to Tags.
+	Source string
 	// Tags contains parsed struct field tags
 	Tags []*structtag.Tag
 	// RawTag contains the raw unparsed struct field tag (if any)
@@ -228,9 +273,24 @@ func (p *parser) parseStructTag(tag *ast.BasicLit, resolvedType *schema.Type) st
 	if enc, _ := tags.Get("encore"); enc != nil {
 		ops := append([]string{enc.Name}, enc.Options...)
 		for _, o := range ops {
-			switch o {
-			case "optional":
+			key, name, _ := strings.Cut(o, ":")
+			switch {
+			case o == "optional":
 				opts.Optional = true
+			case o == "sensitive":
+				opts.Sensitive = true
+			case key == "header" || key == "query":
+				if opts.Source != "" {
+					p.errf(tag.Pos(), "encore struct tag cannot specify both %s and %s", opts.Source, key)
+					continue
+				}
+				for _, k := range []string{"header", "query", "qs"} {
+					if t, _ := tags.Get(k); t != nil {
+						p.errf(tag.Pos(), "encore struct tag option %s cannot be combined with a %s tag", key, k)
+					}
+				}
+				opts.Source = key
+				opts.Tags = append(opts.Tags, &structtag.Tag{Key: key, Name: name})
 			default:
 				p.errf(tag.Pos(), "invalid encore struct tag option: %s", o)
 			}
@@ -247,7 +307,7 @@ func (p *parser) parseStructTag(tag *ast.BasicLit, resolvedType *schema.Type) st
 		}
 	}
 
-	if header, _ := tags.Get("header"); header != nil {
+	if header := findTag(opts.Tags, "header"); header != nil {
 		// Due to way headers are encoded, the RFC specifies that multiple values for the same
 		// header should be encoded as a comma-separated list.
 		//
@@ -288,9 +348,63 @@ func (p *parser) parseStructTag(tag *ast.BasicLit, resolvedType *schema.Type) st
 		}
 	}
 
+	// Sensitive values are redacted as a whole, which is unambiguous only
+	// for values that are not aggregates of other fields: for structs and
+	// maps it would be unclear whether their contents are redacted.
+	if opts.Sensitive {
+		typ := resolvedType
+		if list := typ.GetList(); list != nil {
+			typ = list.Elem
+		}
+		if typ.GetTypeParameter() != nil {
+			p.errf(tag.Pos(), "sensitive tags are not allowed on generic fields")
+		} else if !p.isScalarType(typ) {
+			p.errf(tag.Pos(), "sensitive tags can only be used on built in types or slices of built in types"+
+				"\n\tnote: to redact parts of a struct, mark its fields as sensitive instead")
+		}
+	}
+
+	// Query string parameters are decoded from strings, so like headers
+	// they are limited to scalar types, but may be repeated.
+	if query := findTag(opts.Tags, "query", "qs"); query != nil && query.Name != "-" {
+		typ := resolvedType
+		if list := typ.GetList(); list != nil {
+			typ = list.Elem
+		}
+		if !p.isScalarType(typ) {
+			p.errf(tag.Pos(), "query string parameters must be built in types or slices of built in types")
+		}
+	}
+
 	return opts
 }
 
+// findTag returns the first tag with one of the given keys, or nil if there is none.
+func findTag(tags []*structtag.Tag, keys ...string) *structtag.Tag {
+	for _, t := range tags {
+		for _, k := range keys {
+			if t.Key == k {
+				return t
+			}
+		}
+	}
+	return nil
+}
+
+// isScalarType reports whether typ is a builtin type
+// or a named type declared as a builtin type.
+func (p *parser) isScalarType(typ *schema.Type) bool {
+	switch t := p.unalias(typ).GetTyp().(type) {
+	case *schema.Type_Builtin:
+		return true
+	case *schema.Type_Named:
+		_, ok := p.unalias(p.decls[t.Named.Id].Type).GetTyp().(*schema.Type_Builtin)
+		return ok
+	default:
+		return false
+	}
+}
+
 func getField(list *ast.FieldList, n int) (field *ast.Field, name string) {
 	i := 0
 	for _, f := range list.List {
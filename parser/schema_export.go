@@ -0,0 +1,354 @@
+package parser
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"encr.dev/parser/est"
+	schema "encr.dev/proto/encore/parser/schema/v1"
+)
+
+// ExportedSchema is a normalized, machine-readable description
+// of the request and response types of an application's APIs.
+type ExportedSchema struct {
+	Endpoints []*ExportedEndpoint `json:"endpoints"`
+	// Types are the named types referenced by the endpoints,
+	// directly or indirectly, sorted by name. Each type is described once,
+	// even if it is shared by several endpoints or services,
+	// and is referenced by its qualified name.
+	Types []*ExportedType `json:"types"`
+}
+
+// ExportedEndpoint describes the payload types of a single API.
+type ExportedEndpoint struct {
+	Service  string         `json:"service"`
+	Name     string         `json:"name"`
+	Raw      bool           `json:"raw,omitempty"`
+	Request  *ExportedParam `json:"request,omitempty"`
+	Response *ExportedParam `json:"response,omitempty"`
+}
+
+// ExportedParam is an API's request or response type.
+type ExportedParam struct {
+	Type     *ExportedTypeRef `json:"type"`
+	Optional bool             `json:"optional,omitempty"` // declared as a pointer
+}
+
+// ExportedType describes a named type declaration.
+type ExportedType struct {
+	Name       string   `json:"name"` // qualified by import path ("example.com/users.Params")
+	Doc        string   `json:"doc,omitempty"`
+	TypeParams []string `json:"type_params,omitempty"`
+
+	// Kind is "struct" for struct types, "enum" for types
+	// defined in terms of a builtin type (like "type Status string")
+	// and "alias" for type aliases (like "type ID = string") and all other types.
+	Kind string `json:"kind"`
+
+	Fields     []*ExportedField `json:"fields,omitempty"`      // for Kind == "struct"
+	Underlying *ExportedTypeRef `json:"underlying,omitempty"`  // for other kinds
+	EnumValues []string         `json:"enum_values,omitempty"` // for Kind == "enum", the values of the type's exported constants
+}
+
+// ExportedField describes a struct field.
+type ExportedField struct {
+	Name      string           `json:"name"`
+	JSONName  string           `json:"json_name"`
+	Doc       string           `json:"doc,omitempty"`
+	Optional  bool             `json:"optional,omitempty"`  // declared as a pointer, omitempty or encore:"optional"
+	Sensitive bool             `json:"sensitive,omitempty"` // declared with encore:"sensitive"
+	Type      *ExportedTypeRef `json:"type"`
+}
+
+// ExportedTypeRef describes a type expression.
+// Named types are referenced by name rather than inlined,
+// which allows recursive types to be described.
+type ExportedTypeRef struct {
+	// Kind is one of "builtin", "named", "list", "map", "struct" or "type_param".
+	Kind string `json:"kind"`
+
+	Builtin   string             `json:"builtin,omitempty"`    // for Kind == "builtin"
+	Ref       string             `json:"ref,omitempty"`        // for Kind == "named"
+	TypeArgs  []*ExportedTypeRef `json:"type_args,omitempty"`  // for Kind == "named"
+	Elem      *ExportedTypeRef   `json:"elem,omitempty"`       // for Kind == "list"
+	Key       *ExportedTypeRef   `json:"key,omitempty"`        // for Kind == "map"
+	Value     *ExportedTypeRef   `json:"value,omitempty"`      // for Kind == "map"
+	Fields    []*ExportedField   `json:"fields,omitempty"`     // for Kind == "struct"
+	TypeParam string             `json:"type_param,omitempty"` // for Kind == "type_param"
+}
+
+// NamingPolicy determines the JSON names of struct fields
+// that do not specify one with a json tag.
+type NamingPolicy int
+
+const (
+	NamingAsIs      NamingPolicy = iota // use the Go field name ("UserID")
+	NamingCamelCase                     // use camelCase ("userID")
+	NamingSnakeCase                     // use snake_case ("user_id")
+)
+
+// ExportOptions configures ExportSchema.
+type ExportOptions struct {
+	// NamingPolicy is the policy for naming fields without
+	// an explicit JSON name. Explicit names are always kept.
+	NamingPolicy NamingPolicy
+}
+
+// ExportSchema describes the request and response types of all APIs
+// in the parse result. The output is deterministic JSON.
+// If opts is nil the default options are used.
+func ExportSchema(res *Result, opts *ExportOptions) ([]byte, error) {
+	if opts == nil {
+		opts = &ExportOptions{}
+	}
+	s, err := exportSchema(res.App, opts)
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(s, "", "  ")
+}
+
+func exportSchema(app *est.Application, opts *ExportOptions) (*ExportedSchema, error) {
+	e := newSchemaExporter(app, opts.NamingPolicy)
+
+	s := &ExportedSchema{Endpoints: []*ExportedEndpoint{}}
+	for _, svc := range app.Services {
+		for _, rpc := range svc.RPCs {
+			ep := &ExportedEndpoint{
+				Service: svc.Name,
+				Name:    rpc.Name,
+				Raw:     rpc.Raw,
+			}
+			var err error
+			if ep.Request, err = e.param(rpc.Request); err != nil {
+				return nil, fmt.Errorf("%s.%s request: %v", svc.Name, rpc.Name, err)
+			}
+			if ep.Response, err = e.param(rpc.Response); err != nil {
+				return nil, fmt.Errorf("%s.%s response: %v", svc.Name, rpc.Name, err)
+			}
+			s.Endpoints = append(s.Endpoints, ep)
+		}
+	}
+	sort.SliceStable(s.Endpoints, func(i, j int) bool {
+		a, b := s.Endpoints[i], s.Endpoints[j]
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		}
+		return a.Name < b.Name
+	})
+
+	// Resolve the referenced declarations. Resolving a declaration
+	// may queue further declarations, so loop until done.
+	s.Types = []*ExportedType{}
+	for len(e.queue) > 0 {
+		id := e.queue[0]
+		e.queue = e.queue[1:]
+		t, err := e.decl(id)
+		if err != nil {
+			return nil, err
+		}
+		s.Types = append(s.Types, t)
+	}
+	sort.SliceStable(s.Types, func(i, j int) bool {
+		return s.Types[i].Name < s.Types[j].Name
+	})
+	return s, nil
+}
+
+// schemaExporter converts schema types to their exported form.
+// It keeps track of the declarations referenced so each is only exported once,
+// even in the presence of recursive types.
+type schemaExporter struct {
+	decls   []*schema.Decl
+	aliases map[uint32]bool // decl ids of type aliases
+	seen    map[uint32]bool
+	names   map[string]uint32 // qualified name -> decl id, to detect collisions
+	queue   []uint32
+	naming  NamingPolicy
+}
+
+func newSchemaExporter(app *est.Application, naming NamingPolicy) *schemaExporter {
+	e := &schemaExporter{
+		decls:   app.Decls,
+		aliases: make(map[uint32]bool),
+		seen:    make(map[uint32]bool),
+		names:   make(map[string]uint32),
+		naming:  naming,
+	}
+	for _, pkg := range app.Packages {
+		for _, alias := range pkg.TypeAliases {
+			e.aliases[alias.Decl.Id] = true
+		}
+	}
+	return e
+}
+
+func (e *schemaExporter) param(p *est.Param) (*ExportedParam, error) {
+	if p == nil {
+		return nil, nil
+	}
+	typ, err := e.typ(p.Type)
+	if err != nil {
+		return nil, err
+	}
+	return &ExportedParam{Type: typ, Optional: p.IsPtr}, nil
+}
+
+func (e *schemaExporter) decl(id uint32) (*ExportedType, error) {
+	d := e.decls[id]
+	t := &ExportedType{
+		Name: e.declName(d),
+		Doc:  d.Doc,
+	}
+	for _, tp := range d.TypeParams {
+		t.TypeParams = append(t.TypeParams, tp.Name)
+	}
+
+	var err error
+	switch typ := d.Type.Typ.(type) {
+	case *schema.Type_Struct:
+		t.Kind = "struct"
+		t.Fields, err = e.fields(typ.Struct)
+	default:
+		t.Kind = "alias"
+		if _, ok := typ.(*schema.Type_Builtin); ok && !e.aliases[id] {
+			t.Kind = "enum"
+			t.EnumValues = d.EnumValues
+		}
+		t.Underlying, err = e.typ(d.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("type %s: %v", t.Name, err)
+	}
+	return t, nil
+}
+
+func (e *schemaExporter) fields(st *schema.Struct) ([]*ExportedField, error) {
+	var fields []*ExportedField
+	for _, f := range st.Fields {
+		if f.JsonName == "-" {
+			continue
+		}
+		typ, err := e.typ(f.Typ)
+		if err != nil {
+			return nil, fmt.Errorf("field %s: %v", f.Name, err)
+		}
+		jsonName := f.JsonName
+		if jsonName == "" {
+			jsonName = e.jsonName(f.Name)
+		}
+		fields = append(fields, &ExportedField{
+			Name:      f.Name,
+			JSONName:  jsonName,
+			Doc:       f.Doc,
+			Optional:  f.Optional || f.Pointer || hasTagOption(f.Tags, "json", "omitempty"),
+			Sensitive: f.Sensitive,
+			Type:      typ,
+		})
+	}
+	return fields, nil
+}
+
+// jsonName returns the JSON name of a field without an explicit one,
+// according to the naming policy.
+func (e *schemaExporter) jsonName(name string) string {
+	switch e.naming {
+	case NamingCamelCase:
+		return camelCase(name)
+	case NamingSnakeCase:
+		return SnakeCase(name)
+	default:
+		return name
+	}
+}
+
+// hasTagOption reports whether the struct tag with the given key has the given option.
+func hasTagOption(tags []*schema.Tag, key, option string) bool {
+	for _, t := range tags {
+		if t.Key == key {
+			for _, o := range t.Options {
+				if o == option {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+func (e *schemaExporter) typ(typ *schema.Type) (*ExportedTypeRef, error) {
+	switch t := typ.Typ.(type) {
+	case *schema.Type_Builtin:
+		return &ExportedTypeRef{Kind: "builtin", Builtin: strings.ToLower(t.Builtin.String())}, nil
+
+	case *schema.Type_Named:
+		id := t.Named.Id
+		if int(id) >= len(e.decls) {
+			return nil, fmt.Errorf("unknown declaration id %d", id)
+		}
+		name := e.declName(e.decls[id])
+		if !e.seen[id] {
+			if other, ok := e.names[name]; ok {
+				return nil, fmt.Errorf("declarations %d and %d have the same name %s", other, id, name)
+			}
+			e.seen[id] = true
+			e.names[name] = id
+			e.queue = append(e.queue, id)
+		}
+		ref := &ExportedTypeRef{Kind: "named", Ref: name}
+		for _, arg := range t.Named.TypeArguments {
+			a, err := e.typ(arg)
+			if err != nil {
+				return nil, err
+			}
+			ref.TypeArgs = append(ref.TypeArgs, a)
+		}
+		return ref, nil
+
+	case *schema.Type_List:
+		elem, err := e.typ(t.List.Elem)
+		if err != nil {
+			return nil, err
+		}
+		return &ExportedTypeRef{Kind: "list", Elem: elem}, nil
+
+	case *schema.Type_Map:
+		key, err := e.typ(t.Map.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := e.typ(t.Map.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &ExportedTypeRef{Kind: "map", Key: key, Value: value}, nil
+
+	case *schema.Type_Struct:
+		fields, err := e.fields(t.Struct)
+		if err != nil {
+			return nil, err
+		}
+		return &ExportedTypeRef{Kind: "struct", Fields: fields}, nil
+
+	case *schema.Type_TypeParameter:
+		d := e.decls[t.TypeParameter.DeclId]
+		idx := int(t.TypeParameter.ParamIdx)
+		if idx >= len(d.TypeParams) {
+			return nil, fmt.Errorf("unknown type parameter %d of %s", idx, e.declName(d))
+		}
+		return &ExportedTypeRef{Kind: "type_param", TypeParam: d.TypeParams[idx].Name}, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported type %T", t)
+	}
+}
+
+// declName returns the name of d qualified by its import path.
+func (e *schemaExporter) declName(d *schema.Decl) string {
+	if d.Loc == nil || d.Loc.PkgPath == "" {
+		return d.Name
+	}
+	return d.Loc.PkgPath + "." + d.Name
+}
@@ -0,0 +1,81 @@
+package parser
+
+import (
+	"testing"
+
+	qt "github.com/frankban/quicktest"
+	"github.com/rogpeppe/go-internal/txtar"
+
+	"encr.dev/parser/est"
+	schema "encr.dev/proto/encore/parser/schema/v1"
+)
+
+func TestExportSchemaNamingPolicy(t *testing.T) {
+	c := qt.New(t)
+	a := txtar.Parse([]byte(`
+-- svc/svc.go --
+package svc
+
+import "context"
+
+type Params struct {
+	UserID      string
+	HTTPServer  string
+	DisplayName string ` + "`json:\",omitempty\"`" + `
+	Email       string ` + "`json:\"EMAIL\"`" + `
+}
+
+//encore:api public
+func Get(ctx context.Context, p *Params) error { return nil }
+`))
+	base := t.TempDir()
+	err := txtar.Write(a, base)
+	c.Assert(err, qt.IsNil)
+	res, err := Parse(&Config{AppRoot: base, WorkingDir: ".", ModulePath: "test"})
+	c.Assert(err, qt.IsNil)
+
+	tests := []struct {
+		Policy NamingPolicy
+		Want   []string
+	}{
+		{NamingAsIs, []string{"UserID", "HTTPServer", "DisplayName", "EMAIL"}},
+		{NamingCamelCase, []string{"userID", "httpServer", "displayName", "EMAIL"}},
+		{NamingSnakeCase, []string{"user_id", "http_server", "display_name", "EMAIL"}},
+	}
+	for _, test := range tests {
+		s, err := exportSchema(res.App, &ExportOptions{NamingPolicy: test.Policy})
+		c.Assert(err, qt.IsNil)
+		c.Assert(s.Types, qt.HasLen, 1)
+		var got []string
+		for _, f := range s.Types[0].Fields {
+			got = append(got, f.JSONName)
+		}
+		c.Assert(got, qt.DeepEquals, test.Want, qt.Commentf("policy %d", test.Policy))
+	}
+}
+
+func TestExportSchemaNameCollision(t *testing.T) {
+	c := qt.New(t)
+	named := func(id uint32) *schema.Type {
+		return &schema.Type{Typ: &schema.Type_Named{Named: &schema.Named{Id: id}}}
+	}
+	str := &schema.Type{Typ: &schema.Type_Builtin{Builtin: schema.Builtin_STRING}}
+	loc := &schema.Loc{PkgPath: "test/common"}
+	app := &est.Application{
+		Decls: []*schema.Decl{
+			{Id: 0, Name: "Money", Loc: loc, Type: str},
+			{Id: 1, Name: "Money", Loc: loc, Type: str},
+		},
+		Services: []*est.Service{{
+			Name: "svc",
+			RPCs: []*est.RPC{{
+				Name: "Get",
+				Request: &est.Param{Type: &schema.Type{Typ: &schema.Type_Struct{Struct: &schema.Struct{
+					Fields: []*schema.Field{{Name: "A", Typ: named(0)}, {Name: "B", Typ: named(1)}},
+				}}}},
+			}},
+		}},
+	}
+	_, err := exportSchema(app, &ExportOptions{})
+	c.Assert(err, qt.ErrorMatches, `svc.Get request: field B: declarations 0 and 1 have the same name test/common.Money`)
+}
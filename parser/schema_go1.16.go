@@ -32,7 +32,12 @@ func (p *parser) parseDecl(pkg *est.Package, d *names.PkgDecl, _ typeParameterLo
 		p.declMap[key] = decl
 		p.decls = append(p.decls, decl)
 
+		p.declareAlias(pkg, d, d.Spec.(*ast.TypeSpec), decl)
+
 		decl.Type = p.resolveType(pkg, d.File, d.Spec.(*ast.TypeSpec).Type, nil)
+		decl.EnumValues = p.enumValues(pkg, d.Name, decl.Type)
+	} else {
+		p.checkRecursiveAlias(decl)
 	}
 
 	return &schema.Type{Typ: &schema.Type_Named{
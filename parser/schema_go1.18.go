@@ -36,6 +36,7 @@ func (p *parser) parseDecl(pkg *est.Package, d *names.PkgDecl, typeParameters ty
 		}
 		p.declMap[key] = decl
 		p.decls = append(p.decls, decl)
+		p.declareAlias(pkg, d, spec, decl)
 
 		typeParameterLookup := make(typeParameterLookup)
 
@@ -63,6 +64,9 @@ func (p *parser) parseDecl(pkg *est.Package, d *names.PkgDecl, typeParameters ty
 		}
 
 		decl.Type = p.resolveType(pkg, d.File, spec.Type, typeParameterLookup)
+		decl.EnumValues = p.enumValues(pkg, d.Name, decl.Type)
+	} else {
+		p.checkRecursiveAlias(decl)
 	}
 
 	return &schema.Type{Typ: &schema.Type_Named{
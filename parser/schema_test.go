@@ -10,6 +10,7 @@ import (
 	qt "github.com/frankban/quicktest"
 
 	"encr.dev/pkg/errlist"
+	schema "encr.dev/proto/encore/parser/schema/v1"
 )
 
 func TestParseStructTag(t *testing.T) {
@@ -61,11 +62,12 @@ func TestParseStructTag(t *testing.T) {
 		errors: errlist.New(fset),
 	}
 	c := qt.New(t)
+	str := &schema.Type{Typ: &schema.Type_Builtin{Builtin: schema.Builtin_STRING}}
 	for _, test := range tests {
 		x, err := goparser.ParseExpr("`" + test.Tag + "`")
 		c.Assert(err, qt.IsNil)
 		lit := x.(*ast.BasicLit)
-		got := p.parseStructTag(lit, nil)
+		got := p.parseStructTag(lit, str)
 		c.Assert(p.errors.Err(), qt.IsNil)
 		c.Assert(got, qt.DeepEquals, test.Want)
 	}
@@ -6,6 +6,7 @@ import (
 	"go/ast"
 	"go/token"
 	"go/types"
+	"regexp"
 	"strconv"
 	"strings"
 
@@ -15,6 +16,9 @@ import (
 	schema "encr.dev/proto/encore/parser/schema/v1"
 )
 
+// svcNameRe matches valid service names.
+var svcNameRe = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
 // parseFeatures parses the application packages looking for Encore features
 // such as RPCs and auth handlers, and computes the set of services.
 func (p *parser) parseServices() {
@@ -24,6 +28,15 @@ func (p *parser) parseServices() {
 	// whether they define RPCs.
 	p.svcMap = make(map[string]*est.Service)
 	for _, pkg := range p.pkgs {
+		p.checkCanceled()
+
+		// Test files are only compiled into tests, so a package
+		// consisting of only test files cannot be a service.
+		if len(pkg.TestFiles) == len(pkg.Files) {
+			p.checkTestOnlyDirectives(pkg)
+			continue
+		}
+
 		// svc is a candidate service; if we don't find any
 		// rpcs it is discarded.
 		svc := &est.Service{
@@ -33,12 +46,19 @@ func (p *parser) parseServices() {
 		}
 		if isSvc := p.parseFuncs(pkg, svc); !isSvc {
 			continue
+		} else if len(svc.RPCs) == 0 {
+			p.warnf(pkg.Files[0].AST.Package, "service %s does not define any API endpoints", svc.Name)
 		}
 		pkg.Service = svc
 		svcPaths[pkg.ImportPath] = svc
+		namePos := pkg.Files[0].AST.Name.Pos()
+		if !svcNameRe.MatchString(svc.Name) || token.IsKeyword(svc.Name) {
+			p.errf(namePos, "invalid service name %s: service names must start with a lowercase letter "+
+				"and contain only lowercase letters and digits", svc.Name)
+		}
 		if svc2 := p.svcMap[svc.Name]; svc2 != nil {
-			p.errf(pkg.AST.Pos(), "service %s defined twice (previous definition at %s)",
-				svc.Name, p.fset.Position(svc2.Root.Files[0].AST.Pos()))
+			p.errf(namePos, "service %s defined twice (previous definition at %s)",
+				svc.Name, p.fset.Position(svc2.Root.Files[0].AST.Name.Pos()))
 			continue
 		}
 		p.svcs = append(p.svcs, svc)
@@ -66,6 +86,53 @@ PkgLoop:
 			}
 		}
 	}
+
+	for _, svc := range p.svcs {
+		p.checkInitFuncs(svc)
+	}
+	p.validateParamPassing()
+}
+
+// checkTestOnlyDirectives warns about the first encore directive
+// in a package consisting of only test files, which is ignored.
+func (p *parser) checkTestOnlyDirectives(pkg *est.Package) {
+	for _, f := range pkg.Files {
+		for _, decl := range f.AST.Decls {
+			var doc *ast.CommentGroup
+			switch decl := decl.(type) {
+			case *ast.FuncDecl:
+				doc = decl.Doc
+			case *ast.GenDecl:
+				doc = decl.Doc
+			}
+			if doc == nil {
+				continue
+			}
+			for _, c := range doc.List {
+				if text := strings.TrimPrefix(c.Text, "//"); strings.HasPrefix(strings.TrimSpace(text), "encore:") {
+					p.warnf(c.Pos(), "encore directive is ignored: package %s only contains test files, "+
+						"so it cannot define a service (declare services in non-test files)", pkg.Name)
+					return
+				}
+			}
+		}
+	}
+}
+
+// checkInitFuncs warns about init functions declared in the service's packages.
+// Encore manages the initialization order of services, so work done in
+// init functions is better done when initializing the service struct.
+func (p *parser) checkInitFuncs(svc *est.Service) {
+	for _, pkg := range svc.Pkgs {
+		for _, f := range pkg.Files {
+			for _, decl := range f.AST.Decls {
+				if fd, ok := decl.(*ast.FuncDecl); ok && fd.Recv == nil && fd.Name.Name == "init" {
+					p.warnf(fd.Name.Pos(), "service %s declares an init function: initialization order is managed by Encore, "+
+						"consider initializing the service in a service struct (//encore:service) instead", svc.Name)
+				}
+			}
+		}
+	}
 }
 
 // parseResources parses infrastructure resources declared in the packages.
@@ -82,8 +149,16 @@ func (p *parser) parseResources() {
 					vs := s.(*ast.ValueSpec)
 					for i, x := range vs.Values {
 						if call, ok := x.(*ast.CallExpr); ok {
-							if sel, ok := call.Fun.(*ast.SelectorExpr); ok {
+							// Unwrap generic calls like "pkg.Foo[T]()"
+							fun, typeArg := call.Fun, ast.Expr(nil)
+							if idx, ok := fun.(*ast.IndexExpr); ok {
+								fun, typeArg = idx.X, idx.Index
+							}
+							if sel, ok := fun.(*ast.SelectorExpr); ok {
 								if id, ok := sel.X.(*ast.Ident); ok {
+									if p.checkShadowedImport(file.AST, info, sel, sqldbImportPath) || p.checkShadowedImport(file.AST, info, sel, pubsubImportPath) {
+										continue
+									}
 									ri := info.Idents[id]
 									if ri == nil {
 										continue
@@ -101,12 +176,24 @@ func (p *parser) parseResources() {
 														DeclFile: file,
 														DeclName: decl,
 														DBName:   name,
+														CallPos:  call.Pos(),
 													})
 												}
 											} else {
 												p.errf(call.Args[0].Pos(), "sqldb.Named must be called with a string literal, not %v", call.Args[0])
 											}
 										}
+									case pubsubImportPath:
+										switch sel.Sel.Name {
+										case "NewTopic":
+											p.parsePubSubTopic(pkg, file, vs.Names[i], call, typeArg)
+										case "NewSubscription":
+											p.parsePubSubSubscription(pkg, file, vs.Names[i], call)
+										}
+									default:
+										if d := p.resourceDetector(ri.ImportPath, sel.Sel.Name); d != nil {
+											p.detectResource(d, pkg, file, vs.Names[i], call)
+										}
 									}
 								}
 							}
@@ -120,6 +207,7 @@ func (p *parser) parseResources() {
 
 // parseFuncs parses the pkg for any declared RPCs and auth handlers.
 func (p *parser) parseFuncs(pkg *est.Package, svc *est.Service) (isService bool) {
+	isService = p.parseServiceStruct(pkg, svc)
 	for _, f := range pkg.Files {
 		for _, decl := range f.AST.Decls {
 			fd, ok := decl.(*ast.FuncDecl)
@@ -133,9 +221,26 @@ func (p *parser) parseFuncs(pkg *est.Package, svc *est.Service) (isService bool)
 			}
 
 			switch dir := dir.(type) {
-			case *rpcDirective:
+			case *RPCDirective:
+				var svcStruct *est.ServiceStruct
+				if fd.Recv != nil {
+					if svcStruct = p.rpcReceiver(svc, fd); svcStruct == nil {
+						continue
+					}
+				}
+				if !ast.IsExported(fd.Name.Name) {
+					p.warnf(fd.Name.Pos(), "API endpoint %s is not exported and cannot be called from other services", fd.Name.Name)
+				}
 				path := dir.Path
-				if path == nil {
+				if prefix := svc.PathPrefix; prefix != nil && path != nil {
+					if hasPathPrefix(path, prefix) {
+						p.errf(path.Pos, "API path %s already includes the service path prefix %s", path, prefix)
+					}
+					path = &paths.Path{
+						Pos:      path.Pos,
+						Segments: append(append([]paths.Segment{}, prefix.Segments...), path.Segments...),
+					}
+				} else if path == nil {
 					path = &paths.Path{
 						Pos: dir.TokenPos,
 						Segments: []paths.Segment{{
@@ -144,23 +249,51 @@ func (p *parser) parseFuncs(pkg *est.Package, svc *est.Service) (isService bool)
 						}},
 					}
 				}
+				access := svc.ResolveAccess(dir.Access)
+				if dir.Access == "" {
+					// validateRPCDirective only checks declared access types.
+					if err := validateRPCAccess(dir, access); err != nil {
+						p.err(fd.Doc.Pos(), err.Error())
+					}
+				}
 				rpc := &est.RPC{
 					Svc:         svc,
 					Name:        fd.Name.Name,
 					Doc:         doc,
-					Access:      dir.Access,
+					Access:      access,
 					Raw:         dir.Raw,
 					Func:        fd,
 					File:        f,
 					Path:        path,
 					HTTPMethods: dir.Method,
+					Transforms:  dir.Transforms,
+					Pos:         fd.Name.Pos(),
+					SvcStruct:   svcStruct,
+
+					Streaming:          dir.Stream,
+					Deprecated:         dir.Deprecated,
+					DeprecationMessage: dir.DeprecationMessage,
+					MaxBodySize:        dir.MaxBodySize,
+					SuccessStatus:      dir.SuccessStatus,
+					Tags:               dir.Tags,
+					BuildTags:          f.BuildTags,
+					RateLimit:          dir.RateLimit,
+					DeclaredAccess:     dir.Access,
+				}
+				if rpc.SuccessStatus == 0 {
+					rpc.SuccessStatus = 200
+				}
+				// Doc comments are only known if they were parsed.
+				if !p.cfg.SkipDocComments && rpc.Access != est.Private && strings.TrimSpace(doc) == "" {
+					p.warnf(fd.Name.Pos(), "%s API %s.%s has no documentation: consider adding a doc comment describing it",
+						rpc.Access, svc.Name, rpc.Name)
 				}
 				p.initRPC(rpc)
 
 				svc.RPCs = append(svc.RPCs, rpc)
 				isService = true
 
-			case *authHandlerDirective:
+			case *AuthHandlerDirective:
 				if h := p.authHandler; h != nil {
 					p.errf(fd.Pos(), "cannot declare multiple auth handlers (previous declaration at %s)",
 						p.fset.Position(h.Func.Pos()))
@@ -177,6 +310,9 @@ func (p *parser) parseFuncs(pkg *est.Package, svc *est.Service) (isService bool)
 				p.authHandler = authHandler
 				isService = true
 
+			case *ServiceDirective:
+				p.errf(dir.Pos(), "the encore:service directive must be declared on a struct type, not on func %s", fd.Name.Name)
+
 			default:
 				p.errf(dir.Pos(), "unexpected directive type %T", dir)
 				p.abort()
@@ -186,9 +322,257 @@ func (p *parser) parseFuncs(pkg *est.Package, svc *est.Service) (isService bool)
 	return isService
 }
 
+// parseServiceStruct parses the struct type declared with //encore:service
+// in pkg, if any, and records it on svc. It reports whether one was found.
+func (p *parser) parseServiceStruct(pkg *est.Package, svc *est.Service) bool {
+	for _, f := range pkg.Files {
+		for _, decl := range f.AST.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range gd.Specs {
+				ts := spec.(*ast.TypeSpec)
+				cg := ts.Doc
+				if cg == nil && !gd.Lparen.IsValid() {
+					cg = gd.Doc
+				}
+				dir, doc := p.parseDirectives(cg)
+				if dir == nil {
+					continue
+				} else if _, ok := dir.(*ServiceDirective); !ok {
+					p.errf(dir.Pos(), "unexpected encore directive on type %s", ts.Name.Name)
+					continue
+				} else if _, ok := ts.Type.(*ast.StructType); !ok {
+					p.errf(ts.Pos(), "the encore:service directive must be declared on a struct type")
+					continue
+				} else if ss := svc.Struct; ss != nil {
+					p.errf(ts.Pos(), "cannot declare multiple service structs in service %s (previous declaration at %s)",
+						svc.Name, p.fset.Position(ss.Decl.Pos()))
+					continue
+				}
+				svc.Struct = &est.ServiceStruct{
+					Svc:  svc,
+					Name: ts.Name.Name,
+					Doc:  doc,
+					Decl: ts,
+					File: f,
+				}
+				svc.PathPrefix = dir.(*ServiceDirective).PathPrefix
+				svc.CORS = dir.(*ServiceDirective).CORS
+				svc.DefaultAccess = dir.(*ServiceDirective).DefaultAccess
+			}
+		}
+	}
+
+	ss := svc.Struct
+	if ss == nil {
+		return false
+	}
+	initName := "init" + ss.Name
+	for _, f := range pkg.Files {
+		for _, decl := range f.AST.Decls {
+			fd, ok := decl.(*ast.FuncDecl)
+			if !ok {
+				continue
+			}
+			switch name := fd.Name.Name; {
+			case name == initName && fd.Recv == nil:
+				ss.Init = fd
+				p.validateServiceStructInit(ss, fd)
+			case name == initName:
+				p.errf(fd.Name.Pos(), "the initializer %s of service struct %s must be a function, not a method", initName, ss.Name)
+			case strings.EqualFold(name, initName) && fd.Recv == nil:
+				p.warnf(fd.Name.Pos(), "%s looks like the initializer of service struct %s, but initializers must be named %s",
+					name, ss.Name, initName)
+			}
+		}
+	}
+	return true
+}
+
+// validateServiceStructInit validates that the initializer fd
+// of the service struct ss has the signature func() (*Struct, error).
+func (p *parser) validateServiceStructInit(ss *est.ServiceStruct, fd *ast.FuncDecl) {
+	hint := fmt.Sprintf("\n\thint: service struct initializers must have the signature func %s() (*%s, error)", fd.Name.Name, ss.Name)
+	if tparams := funcTypeParams(fd); tparams != nil && tparams.NumFields() > 0 {
+		p.errf(tparams.Pos(), "the initializer %s cannot have type parameters"+hint, fd.Name.Name)
+	}
+	if params := fd.Type.Params; params.NumFields() > 0 {
+		p.errf(params.Pos(), "the initializer %s cannot take any parameters"+hint, fd.Name.Name)
+	}
+
+	results := fd.Type.Results
+	if results.NumFields() != 2 {
+		p.errf(fd.Name.Pos(), "the initializer %s must return (*%s, error), got %s"+hint,
+			fd.Name.Name, ss.Name, types.ExprString(fd.Type))
+		return
+	}
+	res, _ := getField(results, 0)
+	isStructPtr := false
+	if star, ok := res.Type.(*ast.StarExpr); ok {
+		id, ok := star.X.(*ast.Ident)
+		isStructPtr = ok && id.Name == ss.Name
+	}
+	if !isStructPtr {
+		p.errf(res.Type.Pos(), "the initializer %s must return *%s as its first result, got %s"+hint,
+			fd.Name.Name, ss.Name, types.ExprString(res.Type))
+	}
+	errRes, _ := getField(results, 1)
+	if id, ok := errRes.Type.(*ast.Ident); !ok || id.Name != "error" {
+		p.errf(errRes.Type.Pos(), "the initializer %s must return error as its second result, got %s"+hint,
+			fd.Name.Name, types.ExprString(errRes.Type))
+	}
+}
+
+// resolveInitOrder computes the initialization order of each service's
+// resources and service struct, recording the resources referenced by
+// the service struct's init function as its dependencies.
+func (p *parser) resolveInitOrder() {
+	for _, svc := range p.svcs {
+		var steps []est.InitStep
+		for _, pkg := range svc.Pkgs {
+			for _, res := range pkg.Resources {
+				steps = append(steps, est.InitStep{Resource: res})
+			}
+		}
+		if ss := svc.Struct; ss != nil {
+			ss.Deps = p.initDeps(ss)
+			steps = append(steps, est.InitStep{Struct: ss})
+		}
+
+		index := make(map[est.Resource]int, len(steps))
+		for i, step := range steps {
+			if step.Resource != nil {
+				index[step.Resource] = i
+			}
+		}
+
+		// Only the dependencies the service initializes itself are ordered.
+		order, cycle := topoSort(len(steps), func(i int) []int {
+			var all []est.Resource
+			if ss := steps[i].Struct; ss != nil {
+				all = ss.Deps
+			} else if dep, ok := steps[i].Resource.(est.DependentResource); ok {
+				all = dep.Dependencies()
+			}
+			var deps []int
+			for _, r := range all {
+				if j, ok := index[r]; ok {
+					deps = append(deps, j)
+				}
+			}
+			return deps
+		})
+		if cycle != nil {
+			names := make([]string, len(cycle))
+			for i, j := range cycle {
+				names[i] = steps[j].Name()
+			}
+			// Service structs are not depended on, so cycles are between resources.
+			p.errf(steps[cycle[0]].Resource.Ident().Pos(), "resource dependency cycle in service %s: %s",
+				svc.Name, strings.Join(names, " -> "))
+			continue
+		}
+		for _, i := range order {
+			svc.InitOrder = append(svc.InitOrder, steps[i])
+		}
+	}
+}
+
+// initDeps returns the resources referenced by the init function
+// of a service struct, in the order they are first referenced.
+func (p *parser) initDeps(ss *est.ServiceStruct) []est.Resource {
+	fd := ss.Init
+	if fd == nil {
+		return nil
+	}
+	pkg := ss.Svc.Root
+	var file *est.File
+	for _, f := range pkg.Files {
+		if f.AST.Pos() <= fd.Pos() && fd.End() <= f.AST.End() {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return nil
+	}
+
+	local := make(map[string]est.Resource, len(pkg.Resources))
+	for _, res := range pkg.Resources {
+		local[res.Ident().Name] = res
+	}
+	info := p.names[pkg].Files[file]
+
+	var deps []est.Resource
+	seen := make(map[est.Resource]bool)
+	ast.Inspect(fd, func(node ast.Node) bool {
+		var res est.Resource
+		descend := true
+		if ref, ok := file.References[node]; ok && ref.Res != nil {
+			// A reference to a resource in another package.
+			res, descend = ref.Res, false
+		} else if id, ok := node.(*ast.Ident); ok {
+			if ri := info.Idents[id]; ri != nil && ri.Package {
+				res = local[id.Name]
+			}
+		}
+		if res != nil && !seen[res] {
+			seen[res] = true
+			deps = append(deps, res)
+		}
+		return descend
+	})
+	return deps
+}
+
+// hasPathPrefix reports whether path begins with the literal segments of prefix.
+func hasPathPrefix(path, prefix *paths.Path) bool {
+	if len(path.Segments) < len(prefix.Segments) {
+		return false
+	}
+	for i, s := range prefix.Segments {
+		if path.Segments[i] != s {
+			return false
+		}
+	}
+	return true
+}
+
+// rpcReceiver validates the receiver of an API declared as a method
+// and returns the service struct it is declared on.
+// If the receiver is not the service struct it reports an error and returns nil.
+func (p *parser) rpcReceiver(svc *est.Service, fd *ast.FuncDecl) *est.ServiceStruct {
+	recv := fd.Recv.List[0].Type
+	if star, ok := recv.(*ast.StarExpr); ok {
+		recv = star.X
+	}
+	ss := svc.Struct
+	if id, ok := recv.(*ast.Ident); ok && ss != nil && id.Name == ss.Name {
+		return ss
+	}
+
+	if ss == nil {
+		p.errf(fd.Recv.Pos(), "API %s cannot be declared as a method: service %s does not declare a service struct with //encore:service",
+			fd.Name.Name, svc.Name)
+	} else {
+		p.errf(fd.Recv.Pos(), "API %s must be declared as a method on the service struct %s", fd.Name.Name, ss.Name)
+	}
+	return nil
+}
+
 func (p *parser) initRPC(rpc *est.RPC) {
+	// Request and response types must be concrete for the API to be callable,
+	// so the type arguments of generic types cannot be left to the caller.
+	if tparams := funcTypeParams(rpc.Func); tparams != nil {
+		p.errf(tparams.Pos(), "API %s cannot declare type parameters: request and response types must be concrete "+
+			"(like Paginated[User], not Paginated[T])", rpc.Name)
+		return
+	}
+
 	if rpc.Raw {
-		p.initRawRPC(rpc)
+		p.parseRawEndpoint(rpc)
 	} else {
 		p.initTypedRPC(rpc)
 	}
@@ -205,6 +589,108 @@ func (p *parser) initRPC(rpc *est.RPC) {
 	}
 }
 
+// validatePrivateAPIs ensures private APIs are not routed through the public gateway.
+// Private APIs are only registered on the private router, but a private API sharing
+// a route with a public API (using other HTTP methods) would still be reachable
+// at a publicly served path.
+func (p *parser) validatePrivateAPIs() {
+	var public []*est.RPC
+	for _, svc := range p.svcs {
+		for _, rpc := range svc.RPCs {
+			if rpc.Access != est.Private && rpc.Path != nil {
+				public = append(public, rpc)
+			}
+		}
+	}
+
+	for _, svc := range p.svcs {
+		for _, rpc := range svc.RPCs {
+			if rpc.Access != est.Private || rpc.Path == nil {
+				continue
+			}
+			for _, other := range public {
+				if samePathRoute(rpc.Path, other.Path) {
+					p.errf(rpc.Path.Pos, "private API %s.%s uses the path %s, which is served on the public gateway by %s API %s.%s (at %s): "+
+						"private APIs must not share paths with public APIs",
+						rpc.Svc.Name, rpc.Name, rpc.Path, other.Access, other.Svc.Name, other.Name, p.fset.Position(other.Path.Pos))
+					break
+				}
+			}
+		}
+	}
+}
+
+// reservedRPCPrefixes are the prefixes of API names reserved for
+// Encore's generated code and future framework features.
+var reservedRPCPrefixes = []string{"Encore", "encore", "__"}
+
+// validateRPCNames ensures the API names within each service are unique,
+// ignoring case, as they would otherwise collide in generated clients.
+// It also ensures they don't use any of the reservedRPCPrefixes.
+func (p *parser) validateRPCNames() {
+	for _, svc := range p.svcs {
+		seen := make(map[string]*est.RPC, len(svc.RPCs))
+		for _, rpc := range svc.RPCs {
+			for _, prefix := range reservedRPCPrefixes {
+				if strings.HasPrefix(rpc.Name, prefix) {
+					p.errf(rpc.Pos, "API %s.%s uses the reserved prefix %q: API names starting with "+
+						"\"Encore\", \"encore\" or \"__\" are reserved for Encore's generated code, consider renaming the API",
+						svc.Name, rpc.Name, prefix)
+					break
+				}
+			}
+			key := strings.ToLower(rpc.Name)
+			if prev, ok := seen[key]; ok {
+				p.errf(rpc.Pos, "API %s.%s conflicts with API %s.%s (at %s): API names must be unique within a service, ignoring case",
+					svc.Name, rpc.Name, svc.Name, prev.Name, p.fset.Position(prev.Pos))
+				continue
+			}
+			seen[key] = rpc
+		}
+	}
+}
+
+// validateReservedPaths ensures no API declares a path under
+// one of the path prefixes reserved by the platform.
+func (p *parser) validateReservedPaths() {
+	prefixes := p.cfg.ReservedPathPrefixes
+	if prefixes == nil {
+		prefixes = DefaultReservedPathPrefixes
+	}
+	for _, svc := range p.svcs {
+		for _, rpc := range svc.RPCs {
+			path := rpc.Path.String()
+			for _, prefix := range prefixes {
+				if prefix = strings.Trim(prefix, "/"); prefix == "" {
+					continue
+				}
+				prefix = "/" + prefix
+				if path == prefix || strings.HasPrefix(path, prefix+"/") {
+					p.errf(rpc.Path.Pos, "API %s.%s has path %s under the reserved path prefix %s: "+
+						"paths under it are reserved for the platform, consider choosing another path",
+						svc.Name, rpc.Name, path, prefix)
+					break
+				}
+			}
+		}
+	}
+}
+
+// samePathRoute reports whether a and b match the same requests,
+// ignoring the names of their parameters.
+func samePathRoute(a, b *paths.Path) bool {
+	if len(a.Segments) != len(b.Segments) {
+		return false
+	}
+	for i, s := range a.Segments {
+		o := b.Segments[i]
+		if s.Type != o.Type || (s.Type == paths.Literal && s.Value != o.Value) {
+			return false
+		}
+	}
+	return true
+}
+
 func (p *parser) initTypedRPC(rpc *est.RPC) {
 	const sigHint = `
 	hint: valid signatures are:
@@ -220,16 +706,31 @@ func (p *parser) initTypedRPC(rpc *est.RPC) {
 		return
 	}
 
-	results := rpc.Func.Type.Results
-	numResults := results.NumFields()
-	if numResults == 0 {
-		p.errf(rpc.Func.Type.Pos(), "invalid API signature (too few results)"+sigHint)
+	if !p.validateRPCResults(rpc, sigHint) {
 		return
 	}
+	results := rpc.Func.Type.Results
+	numResults := results.NumFields()
 
 	pkgNames := p.names[rpc.Svc.Root]
 	info := pkgNames.Files[rpc.File]
 
+	// Streaming APIs take the stream as their last parameter
+	// and send their responses on it.
+	if last, _ := getField(params, numParams-1); rpc.Streaming {
+		if !p.parseStreamParam(rpc, info, last) {
+			return
+		} else if numResults > 1 {
+			p.err(rpc.Func.Type.Pos(), "streaming APIs cannot declare a response type; send messages on the stream instead")
+			return
+		}
+		numParams--
+	} else if _, ok := streamMessageType(info, last.Type); ok {
+		p.errf(last.Pos(), "API %s takes an api.Stream parameter but is not declared as streaming\n"+
+			"\thint: declare streaming APIs with the stream option (//encore:api public stream)", rpc.Name)
+		return
+	}
+
 	// First type should always be context.Context
 	req := params.List[0].Type
 	if err := validateSel(info, req, "context", "Context"); err != nil {
@@ -261,9 +762,11 @@ func (p *parser) initTypedRPC(rpc *est.RPC) {
 					name, pp.Value, pp.String())
 				continue
 			}
-			typ := p.resolveType(rpc.Svc.Root, rpc.File, param.Type, nil)
+			typ := p.unalias(p.resolveType(rpc.Svc.Root, rpc.File, param.Type, nil))
 			if !p.validatePathParamType(param, name, typ, pp.Type) {
 				continue
+			} else if !p.validatePathParamConstraint(param, name, typ, pp.Constraint) {
+				continue
 			}
 			pathParams[seenParams].ValueType = typ.GetBuiltin()
 			seenParams++
@@ -276,6 +779,7 @@ func (p *parser) initTypedRPC(rpc *est.RPC) {
 			}
 
 			rpc.Request = p.resolveParameter("payload parameter", rpc.Svc.Root, rpc.File, param.Type)
+			rpc.RequestFields = p.requestFields(rpc.Request)
 		}
 	}
 	if seenParams < len(pathParams) {
@@ -287,24 +791,10 @@ func (p *parser) initTypedRPC(rpc *est.RPC) {
 	}
 
 	// First return value must be *T or *pkg.T
-	if numResults >= 2 {
-		result := results.List[0]
+	if numResults == 2 {
+		result, _ := getField(results, 0)
 		rpc.Response = p.resolveParameter("response", rpc.Svc.Root, rpc.File, result.Type)
-	}
-
-	if numResults > 2 {
-		result, _ := getField(results, 2)
-		p.err(result.Pos(), "API signature cannot contain more than two results"+sigHint)
-		return
-	}
-
-	err, _ := getField(results, numResults-1)
-	if id, ok := err.Type.(*ast.Ident); !ok || id.Name != "error" {
-		p.err(err.Pos(), "last result is not of type error"+sigHint)
-		return
-	} else if pkgNames.Decls["error"] != nil {
-		p.err(err.Pos(), "last result is not of type error (local name shadows builtin)"+sigHint)
-		return
+		rpc.ResponseFields = p.responseFields(rpc.Response)
 	}
 
 	if len(rpc.HTTPMethods) == 0 {
@@ -316,6 +806,71 @@ func (p *parser) initTypedRPC(rpc *est.RPC) {
 	}
 }
 
+// parseStreamParam parses the stream parameter of a streaming API,
+// which must be of type api.Stream[T] where T is a named struct type.
+// It reports whether the parameter is valid.
+func (p *parser) parseStreamParam(rpc *est.RPC, info *names.File, param *ast.Field) bool {
+	const hint = "\n\thint: streaming APIs take the stream as their last parameter, like func(ctx context.Context, stream api.Stream[Message]) error"
+	if rpc.Func.Type.Params.NumFields() < 2 {
+		p.err(rpc.Func.Type.Pos(), "streaming APIs must take an api.Stream parameter"+hint)
+		return false
+	}
+	msg, ok := streamMessageType(info, param.Type)
+	if !ok {
+		p.errf(param.Type.Pos(), "the last parameter of a streaming API must be of type api.Stream[T], got %s"+hint,
+			types.ExprString(param.Type))
+		return false
+	}
+	rpc.StreamMessage = p.resolveParameter("stream message", rpc.Svc.Root, rpc.File, msg)
+	return true
+}
+
+// streamMessageType reports the message type of an api.Stream[T] type expression.
+func streamMessageType(info *names.File, expr ast.Expr) (msg ast.Expr, ok bool) {
+	idx, ok := expr.(*ast.IndexExpr)
+	if !ok {
+		return nil, false
+	} else if imp, obj := pkgObj(info, idx.X); imp != apiImportPath || obj != "Stream" {
+		return nil, false
+	}
+	return idx.Index, true
+}
+
+// validateRPCResults ensures a typed API returns either (response, error) or error.
+// It reports an error at the function signature and returns false otherwise.
+func (p *parser) validateRPCResults(rpc *est.RPC, sigHint string) bool {
+	results := rpc.Func.Type.Results
+	numResults := results.NumFields()
+	isErr := func(i int) bool {
+		field, _ := getField(results, i)
+		id, ok := field.Type.(*ast.Ident)
+		return ok && id.Name == "error"
+	}
+
+	switch {
+	case numResults == 1 && isErr(0), numResults == 2 && !isErr(0) && isErr(1):
+		if p.names[rpc.Svc.Root].Decls["error"] != nil {
+			p.err(rpc.Func.Type.Pos(), "API endpoints must return (response, error) or error (local name error shadows builtin)"+sigHint)
+			return false
+		}
+		return true
+	default:
+		p.errf(rpc.Func.Type.Pos(), "API endpoints must return (response, error) or error, got %s"+sigHint, resultsString(results))
+		return false
+	}
+}
+
+// resultsString returns the result types of a function signature as a
+// parenthesized list, like "(*Response, error)".
+func resultsString(results *ast.FieldList) string {
+	var typs []string
+	for i, n := 0, results.NumFields(); i < n; i++ {
+		field, _ := getField(results, i)
+		typs = append(typs, types.ExprString(field.Type))
+	}
+	return "(" + strings.Join(typs, ", ") + ")"
+}
+
 func (p *parser) validatePathParamType(param *ast.Field, name string, typ *schema.Type, segType paths.SegmentType) bool {
 	b := typ.GetBuiltin()
 
@@ -345,24 +900,86 @@ func (p *parser) validatePathParamType(param *ast.Field, name string, typ *schem
 	}
 }
 
-func (p *parser) initRawRPC(rpc *est.RPC) {
+// validatePathParamConstraint ensures the type of a path parameter
+// matches the type constraint declared in the API path, if any.
+func (p *parser) validatePathParamConstraint(param *ast.Field, name string, typ *schema.Type, constraint string) bool {
+	b := typ.GetBuiltin()
+
+	var ok bool
+	switch constraint {
+	case "":
+		return true
+	case "string":
+		ok = b == schema.Builtin_STRING
+	case "uuid":
+		ok = b == schema.Builtin_UUID
+	case "bool":
+		ok = b == schema.Builtin_BOOL
+	case "int":
+		switch b {
+		case schema.Builtin_INT,
+			schema.Builtin_INT8,
+			schema.Builtin_INT16,
+			schema.Builtin_INT32,
+			schema.Builtin_INT64,
+			schema.Builtin_UINT,
+			schema.Builtin_UINT8,
+			schema.Builtin_UINT16,
+			schema.Builtin_UINT32,
+			schema.Builtin_UINT64:
+			ok = true
+		}
+	}
+	if !ok {
+		p.errf(param.Pos(), "path parameter '%s' does not match its path constraint '<%s>'", name, constraint)
+	}
+	return ok
+}
+
+// parseRawEndpoint parses and validates the signature of a raw endpoint.
+// Raw endpoints operate directly on the HTTP request and response,
+// and therefore cannot declare request or response types.
+// Their paths may contain parameters and wildcards, but these are
+// not bound to function parameters and are read from the request instead.
+func (p *parser) parseRawEndpoint(rpc *est.RPC) {
 	const sigHint = `
 	hint: signature must be func(http.ResponseWriter, *http.Request)`
 
+	// Raw endpoints never have request or response data.
+	rpc.Request = nil
+	rpc.Response = nil
+
+	info := p.names[rpc.Svc.Root].Files[rpc.File]
 	params := rpc.Func.Type.Params
+
+	// Catch the common mistake of marking a typed API as raw.
+	if params.NumFields() > 0 && validateSel(info, params.List[0].Type, "context", "Context") == nil {
+		p.errf(rpc.Func.Type.Pos(), "API %s is declared raw but has a typed API signature"+sigHint+
+			"\n\tor remove 'raw' from the encore:api directive to declare a typed API", rpc.Name)
+		return
+	}
+
 	if params.NumFields() < 2 {
 		p.err(params.Pos(), "invalid API signature (too few parameters)"+sigHint)
 		return
 	} else if params.NumFields() > 2 {
-		p.err(params.Pos(), "invalid API signature (too many parameters)"+sigHint)
+		// Path parameters are not bound to arguments of raw endpoints,
+		// so explain how to access them if it looks like that was the intent.
+		var note string
+		for _, s := range rpc.Path.Segments {
+			if s.Type != paths.Literal {
+				note = fmt.Sprintf("\n\tnote: raw APIs do not receive path parameters as function parameters;"+
+					" read them from the request instead, like encore.CurrentRequest().PathParams.Get(%q)", s.Value)
+				break
+			}
+		}
+		p.err(params.Pos(), "invalid API signature (too many parameters)"+note+sigHint)
 		return
 	} else if results := rpc.Func.Type.Results; results.NumFields() != 0 {
-		p.err(params.Pos(), "invalid API signature (too many results)"+sigHint)
+		p.err(results.Pos(), "raw APIs cannot declare results (response data must be written to the http.ResponseWriter)"+sigHint)
 		return
 	}
 
-	info := p.names[rpc.Svc.Root].Files[rpc.File]
-
 	{
 		// First type should always be http.ResponseWriter
 		rw := params.List[0].Type
@@ -446,7 +1063,7 @@ func (p *parser) parseAuthHandler(h *est.AuthHandler) {
 
 	// Second param must be string or named type pointing to a struct
 	authInfo, _ := getField(params, 1)
-	paramType := p.resolveType(h.Svc.Root, h.File, authInfo.Type, nil)
+	paramType := p.unalias(p.resolveType(h.Svc.Root, h.File, authInfo.Type, nil))
 	switch typ := paramType.Typ.(type) {
 	case *schema.Type_Named:
 		decl := p.decls[typ.Named.Id]
@@ -515,6 +1132,17 @@ func (p *parser) parseAuthHandler(h *est.AuthHandler) {
 }
 
 func (p *parser) resolveParameter(parameterType string, pkg *est.Package, file *est.File, expr ast.Expr) *est.Param {
+	if star, ok := expr.(*ast.StarExpr); ok {
+		if _, ok := star.X.(*ast.StarExpr); ok {
+			p.errf(expr.Pos(), "%s cannot be a pointer to a pointer (got %s, use *%s instead)",
+				parameterType, types.ExprString(expr), types.ExprString(deref(expr)))
+		}
+	}
+	if p.isInterfaceType(pkg, file, deref(expr)) {
+		p.errf(expr.Pos(), "%s must be a struct type, not the interface type %s", parameterType, types.ExprString(deref(expr)))
+		p.abort()
+	}
+
 	typ := p.resolveType(pkg, file, expr, nil)
 
 	// Check it's a supported parameter type (i.e. a named type which is a structure)
@@ -524,15 +1152,149 @@ func (p *parser) resolveParameter(parameterType string, pkg *est.Package, file *
 		p.abort()
 	}
 
-	if p.decls[n.Id].Type.GetStruct() == nil {
+	st := p.concreteStruct(n)
+	if st == nil {
 		p.errf(expr.Pos(), "%s must be a struct type", parameterType)
 	}
 	_, isPtr := expr.(*ast.StarExpr)
 
 	return &est.Param{
-		IsPtr: isPtr,
-		Type:  typ,
+		IsPtr:  isPtr,
+		Type:   typ,
+		Pos:    expr.Pos(),
+		Struct: st,
+	}
+}
+
+// isInterfaceType reports whether expr refers to a named interface type
+// declared in the app, like "Iface" or "pkg.Iface".
+func (p *parser) isInterfaceType(pkg *est.Package, file *est.File, expr ast.Expr) bool {
+	var decl *names.PkgDecl
+	switch expr := expr.(type) {
+	case *ast.Ident:
+		decl = p.names[pkg].Decls[expr.Name]
+	case *ast.SelectorExpr:
+		if pkgName, ok := expr.X.(*ast.Ident); ok {
+			pkgPath := p.names[pkg].Files[file].NameToPath[pkgName.Name]
+			if otherPkg, ok := p.pkgMap[pkgPath]; ok {
+				decl = p.names[otherPkg].Decls[expr.Sel.Name]
+			}
+		}
+	}
+	if decl == nil || decl.Type != token.TYPE {
+		return false
+	}
+	ts, ok := decl.Spec.(*ast.TypeSpec)
+	if !ok {
+		return false
+	}
+	_, ok = ts.Type.(*ast.InterfaceType)
+	return ok
+}
+
+// validateParamPassing warns about API request and response types that are
+// passed by value when most APIs pass them by pointer, or vice versa.
+// Mixing the two styles makes generated clients inconsistent.
+func (p *parser) validateParamPassing() {
+	type param struct {
+		rpc  *est.RPC
+		kind string
+		*est.Param
+	}
+	var ptrs, values []param
+	for _, svc := range p.svcs {
+		for _, rpc := range svc.RPCs {
+			for _, prm := range []param{{rpc, "request", rpc.Request}, {rpc, "response", rpc.Response}} {
+				if prm.Param == nil {
+					continue
+				} else if prm.IsPtr {
+					ptrs = append(ptrs, prm)
+				} else {
+					values = append(values, prm)
+				}
+			}
+		}
+	}
+	if len(ptrs) == 0 || len(values) == 0 {
+		return
+	}
+
+	// Warn about the less common style, preferring pointers in case of a tie.
+	minority, used, common := values, "by value", "by pointer"
+	if len(ptrs) < len(values) {
+		minority, used, common = ptrs, "by pointer", "by value"
+	}
+	for _, prm := range minority {
+		p.warnf(prm.Pos, "API %s.%s passes its %s type %s, while most APIs pass them %s: consider using a consistent style",
+			prm.rpc.Svc.Name, prm.rpc.Name, prm.kind, used, common)
+	}
+}
+
+// requestFields describes where each field of an API's request data is decoded from.
+// Fields without a header or query tag are decoded from the body,
+// and fields omitted with a "-" tag name are skipped.
+func (p *parser) requestFields(req *est.Param) []*est.RequestField {
+	st := req.Struct
+	var fields []*est.RequestField
+Fields:
+	for _, f := range st.GetFields() {
+		rf := &est.RequestField{Name: f.Name, Source: est.BodySource}
+		for _, tag := range f.Tags {
+			switch tag.Key {
+			case "header", "query", "qs", "json":
+				if tag.Name == "-" {
+					continue Fields
+				}
+			}
+		}
+		for _, tag := range f.Tags {
+			switch tag.Key {
+			case "header":
+				rf.Source, rf.SourceName = est.HeaderSource, tag.Name
+				if rf.SourceName == "" {
+					rf.SourceName = f.Name
+				}
+			case "query", "qs":
+				rf.Source, rf.SourceName = est.QuerySource, tag.Name
+				if rf.SourceName == "" {
+					rf.SourceName = SnakeCase(f.Name)
+				}
+			}
+		}
+		fields = append(fields, rf)
+	}
+	return fields
+}
+
+// responseFields describes where each field of an API's response data is encoded to.
+// Fields without a header tag are encoded in the body, and fields omitted
+// with a "-" tag name are skipped. Responses have no query string, so query
+// tags are ignored, which lets request types double as response types.
+func (p *parser) responseFields(resp *est.Param) []*est.ResponseField {
+	st := resp.Struct
+	var fields []*est.ResponseField
+Fields:
+	for _, f := range st.GetFields() {
+		rf := &est.ResponseField{Name: f.Name, Source: est.BodySource}
+		for _, tag := range f.Tags {
+			switch tag.Key {
+			case "header", "json":
+				if tag.Name == "-" {
+					continue Fields
+				}
+			}
+		}
+		for _, tag := range f.Tags {
+			if tag.Key == "header" {
+				rf.Source, rf.SourceName = est.HeaderSource, tag.Name
+				if rf.SourceName == "" {
+					rf.SourceName = f.Name
+				}
+			}
+		}
+		fields = append(fields, rf)
 	}
+	return fields
 }
 
 var errNotFound = errors.New("not found")
@@ -1,7 +1,127 @@
 package parser
 
 import (
+	"go/ast"
+	"go/token"
+	"strconv"
+
+	"github.com/golang/protobuf/proto"
+
+	"encr.dev/parser/est"
 	schema "encr.dev/proto/encore/parser/schema/v1"
 )
 
 type typeParameterLookup map[string]*schema.TypeParameterRef
+
+// funcTypeParams returns the type parameters of fd, or nil if it has none.
+// It is overridden for Go versions supporting generics.
+var funcTypeParams = func(fd *ast.FuncDecl) *ast.FieldList { return nil }
+
+// concreteStruct returns the struct type declared by named with the type arguments
+// of named substituted for the type parameters of its declaration.
+// Type aliases are followed to the declarations they refer to.
+// It returns nil if the declaration is not a struct type.
+func (p *parser) concreteStruct(named *schema.Named) *schema.Struct {
+	decl := p.decls[named.Id]
+	if n := decl.Type.GetNamed(); n != nil && p.aliases[named.Id] != nil {
+		return p.concreteStruct(n)
+	} else if decl.Type.GetStruct() == nil {
+		return nil
+	}
+	typ := proto.Clone(decl.Type).(*schema.Type)
+	substituteTypeArgs(typ, named.Id, named.TypeArguments)
+	return typ.GetStruct()
+}
+
+// substituteTypeArgs replaces, in place, the references within typ to the
+// type parameters of the declaration with the given id by the corresponding type arguments.
+func substituteTypeArgs(typ *schema.Type, declID uint32, args []*schema.Type) {
+	switch t := typ.Typ.(type) {
+	case *schema.Type_TypeParameter:
+		if ref := t.TypeParameter; ref.DeclId == declID && int(ref.ParamIdx) < len(args) {
+			typ.Typ = proto.Clone(args[ref.ParamIdx]).(*schema.Type).Typ
+		}
+	case *schema.Type_Named:
+		for _, arg := range t.Named.TypeArguments {
+			substituteTypeArgs(arg, declID, args)
+		}
+	case *schema.Type_List:
+		substituteTypeArgs(t.List.Elem, declID, args)
+	case *schema.Type_Map:
+		substituteTypeArgs(t.Map.Key, declID, args)
+		substituteTypeArgs(t.Map.Value, declID, args)
+	case *schema.Type_Struct:
+		for _, f := range t.Struct.Fields {
+			substituteTypeArgs(f.Typ, declID, args)
+		}
+	}
+}
+
+// enumValues returns the values of the exported constants of the named type
+// typeName declared in pkg, in declaration order, if typ (the type the name
+// denotes) is a builtin type. This lets types like "type Status string" with
+// a set of constants be described as enums.
+//
+// Constants count if declared with the type, like `const A Status = "a"`,
+// or by implicitly repeating such a declaration within a const group (as is common
+// with iota). Constants whose values are not literals or iota are skipped.
+func (p *parser) enumValues(pkg *est.Package, typeName string, typ *schema.Type) []string {
+	if _, ok := typ.Typ.(*schema.Type_Builtin); !ok {
+		return nil
+	}
+
+	var values []string
+	for _, f := range pkg.Files {
+		for _, decl := range f.AST.Decls {
+			gd, ok := decl.(*ast.GenDecl)
+			if !ok || gd.Tok != token.CONST {
+				continue
+			}
+			// The type and values of the last explicit specification,
+			// which specifications without values repeat.
+			var specType ast.Expr
+			var specValues []ast.Expr
+			for iota, spec := range gd.Specs {
+				vs := spec.(*ast.ValueSpec)
+				if vs.Type != nil || len(vs.Values) > 0 {
+					specType, specValues = vs.Type, vs.Values
+				}
+				if id, ok := specType.(*ast.Ident); !ok || id.Name != typeName {
+					continue
+				}
+				for i, name := range vs.Names {
+					if !name.IsExported() || i >= len(specValues) {
+						continue
+					}
+					if v, ok := constValue(specValues[i], iota); ok {
+						values = append(values, v)
+					}
+				}
+			}
+		}
+	}
+	return values
+}
+
+// constValue returns the value of the constant expression x
+// if it is a literal or iota, which has the given value.
+func constValue(x ast.Expr, iota int) (string, bool) {
+	switch x := x.(type) {
+	case *ast.ParenExpr:
+		return constValue(x.X, iota)
+	case *ast.Ident:
+		if x.Name == "iota" {
+			return strconv.Itoa(iota), true
+		}
+	case *ast.BasicLit:
+		switch x.Kind {
+		case token.STRING:
+			if s, err := strconv.Unquote(x.Value); err == nil {
+				return s, true
+			}
+		case token.INT, token.FLOAT:
+			return x.Value, true
+		}
+	}
+	return "", false
+}
@@ -26,3 +26,19 @@ func SnakeCase(s string) string {
 
 	return string(out)
 }
+
+// camelCase converts exported Go names to camelCase by lowercasing
+// the leading uppercase letters, keeping the last one of an initialism
+// in uppercase when it starts a new word ("HTTPServer" becomes "httpServer").
+func camelCase(s string) string {
+	in := []rune(s)
+	for i, r := range in {
+		if !unicode.IsUpper(r) {
+			break
+		} else if i > 0 && i+1 < len(in) && unicode.IsLower(in[i+1]) {
+			break
+		}
+		in[i] = unicode.ToLower(r)
+	}
+	return string(in)
+}
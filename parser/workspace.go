@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// A workspaceModule is a module listed in a go.work file.
+type workspaceModule struct {
+	Dir  string // directory of the module, relative to the app root in slash form
+	Path string // module path
+}
+
+// readWorkspace reads the go.work file in appRoot, if any, and returns
+// the modules it uses. It returns nil, nil if there is no go.work file.
+//
+// The module rooted in appRoot, if used, is given the path modulePath
+// if it is non-empty. All other module paths are read from their go.mod files.
+func readWorkspace(fsys fs.FS, appRoot, modulePath string) ([]workspaceModule, error) {
+	workPath := filepath.Join(appRoot, "go.work")
+	data, err := fs.ReadFile(fsys, workPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	wf, err := modfile.ParseWork(workPath, data, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var mods []workspaceModule
+	seen := make(map[string]string) // module path -> dir
+	for _, use := range wf.Use {
+		dir := use.Path
+		if !filepath.IsAbs(dir) {
+			dir = filepath.Join(appRoot, dir)
+		}
+		rel, err := filepath.Rel(appRoot, dir)
+		if err != nil {
+			return nil, err
+		}
+		rel = filepath.ToSlash(rel)
+		if rel == ".." || strings.HasPrefix(rel, "../") {
+			return nil, fmt.Errorf("%s: workspace module %s is outside the app root", workPath, use.Path)
+		}
+
+		mod := workspaceModule{Dir: path.Clean(rel), Path: modulePath}
+		if mod.Dir != "." || mod.Path == "" {
+			if mod.Path, err = readModulePath(fsys, dir); err != nil {
+				return nil, err
+			}
+		}
+		if prev, ok := seen[mod.Path]; ok {
+			return nil, fmt.Errorf("%s: module %s is used twice (in %s and %s)", workPath, mod.Path, prev, mod.Dir)
+		}
+		seen[mod.Path] = mod.Dir
+		mods = append(mods, mod)
+	}
+	if len(mods) == 0 {
+		return nil, fmt.Errorf("%s: workspace does not use any modules", workPath)
+	}
+	return mods, nil
+}
+
+// mainModulePath returns the module path of the app in the workspace:
+// the module rooted in the app root if it is used, and otherwise
+// the first module listed.
+func mainModulePath(mods []workspaceModule) string {
+	for _, m := range mods {
+		if m.Dir == "." {
+			return m.Path
+		}
+	}
+	return mods[0].Path
+}
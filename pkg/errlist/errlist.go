@@ -10,12 +10,28 @@ import (
 )
 
 type List struct {
-	list scanner.ErrorList
-	fset *token.FileSet
+	list     scanner.ErrorList
+	warnings scanner.ErrorList
+	fset     *token.FileSet
+	spans    map[*scanner.Error]*Span
+	max      int  // max number of errors; <= 0 means no limit
+	bailed   bool // whether the list bailed out because of too many errors
+
+	formatter Formatter // formats the list for Error and Print, if non-nil
 }
 
+// DefaultMaxErrors is the number of errors a List returned
+// by New accepts before bailing out.
+const DefaultMaxErrors = 10
+
 func New(fset *token.FileSet) *List {
-	return &List{fset: fset}
+	return &List{fset: fset, max: DefaultMaxErrors}
+}
+
+// SetMaxErrors sets the number of errors the list accepts
+// before bailing out. If n <= 0 there is no limit.
+func (l *List) SetMaxErrors(n int) {
+	l.max = n
 }
 
 // Add adds an error to the list.
@@ -24,14 +40,7 @@ func New(fset *token.FileSet) *List {
 // with a Bailout value to abort processing.
 // Use HandleBailout to conveniently handle this.
 func (l *List) Add(pos token.Pos, msg string) {
-	pp := l.fset.Position(pos)
-	n := len(l.list)
-	if n > 0 && l.list[n-1].Pos.Line == pp.Line {
-		return // spurious
-	} else if n > 10 {
-		panic(Bailout{err: l})
-	}
-	addErrToList(&l.list, pp, msg)
+	l.add(l.fset.Position(pos), msg)
 }
 
 // Addf is equivalent to Add(pos, fmt.Sprintf(format, args...))
@@ -45,57 +54,204 @@ func (l *List) Addf(pos token.Pos, format string, args ...interface{}) {
 // with a Bailout value to abort processing.
 // Use HandleBailout to conveniently handle this.
 func (l *List) AddRaw(err *scanner.Error) {
+	l.add(err.Pos, err.Msg)
+}
+
+// AddSpan is like Add, but additionally records the range of source code
+// from start to end (exclusive) that the error applies to, for tools that
+// want to highlight it. The error itself is reported at pos.
+func (l *List) AddSpan(pos, start, end token.Pos, msg string) {
+	if e := l.add(l.fset.Position(pos), msg); e != nil {
+		if l.spans == nil {
+			l.spans = make(map[*scanner.Error]*Span)
+		}
+		l.spans[e] = &Span{Start: l.fset.Position(start), End: l.fset.Position(end)}
+	}
+}
+
+// Span returns the range of source code that e applies to,
+// if it was added with AddSpan. Otherwise it reports nil.
+func (l *List) Span(e *scanner.Error) *Span {
+	return l.spans[e]
+}
+
+// A Span is a range of source code.
+type Span struct {
+	Start token.Position
+	End   token.Position // exclusive
+}
+
+// add adds an error to the list, returning the added error.
+// It returns nil if the error was discarded as spurious.
+func (l *List) add(pos token.Position, msg string) *scanner.Error {
 	n := len(l.list)
-	if n > 0 && l.list[n-1].Pos.Line == err.Pos.Line {
-		return // spurious
-	} else if n > 10 {
+	if l.bailed {
 		panic(Bailout{err: l})
+	} else if n > 0 && l.list[n-1].Pos.Line == pos.Line && l.list[n-1].Pos.Filename == pos.Filename {
+		return nil // spurious
+	} else if l.full() {
+		l.bail()
 	}
-	addErrToList(&l.list, err.Pos, err.Msg)
+	addErrToList(&l.list, pos, msg)
+	return l.list[len(l.list)-1]
+}
+
+// full reports whether the list has reached its max number of errors.
+func (l *List) full() bool {
+	return l.max > 0 && len(l.list) >= l.max
+}
+
+// bail marks the list as having too many errors, adding a trailing
+// "too many errors" marker, and panics with a Bailout value.
+func (l *List) bail() {
+	if !l.bailed {
+		l.bailed = true
+		l.list = append(l.list, &scanner.Error{Msg: "too many errors"})
+	}
+	panic(Bailout{err: l})
+}
+
+// Bailed reports whether processing was aborted because
+// the list reached its max number of errors.
+func (l *List) Bailed() bool {
+	return l.bailed
+}
+
+// AddWarning adds a warning to the list.
+//
+// Warnings are tracked separately from errors: they never
+// cause Err to report an error and never trigger a bailout.
+func (l *List) AddWarning(pos token.Pos, msg string) {
+	addErrToList(&l.warnings, l.fset.Position(pos), msg)
+}
+
+// AddWarningf is equivalent to AddWarning(pos, fmt.Sprintf(format, args...))
+func (l *List) AddWarningf(pos token.Pos, format string, args ...interface{}) {
+	l.AddWarning(pos, fmt.Sprintf(format, args...))
+}
+
+// Errors returns the errors added to the list, including
+// the "too many errors" marker if the list bailed out.
+func (l *List) Errors() scanner.ErrorList {
+	return l.list
+}
+
+// Warnings returns the warnings added to the list.
+func (l *List) Warnings() scanner.ErrorList {
+	return l.warnings
+}
+
+// PromoteWarnings adds copies of the warnings to the errors, so that
+// Err reports them. The warnings are still reported by Warnings.
+//
+// Unlike Add it never bails out: promoting warnings is meant to be
+// done once processing has completed.
+func (l *List) PromoteWarnings() {
+	n := l.Len()
+	list := make(scanner.ErrorList, 0, len(l.list)+len(l.warnings))
+	list = append(list, l.list[:n]...)
+	for _, w := range l.warnings {
+		e := *w
+		list = append(list, &e)
+	}
+	// Keep the "too many errors" marker, if any.
+	l.list = append(list, l.list[n:]...)
 }
 
 // Merge merges another list into this one.
 // The token.FileSet in use must be the same one as this one,
 // or else it panics.
+//
+// Like Add, it panics with a Bailout value if
+// the merged list has too many errors.
 func (l *List) Merge(other *List) {
 	if other.fset != l.fset {
 		panic("errlist: cannot merge lists with different *token.FileSets")
 	}
-	l.list = append(l.list, other.list...)
+	l.warnings = append(l.warnings, other.warnings...)
+	for _, e := range other.list[:other.Len()] {
+		if l.bailed || l.full() {
+			l.bail()
+		}
+		l.list = append(l.list, e)
+		if span := other.spans[e]; span != nil {
+			if l.spans == nil {
+				l.spans = make(map[*scanner.Error]*Span)
+			}
+			l.spans[e] = span
+		}
+	}
 }
 
-// Err returns an error equivalent to this error list.
+// Err returns an error equivalent to this error list,
+// with the errors sorted as by Sort.
 // If the list is empty, Err returns nil.
 func (l *List) Err() error {
 	if len(l.list) == 0 {
 		return nil
 	}
+	l.Sort()
 	return l
 }
 
 // Error implements the error interface.
+// If the list has a formatter it formats the list with it.
 func (l *List) Error() string {
+	if l.formatter != nil {
+		return l.formatted(l.formatter)
+	}
 	return l.list.Error()
 }
 
-// Sort sorts the error list.
+// Sort sorts the error and warning lists by filename, line and column,
+// with ties ordered by message. Identical errors reported at the same
+// position more than once are removed.
 func (l *List) Sort() {
-	l.list.Sort()
+	if l.bailed {
+		// Keep the "too many errors" marker last.
+		n := len(l.list) - 1
+		marker := l.list[n]
+		l.list = append(sortUnique(l.list[:n]), marker)
+	} else {
+		l.list = sortUnique(l.list)
+	}
+	l.warnings = sortUnique(l.warnings)
 }
 
-// MakeRelative rewrites the errors by making filenames within the
+// sortUnique sorts list and removes duplicate errors in place.
+func sortUnique(list scanner.ErrorList) scanner.ErrorList {
+	list.Sort()
+	out := list[:0]
+	for _, e := range list {
+		if n := len(out); n > 0 && e.Pos == out[n-1].Pos && e.Msg == out[n-1].Msg {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// MakeRelative rewrites the errors, warnings and spans by making filenames within the
 // app root relative to the relwd (which must be a relative path
 // within the root).
 func (l *List) MakeRelative(root, relwd string) {
 	wdroot := filepath.Join(root, relwd)
-	for _, e := range l.list {
-		fn := e.Pos.Filename
-		if strings.HasPrefix(fn, root) {
-			if rel, err := filepath.Rel(wdroot, fn); err == nil {
-				e.Pos.Filename = rel
+	makeRel := func(pos *token.Position) {
+		if strings.HasPrefix(pos.Filename, root) {
+			if rel, err := filepath.Rel(wdroot, pos.Filename); err == nil {
+				pos.Filename = rel
 			}
 		}
 	}
+	for _, list := range []scanner.ErrorList{l.list, l.warnings} {
+		for _, e := range list {
+			makeRel(&e.Pos)
+		}
+	}
+	for _, span := range l.spans {
+		makeRel(&span.Start)
+		makeRel(&span.End)
+	}
 }
 
 // HandleBailout handles bailouts raised by (*List).Add and family
@@ -110,7 +266,12 @@ func (l *List) HandleBailout(err *error) {
 	}
 }
 
+// Len reports the number of errors in the list,
+// not counting the "too many errors" marker.
 func (l *List) Len() int {
+	if l.bailed {
+		return len(l.list) - 1
+	}
 	return len(l.list)
 }
 
@@ -125,11 +286,11 @@ func (l *List) Abort() {
 type Bailout struct{ err error }
 
 // Print is a utility function that prints a list of errors to w,
-// one error per line, if the err parameter is an ErrorList. Otherwise
-// it prints the err string.
+// one error per line or as formatted by the list's formatter, if the err
+// parameter is a *List. Otherwise it prints the err string.
 func Print(w io.Writer, err error) {
 	if l, ok := err.(*List); ok {
-		scanner.PrintError(w, l.list)
+		l.Format(w, nil)
 	} else if err != nil {
 		fmt.Fprintf(w, "%s\n", err)
 	}
@@ -0,0 +1,275 @@
+package errlist
+
+import (
+	"bytes"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func TestSort(t *testing.T) {
+	fset := token.NewFileSet()
+	a := fset.AddFile("a.go", -1, 100)
+	a.SetLines([]int{0, 10, 20, 30})
+	b := fset.AddFile("b.go", -1, 100)
+	b.SetLines([]int{0, 10, 20, 30})
+
+	l := New(fset)
+	l.Add(b.Pos(12), "b2")
+	l.Add(a.Pos(25), "a3")
+	l.Add(a.Pos(3), "a1")
+
+	// Errors on the same line are dropped by Add,
+	// so merge in duplicates from other lists.
+	other := New(fset)
+	other.Add(a.Pos(25), "a3")
+	other.Add(a.Pos(3), "a1 other")
+	l.Merge(other)
+	other = New(fset)
+	other.Add(b.Pos(12), "b2")
+	l.Merge(other)
+
+	err := l.Err()
+	if err == nil {
+		t.Fatal("got nil error, want errors")
+	}
+
+	var got []string
+	for _, e := range l.list {
+		got = append(got, e.Error())
+	}
+	want := []string{
+		"a.go:1:4: a1",
+		"a.go:1:4: a1 other",
+		"a.go:3:6: a3",
+		"b.go:2:3: b2",
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("got errors:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	if l.Len() != len(want) {
+		t.Errorf("got Len() = %d, want %d", l.Len(), len(want))
+	}
+}
+
+func TestAddSameLine(t *testing.T) {
+	fset := token.NewFileSet()
+	a := fset.AddFile("a.go", -1, 100)
+	a.SetLines([]int{0, 10, 20, 30})
+	b := fset.AddFile("b.go", -1, 100)
+	b.SetLines([]int{0, 10, 20, 30})
+
+	// A second error on the same line of the same file is dropped,
+	// but not one on the same line of another file.
+	l := New(fset)
+	l.Add(a.Pos(12), "a1")
+	l.Add(a.Pos(15), "a2")
+	l.Add(b.Pos(12), "b1")
+	if l.Len() != 2 {
+		t.Errorf("got Len() = %d, want 2", l.Len())
+	}
+}
+
+func TestMaxErrors(t *testing.T) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("a.go", -1, 1000)
+	lines := make([]int, 100)
+	for i := range lines {
+		lines[i] = i * 10
+	}
+	f.SetLines(lines)
+
+	// addAll adds an error on each line of the file,
+	// reporting whether the list bailed out.
+	addAll := func(l *List) (bailed bool) {
+		defer func() {
+			if e := recover(); e != nil {
+				if _, ok := e.(Bailout); !ok {
+					panic(e)
+				}
+				bailed = true
+			}
+		}()
+		for i := len(lines) - 1; i >= 0; i-- {
+			l.Add(f.Pos(lines[i]), "err")
+		}
+		return false
+	}
+
+	l := New(fset)
+	l.SetMaxErrors(5)
+	if !addAll(l) {
+		t.Fatal("list did not bail out")
+	}
+	if !l.Bailed() {
+		t.Error("got Bailed() = false, want true")
+	}
+	if l.Len() != 5 {
+		t.Errorf("got Len() = %d, want 5", l.Len())
+	}
+	l.Sort()
+	if last := l.list[len(l.list)-1].Error(); last != "too many errors" {
+		t.Errorf("got last error %q, want %q", last, "too many errors")
+	}
+
+	// Merging in more errors must respect the limit.
+	l = New(fset)
+	l.SetMaxErrors(5)
+	other := New(fset)
+	other.SetMaxErrors(0)
+	if addAll(other) {
+		t.Fatal("list without limit bailed out")
+	}
+	if other.Bailed() || other.Len() != len(lines) {
+		t.Fatalf("got Bailed() = %v, Len() = %d, want false, %d", other.Bailed(), other.Len(), len(lines))
+	}
+	func() {
+		defer l.HandleBailout(new(error))
+		l.Merge(other)
+	}()
+	if !l.Bailed() || l.Len() != 5 {
+		t.Errorf("got Bailed() = %v, Len() = %d after Merge, want true, 5", l.Bailed(), l.Len())
+	}
+}
+
+func TestAddSpan(t *testing.T) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("/app/svc/a.go", -1, 100)
+	f.SetLines([]int{0, 10, 20, 30})
+
+	l := New(fset)
+	l.Add(f.Pos(3), "no span")
+	l.AddSpan(f.Pos(20), f.Pos(24), f.Pos(28), "with span")
+	l.MakeRelative("/app", ".")
+	l.Sort()
+
+	if span := l.Span(l.list[0]); span != nil {
+		t.Errorf("got span %v for error added without span, want nil", span)
+	}
+	e := l.list[1]
+	if got, want := e.Error(), "svc/a.go:3:1: with span"; got != want {
+		t.Errorf("got error %q, want %q", got, want)
+	}
+	span := l.Span(e)
+	if span == nil {
+		t.Fatal("got nil span")
+	}
+	if got, want := span.Start.String(), "svc/a.go:3:5"; got != want {
+		t.Errorf("got span start %s, want %s", got, want)
+	}
+	if got, want := span.End.String(), "svc/a.go:3:9"; got != want {
+		t.Errorf("got span end %s, want %s", got, want)
+	}
+
+	// Spans are kept when merging lists.
+	merged := New(fset)
+	merged.Merge(l)
+	if merged.Span(e) != span {
+		t.Error("span not kept by Merge")
+	}
+}
+
+func TestPromoteWarnings(t *testing.T) {
+	fset := token.NewFileSet()
+	f := fset.AddFile("a.go", -1, 100)
+	f.SetLines([]int{0, 10, 20, 30})
+
+	l := New(fset)
+	l.AddWarning(f.Pos(10), "warning")
+	if err := l.Err(); err != nil {
+		t.Fatalf("got err %v before promoting warnings, want nil", err)
+	}
+
+	l.Add(f.Pos(20), "error")
+	l.PromoteWarnings()
+	l.Sort()
+	if got, want := l.Error(), "a.go:2:1: warning (and 1 more errors)"; got != want {
+		t.Errorf("got error %q, want %q", got, want)
+	}
+	if l.Len() != 2 {
+		t.Errorf("got Len() = %d, want 2", l.Len())
+	}
+	if w := l.Warnings(); len(w) != 1 || w[0].Msg != "warning" {
+		t.Errorf("got warnings %v, want the single warning", w)
+	}
+}
+
+func TestFormat(t *testing.T) {
+	newList := func() *List {
+		fset := token.NewFileSet()
+		f := fset.AddFile("a.go", -1, 100)
+		f.SetLines([]int{0, 10, 20, 30})
+
+		l := New(fset)
+		l.SetMaxErrors(2)
+		func() {
+			defer l.HandleBailout(new(error))
+			l.AddSpan(f.Pos(22), f.Pos(22), f.Pos(25), "second")
+			l.Add(f.Pos(3), "first")
+			l.Add(f.Pos(12), "third")
+		}()
+		return l
+	}
+
+	tests := []struct {
+		name string
+		f    Formatter
+		want string
+	}{
+		{
+			name: "plain",
+			f:    Plain,
+			want: "a.go:1:4: first\n" +
+				"a.go:3:3: second\n" +
+				"too many errors\n",
+		},
+		{
+			name: "ansi",
+			f:    ANSI,
+			want: "\x1b[1ma.go:1:4:\x1b[0m \x1b[31mfirst\x1b[0m\n" +
+				"\x1b[1ma.go:3:3:\x1b[0m \x1b[31msecond\x1b[0m\n" +
+				"\x1b[31mtoo many errors\x1b[0m\n",
+		},
+		{
+			name: "json",
+			f:    JSON,
+			want: `{"filename":"a.go","line":1,"column":4,"message":"first"}` + "\n" +
+				`{"filename":"a.go","line":3,"column":3,"message":"second","span":{"start":{"line":3,"column":3},"end":{"line":3,"column":6}}}` + "\n" +
+				`{"message":"too many errors"}` + "\n",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			l := newList()
+			var buf bytes.Buffer
+			if err := l.Format(&buf, test.f); err != nil {
+				t.Fatal(err)
+			}
+			if got := buf.String(); got != test.want {
+				t.Errorf("got Format output:\n%q\nwant:\n%q", got, test.want)
+			}
+
+			// Error and Print use the list's formatter.
+			l.SetFormatter(test.f)
+			if got, want := l.Error(), strings.TrimSuffix(test.want, "\n"); got != want {
+				t.Errorf("got Error() = %q, want %q", got, want)
+			}
+			buf.Reset()
+			Print(&buf, l)
+			if got := buf.String(); got != test.want {
+				t.Errorf("got Print output:\n%q\nwant:\n%q", got, test.want)
+			}
+		})
+	}
+
+	// Without a formatter Error is unchanged, and Format and Print use Plain.
+	l := newList()
+	if got, want := l.Err().Error(), "a.go:1:4: first (and 2 more errors)"; got != want {
+		t.Errorf("got Error() = %q without formatter, want %q", got, want)
+	}
+	var buf bytes.Buffer
+	Print(&buf, l)
+	if got, want := buf.String(), tests[0].want; got != want {
+		t.Errorf("got Print output without formatter:\n%q\nwant:\n%q", got, want)
+	}
+}
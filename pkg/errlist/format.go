@@ -0,0 +1,138 @@
+package errlist
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/token"
+	"io"
+	"strings"
+)
+
+// A Formatter formats the errors of a List, for front-ends that want
+// them in a format other than plain text, such as JSON.
+type Formatter interface {
+	// Format writes the errors in l to w, in the order they are in.
+	Format(w io.Writer, l *List) error
+}
+
+// Built-in formatters.
+var (
+	// Plain formats errors as plain text, one error per line,
+	// like Print does by default.
+	Plain Formatter = plainFormatter{}
+
+	// ANSI formats errors like Plain, but highlights them
+	// with ANSI escape codes for display in a terminal.
+	ANSI Formatter = ansiFormatter{}
+
+	// JSON formats errors as JSON objects, one per line.
+	// Positions and spans are omitted when unknown.
+	JSON Formatter = jsonFormatter{}
+)
+
+// SetFormatter sets the formatter used by Error and Print to format
+// the list. If f is nil, which is the default, Error reports the first
+// error and the number of additional errors, like scanner.ErrorList.
+func (l *List) SetFormatter(f Formatter) {
+	l.formatter = f
+}
+
+// Format sorts the list as by Sort and writes its errors to w using f.
+// If f is nil it uses the list's formatter, or else Plain.
+func (l *List) Format(w io.Writer, f Formatter) error {
+	if f == nil {
+		f = l.formatter
+	}
+	if f == nil {
+		f = Plain
+	}
+	l.Sort()
+	return f.Format(w, l)
+}
+
+type plainFormatter struct{}
+
+func (plainFormatter) Format(w io.Writer, l *List) error {
+	for _, e := range l.list {
+		if _, err := fmt.Fprintf(w, "%s\n", e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+const (
+	ansiBold  = "\x1b[1m"
+	ansiRed   = "\x1b[31m"
+	ansiReset = "\x1b[0m"
+)
+
+type ansiFormatter struct{}
+
+func (ansiFormatter) Format(w io.Writer, l *List) error {
+	for _, e := range l.list {
+		var err error
+		if e.Pos.Filename != "" || e.Pos.IsValid() {
+			_, err = fmt.Fprintf(w, "%s%s:%s %s%s%s\n", ansiBold, e.Pos, ansiReset, ansiRed, e.Msg, ansiReset)
+		} else {
+			_, err = fmt.Fprintf(w, "%s%s%s\n", ansiRed, e.Msg, ansiReset)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type jsonFormatter struct{}
+
+type jsonError struct {
+	Filename string    `json:"filename,omitempty"`
+	Line     int       `json:"line,omitempty"`
+	Column   int       `json:"column,omitempty"`
+	Message  string    `json:"message"`
+	Span     *jsonSpan `json:"span,omitempty"`
+}
+
+type jsonSpan struct {
+	Start jsonPos `json:"start"`
+	End   jsonPos `json:"end"`
+}
+
+type jsonPos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+func (jsonFormatter) Format(w io.Writer, l *List) error {
+	enc := json.NewEncoder(w)
+	for _, e := range l.list {
+		je := jsonError{
+			Filename: e.Pos.Filename,
+			Line:     e.Pos.Line,
+			Column:   e.Pos.Column,
+			Message:  e.Msg,
+		}
+		if span := l.Span(e); span != nil {
+			je.Span = &jsonSpan{Start: toJSONPos(span.Start), End: toJSONPos(span.End)}
+		}
+		if err := enc.Encode(je); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func toJSONPos(pos token.Position) jsonPos {
+	return jsonPos{Line: pos.Line, Column: pos.Column}
+}
+
+// formatted returns the list formatted by f, without a trailing newline.
+func (l *List) formatted(f Formatter) string {
+	var buf bytes.Buffer
+	if err := f.Format(&buf, l); err != nil {
+		return l.list.Error()
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
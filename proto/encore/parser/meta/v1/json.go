@@ -0,0 +1,64 @@
+package v1
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// JSONSchemaVersion is the version of the JSON representation of Data
+// produced by MarshalJSON. It is incremented whenever the representation
+// changes in a way consumers need to know about, such as when fields
+// are added to, removed from or renamed in meta.proto or in the
+// schema.proto messages it references.
+const JSONSchemaVersion = 5
+
+// jsonData is the JSON representation of Data.
+type jsonData struct {
+	SchemaVersion int             `json:"schema_version"`
+	Meta          json.RawMessage `json:"meta"`
+}
+
+// MarshalJSON encodes the metadata as a JSON object of the form
+//
+//	{"schema_version": 5, "meta": {...}}
+//
+// where schema_version is JSONSchemaVersion and meta is the protobuf JSON
+// encoding of d, using the field names from meta.proto and including fields
+// with default values.
+//
+// The encoding is deterministic: fields are encoded in field number order,
+// map keys are sorted, and repeated fields keep the order of d, which the
+// parser computes deterministically.
+func (d *Data) MarshalJSON() ([]byte, error) {
+	meta, err := protojson.MarshalOptions{
+		UseProtoNames:   true,
+		EmitUnpopulated: true,
+	}.Marshal(d)
+	if err != nil {
+		return nil, err
+	}
+	// Compact the encoding to remove the randomized
+	// whitespace protojson inserts.
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `{"schema_version":%d,"meta":`, JSONSchemaVersion)
+	if err := json.Compact(&buf, meta); err != nil {
+		return nil, err
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes metadata encoded by MarshalJSON.
+// It reports an error if the data was encoded with a different schema version.
+func (d *Data) UnmarshalJSON(data []byte) error {
+	var jd jsonData
+	if err := json.Unmarshal(data, &jd); err != nil {
+		return err
+	} else if jd.SchemaVersion != JSONSchemaVersion {
+		return fmt.Errorf("unsupported metadata schema version %d (expected %d)", jd.SchemaVersion, JSONSchemaVersion)
+	}
+	return protojson.Unmarshal(jd.Meta, d)
+}
@@ -17,6 +17,7 @@ export interface Data {
   /** the auth handler or nil */
   auth_handler?: AuthHandler | undefined;
   cron_jobs: CronJob[];
+  pubsub_topics: PubSubTopic[];
 }
 
 /**
@@ -55,6 +56,12 @@ export interface Service {
   migrations: DBMigration[];
   /** databases this service connects to */
   databases: string[];
+  /** name of the service's config struct type, if any */
+  config_type: string;
+  /** names of the secrets used by the service */
+  secrets: string[];
+  /** CORS configuration, or nil */
+  cors?: CORS | undefined;
 }
 
 export interface DBMigration {
@@ -83,6 +90,20 @@ export interface RPC {
   loc: Loc;
   path: Path;
   http_methods: string[];
+  /** request/response transformation steps, in order */
+  transforms: string[];
+  /** true if the RPC streams its responses */
+  streaming: boolean;
+  /** streamed message schema, or nil */
+  stream_message_schema?: Type | undefined;
+  /** max request body size in bytes for raw RPCs; 0 means the default */
+  max_body_size: number;
+  /** documentation tags the RPC is grouped under */
+  tags: string[];
+  /** build constraints of the file the RPC is declared in */
+  build_tags: string[];
+  /** rate limit enforced by the gateway, or nil */
+  rate_limit?: RateLimit | undefined;
 }
 
 export enum RPC_AccessType {
@@ -200,3 +221,43 @@ export interface CronJob {
   schedule: string;
   endpoint: QualifiedName;
 }
+
+export interface PubSubTopic {
+  /** the topic name */
+  name: string;
+  /** the type of the messages published to the topic */
+  message_type: Type;
+  delivery_guarantee: PubSubTopic_DeliveryGuarantee;
+  subscriptions: PubSubSubscription[];
+}
+
+export enum PubSubTopic_DeliveryGuarantee {
+  AT_LEAST_ONCE = "AT_LEAST_ONCE",
+  EXACTLY_ONCE = "EXACTLY_ONCE",
+  UNRECOGNIZED = "UNRECOGNIZED",
+}
+
+export interface PubSubSubscription {
+  /** the subscription name */
+  name: string;
+  /** the service the subscription is declared in */
+  service_name: string;
+  /** the function handling the messages */
+  handler: QualifiedName;
+}
+
+export interface CORS {
+  /** allowed origins, or "*" for any origin */
+  allow_origins: string[];
+  /** allowed methods, or empty for the default set */
+  allow_methods: string[];
+  /** allowed request headers, or empty for the default set */
+  allow_headers: string[];
+}
+
+export interface RateLimit {
+  /** max number of requests per window */
+  requests: number;
+  /** length of the window in seconds */
+  window_seconds: number;
+}
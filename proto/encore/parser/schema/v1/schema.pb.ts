@@ -101,6 +101,8 @@ export interface Decl {
   doc: string;
   /** The location of the declaration within the project */
   loc: Loc;
+  /** The values of the exported constants of this type, if it is a builtin type */
+  enum_values: string[];
 }
 
 /**
@@ -165,6 +167,10 @@ export interface Field {
   raw_tag: string;
   /** Parsed go struct tags. Used for marshalling hints */
   tags: Tag[];
+  /** Whether the field is declared as a pointer (like *T). */
+  pointer: boolean;
+  /** Whether the field holds sensitive data that should be redacted when logged or traced. */
+  sensitive: boolean;
 }
 
 export interface Tag {
@@ -0,0 +1,18 @@
+// Package api provides types for declaring Encore APIs.
+package api
+
+// Stream is the response stream of a streaming API.
+//
+// Streaming APIs are declared with the stream option
+// (//encore:api public stream) and take the stream as their last parameter:
+//
+//	//encore:api public stream
+//	func Watch(ctx context.Context, p *Params, stream api.Stream[Event]) error
+//
+// Each message sent on the stream is delivered to the client as it is sent.
+// The stream is closed when the API returns.
+type Stream[T any] interface {
+	// Send sends a message to the client.
+	// It reports an error if the client has disconnected.
+	Send(msg *T) error
+}
@@ -2,10 +2,14 @@ package errs
 
 import (
 	"fmt"
+	"time"
 
 	"encore.dev/internal/stack"
 )
 
+// A Builder allows for gradual construction of an *Error.
+// The zero value is ready for use.
+// Use B() to create a new builder and Err() to construct the error.
 type Builder struct {
 	code    ErrCode
 	codeSet bool
@@ -15,37 +19,75 @@ type Builder struct {
 	msg  string
 	meta []interface{}
 	err  error
+
+	retryAfter    time.Duration
+	retryAfterSet bool
+
+	traceID string
 }
 
+// B is a shorthand for creating a new Builder.
 func B() *Builder { return &Builder{} }
 
+// Code sets the error code.
 func (b *Builder) Code(c ErrCode) *Builder {
 	b.code = c
 	b.codeSet = true
 	return b
 }
 
+// Msg sets the error message.
 func (b *Builder) Msg(msg string) *Builder {
 	b.msg = msg
 	return b
 }
 
+// Msgf is like Msg but uses fmt.Sprintf to construct the message.
 func (b *Builder) Msgf(format string, args ...interface{}) *Builder {
 	b.msg = fmt.Sprintf(format, args...)
 	return b
 }
 
+// Meta appends metadata key-value pairs.
+// Calling Meta multiple times merges the metadata,
+// with later values taking precedence for duplicate keys.
 func (b *Builder) Meta(metaPairs ...interface{}) *Builder {
 	b.meta = append(b.meta, metaPairs...)
 	return b
 }
 
+// MetaMap is like Meta but takes the metadata as a map.
+func (b *Builder) MetaMap(md Metadata) *Builder {
+	for k, v := range md {
+		b.meta = append(b.meta, k, v)
+	}
+	return b
+}
+
+// Details sets the details.
 func (b *Builder) Details(det ErrDetails) *Builder {
 	b.det = det
 	b.detSet = true
 	return b
 }
 
+// RetryAfter sets a hint for how long the client should wait
+// before retrying. Zero means no hint is given.
+func (b *Builder) RetryAfter(d time.Duration) *Builder {
+	b.retryAfter = d
+	b.retryAfterSet = true
+	return b
+}
+
+// TraceID sets the ID of the trace the error occurred in.
+func (b *Builder) TraceID(id string) *Builder {
+	b.traceID = id
+	return b
+}
+
+// Cause sets the underlying error cause.
+// If err is an *Error, its code, details, retry-after hint and trace ID
+// are used unless they have been set explicitly on the builder.
 func (b *Builder) Cause(err error) *Builder {
 	b.err = err
 	if e, ok := err.(*Error); ok {
@@ -55,10 +97,24 @@ func (b *Builder) Cause(err error) *Builder {
 		if !b.detSet {
 			b.det = e.Details
 		}
+		if !b.retryAfterSet {
+			b.retryAfter = e.RetryAfter
+		}
+		if b.traceID == "" {
+			b.traceID = e.TraceID
+		}
 	}
 	return b
 }
 
+// Err returns the constructed error.
+// It never returns nil.
+//
+// If Code has not been set or has been set to OK,
+// the code is set to Unknown.
+//
+// The stack trace of the error begins at the caller of Err,
+// unless the cause is an *Error in which case its stack is kept.
 func (b *Builder) Err() error {
 	code := b.code
 	if code == OK {
@@ -72,9 +128,11 @@ func (b *Builder) Err() error {
 
 	var errMeta Metadata
 	var s stack.Stack
+	var frames []stack.Frame
 	if e, ok := b.err.(*Error); ok {
 		errMeta = e.Meta
 		s = e.stack
+		frames = e.frames
 	} else {
 		s = stack.Build(2)
 	}
@@ -84,7 +142,10 @@ func (b *Builder) Err() error {
 		Message:    msg,
 		Meta:       mergeMeta(errMeta, b.meta),
 		Details:    b.det,
+		RetryAfter: b.retryAfter,
+		TraceID:    b.traceID,
 		underlying: b.err,
 		stack:      s,
+		frames:     frames,
 	}
 }
@@ -0,0 +1,70 @@
+package errs
+
+import (
+	"errors"
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestBuilderMeta(t *testing.T) {
+	err := B().
+		Code(NotFound).
+		Msg("user not found").
+		Meta("user", 1, "attempt", 1).
+		MetaMap(Metadata{"region": "eu"}).
+		Meta("attempt", 2).
+		Err()
+
+	e := err.(*Error)
+	if e.Code != NotFound {
+		t.Errorf("got code %s, want %s", e.Code, NotFound)
+	}
+	want := Metadata{"user": 1, "attempt": 2, "region": "eu"}
+	if !reflect.DeepEqual(e.Meta, want) {
+		t.Errorf("got meta %v, want %v", e.Meta, want)
+	}
+}
+
+func TestBuilderCause(t *testing.T) {
+	cause := B().Code(PermissionDenied).Msg("denied").Meta("a", 1).Err()
+	err := B().Cause(cause).Msg("cannot update").Meta("b", 2).Err()
+
+	e := err.(*Error)
+	if e.Code != PermissionDenied {
+		t.Errorf("got code %s, want %s", e.Code, PermissionDenied)
+	}
+	if want := (Metadata{"a": 1, "b": 2}); !reflect.DeepEqual(e.Meta, want) {
+		t.Errorf("got meta %v, want %v", e.Meta, want)
+	}
+	if !errors.Is(err, cause) {
+		t.Errorf("errors.Is(err, cause) = false, want true")
+	}
+
+	// Merging metadata must not modify the cause.
+	if want := (Metadata{"a": 1}); !reflect.DeepEqual(cause.(*Error).Meta, want) {
+		t.Errorf("cause meta modified: got %v, want %v", cause.(*Error).Meta, want)
+	}
+
+	// An explicitly set code takes precedence over the cause's code.
+	err = B().Code(Internal).Cause(cause).Err()
+	if got := Code(err); got != Internal {
+		t.Errorf("got code %s, want %s", got, Internal)
+	}
+}
+
+func TestBuilderStack(t *testing.T) {
+	err := B().Code(Internal).Msg("boom").Err()
+
+	frames := runtime.CallersFrames(Stack(err).Frames)
+	f, _ := frames.Next()
+	if want := "encore.dev/beta/errs.TestBuilderStack"; f.Function != want {
+		t.Errorf("got top frame %q, want %q", f.Function, want)
+	}
+
+	// The stack of an *Error cause is kept.
+	wrapped := B().Cause(err).Msg("wrapped").Err()
+	if !reflect.DeepEqual(Stack(wrapped), Stack(err)) {
+		t.Errorf("got different stack for wrapped error")
+	}
+}
@@ -1,5 +1,7 @@
 package errs
 
+import "fmt"
+
 // ErrCode is an RPC error code.
 type ErrCode int
 
@@ -169,9 +171,19 @@ func (c ErrCode) String() string {
 	return codeNames[c]
 }
 
+// Error implements the error interface, which allows codes to be used
+// as sentinel errors: errors.Is(err, errs.NotFound) reports whether
+// err is, or wraps, an *Error with the NotFound code.
+func (c ErrCode) Error() string {
+	return c.String()
+}
+
 // HTTPStatus reports a suitable HTTP status code for an error, based on its code.
 // If err is nil it reports 200. If it's not an *Error it reports 500.
 func (c ErrCode) HTTPStatus() int {
+	if c < 0 || int(c) >= len(codeStatus) {
+		return 500
+	}
 	return codeStatus[c]
 }
 
@@ -180,6 +192,22 @@ func (c ErrCode) MarshalJSON() ([]byte, error) {
 	return []byte("\"" + s + "\""), nil
 }
 
+// UnmarshalJSON unmarshals a code from its string representation,
+// as written by MarshalJSON.
+func (c *ErrCode) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	for code, name := range codeNames {
+		if name == s {
+			*c = ErrCode(code)
+			return nil
+		}
+	}
+	return fmt.Errorf("errs: unknown error code %q", s)
+}
+
 var codeNames = [...]string{
 	OK:                 "ok",
 	Canceled:           "canceled",
@@ -0,0 +1,93 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"encore.dev/internal/stack"
+)
+
+// codeSeverity orders the error codes by severity for Combine.
+// Client errors are less severe than transient server errors,
+// which are less severe than internal errors.
+var codeSeverity = [...]int{
+	OK:                 0,
+	Canceled:           1,
+	NotFound:           2,
+	AlreadyExists:      3,
+	InvalidArgument:    4,
+	OutOfRange:         5,
+	FailedPrecondition: 6,
+	PermissionDenied:   7,
+	Unauthenticated:    8,
+	Aborted:            9,
+	ResourceExhausted:  10,
+	DeadlineExceeded:   11,
+	Unavailable:        12,
+	Unimplemented:      13,
+	Unknown:            14,
+	Internal:           15,
+	DataLoss:           16,
+}
+
+// severity reports the severity of c for Combine.
+// Unknown codes are as severe as Unknown.
+func severity(c ErrCode) int {
+	if int(c) < len(codeSeverity) {
+		return codeSeverity[c]
+	}
+	return codeSeverity[Unknown]
+}
+
+// Combine combines several errors into one, such as the errors
+// of concurrent calls. Nil errors are ignored.
+//
+// If all errors are nil it returns nil, and if exactly one is non-nil
+// it returns that error unchanged. Otherwise it returns an *Error whose
+// code is the most severe of the errors' codes, as reported by Code,
+// and whose message lists the errors. If several errors have that code
+// the first one is chosen, and the combined error keeps its details
+// and stack trace.
+//
+// From least to most severe the codes are: OK, Canceled, NotFound,
+// AlreadyExists, InvalidArgument, OutOfRange, FailedPrecondition,
+// PermissionDenied, Unauthenticated, Aborted, ResourceExhausted,
+// DeadlineExceeded, Unavailable, Unimplemented, Unknown, Internal and DataLoss.
+func Combine(errs ...error) error {
+	var nonNil []error
+	for _, err := range errs {
+		if err != nil {
+			nonNil = append(nonNil, err)
+		}
+	}
+	switch len(nonNil) {
+	case 0:
+		return nil
+	case 1:
+		return nonNil[0]
+	}
+
+	chosen := nonNil[0]
+	msgs := make([]string, len(nonNil))
+	for i, err := range nonNil {
+		msgs[i] = err.Error()
+		if severity(Code(err)) > severity(Code(chosen)) {
+			chosen = err
+		}
+	}
+
+	e := &Error{
+		Code:    Code(chosen),
+		Message: fmt.Sprintf("%d errors occurred: %s", len(nonNil), strings.Join(msgs, "; ")),
+	}
+	var ee *Error
+	if errors.As(chosen, &ee) {
+		e.Details = ee.Details
+		e.stack = ee.stack
+		e.frames = ee.frames
+	} else {
+		e.stack = stack.Build(2)
+	}
+	return e
+}
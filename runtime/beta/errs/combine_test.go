@@ -0,0 +1,115 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestCombine(t *testing.T) {
+	notFound := B().Code(NotFound).Msg("no such user").Details(userDetails{UserID: "u1"}).Err()
+	internal := B().Code(Internal).Msg("db down").Details(quotaDetails{Limit: 1}).Err()
+	internal2 := B().Code(Internal).Msg("cache down").Err()
+	unavailable := B().Code(Unavailable).Msg("try later").Err()
+	invalid := B().Code(InvalidArgument).Msg("bad name").Err()
+	plain := errors.New("plain")
+	wrapped := fmt.Errorf("call failed: %w", unavailable)
+
+	tests := []struct {
+		name        string
+		errs        []error
+		wantCode    ErrCode
+		wantMsg     string
+		wantDetails ErrDetails
+	}{
+		{
+			name:        "client and server errors",
+			errs:        []error{notFound, internal, invalid},
+			wantCode:    Internal,
+			wantMsg:     "3 errors occurred: not_found: no such user; internal: db down; invalid_argument: bad name",
+			wantDetails: quotaDetails{Limit: 1},
+		},
+		{
+			name:        "client errors",
+			errs:        []error{notFound, invalid, nil},
+			wantCode:    InvalidArgument,
+			wantMsg:     "2 errors occurred: not_found: no such user; invalid_argument: bad name",
+			wantDetails: nil,
+		},
+		{
+			name:        "ties keep the first error",
+			errs:        []error{internal2, internal},
+			wantCode:    Internal,
+			wantMsg:     "2 errors occurred: internal: cache down; internal: db down",
+			wantDetails: nil,
+		},
+		{
+			name:        "plain errors are unknown",
+			errs:        []error{unavailable, plain},
+			wantCode:    Unknown,
+			wantMsg:     "2 errors occurred: unavailable: try later; plain",
+			wantDetails: nil,
+		},
+		{
+			name:        "wrapped errors use their chain's code",
+			errs:        []error{notFound, wrapped},
+			wantCode:    Unavailable,
+			wantMsg:     "2 errors occurred: not_found: no such user; call failed: unavailable: try later",
+			wantDetails: nil,
+		},
+		{
+			name:        "context errors",
+			errs:        []error{context.Canceled, notFound},
+			wantCode:    NotFound,
+			wantMsg:     "2 errors occurred: context canceled; not_found: no such user",
+			wantDetails: userDetails{UserID: "u1"},
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			err, ok := Combine(test.errs...).(*Error)
+			if !ok {
+				t.Fatalf("got %T, want *Error", Combine(test.errs...))
+			}
+			if err.Code != test.wantCode {
+				t.Errorf("got code %v, want %v", err.Code, test.wantCode)
+			}
+			if err.Message != test.wantMsg {
+				t.Errorf("got message %q, want %q", err.Message, test.wantMsg)
+			}
+			if !reflect.DeepEqual(err.Details, test.wantDetails) {
+				t.Errorf("got details %#v, want %#v", err.Details, test.wantDetails)
+			}
+		})
+	}
+}
+
+func TestCombineTrivial(t *testing.T) {
+	if err := Combine(); err != nil {
+		t.Errorf("Combine() = %v, want nil", err)
+	}
+	if err := Combine(nil, nil); err != nil {
+		t.Errorf("Combine(nil, nil) = %v, want nil", err)
+	}
+	single := B().Code(NotFound).Msg("no such user").Err()
+	if err := Combine(nil, single, nil); err != single {
+		t.Errorf("Combine(nil, err, nil) = %v, want err unchanged", err)
+	}
+}
+
+func TestCodeSeverity(t *testing.T) {
+	// Every code has a distinct severity.
+	seen := make(map[int]ErrCode)
+	for c := OK; c <= Unauthenticated; c++ {
+		s := severity(c)
+		if other, ok := seen[s]; ok {
+			t.Errorf("codes %v and %v have the same severity %d", c, other, s)
+		}
+		seen[s] = c
+	}
+	if got, want := severity(ErrCode(100)), severity(Unknown); got != want {
+		t.Errorf("got severity %d for an unknown code, want %d", got, want)
+	}
+}
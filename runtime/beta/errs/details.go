@@ -1,5 +1,72 @@
 package errs
 
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"reflect"
+
+	jsoniter "github.com/json-iterator/go"
+)
+
 type ErrDetails interface {
 	ErrDetails() // marker method
 }
+
+// RawDetails holds error details of an unknown type in their JSON form.
+// It is used when decoding errors with UnmarshalJSON,
+// and marshals back to the same JSON.
+type RawDetails jsoniter.RawMessage
+
+func (RawDetails) ErrDetails() {}
+
+// MarshalJSON returns the JSON form of the details.
+func (d RawDetails) MarshalJSON() ([]byte, error) {
+	if len(d) == 0 {
+		return []byte("null"), nil
+	}
+	return d, nil
+}
+
+// ValidateDetails reports whether det is preserved when replicated
+// across RPC boundaries with RoundTrip, which copies details using encoding/gob.
+// It returns an error naming the offending field if det has fields that gob
+// ignores, such as unexported fields, channels and functions, or if det
+// cannot be encoded or is not equal to itself after an encode/decode round trip.
+//
+// It is intended for use in tests, to assert that an app's details types are valid.
+func ValidateDetails(det ErrDetails) error {
+	if det == nil {
+		return nil
+	}
+
+	t := reflect.TypeOf(det)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Struct {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			switch {
+			case f.PkgPath != "":
+				return fmt.Errorf("errs: details type %T: field %s is unexported and is lost by RoundTrip", det, f.Name)
+			case f.Type.Kind() == reflect.Chan, f.Type.Kind() == reflect.Func:
+				return fmt.Errorf("errs: details type %T: field %s of type %s is lost by RoundTrip", det, f.Name, f.Type)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	registerDetails(det)
+	if err := gob.NewEncoder(&buf).Encode(struct{ Details ErrDetails }{Details: det}); err != nil {
+		return fmt.Errorf("errs: details type %T cannot be encoded: %v", det, err)
+	}
+	var dst struct{ Details ErrDetails }
+	if err := gob.NewDecoder(&buf).Decode(&dst); err != nil {
+		return fmt.Errorf("errs: details type %T cannot be decoded: %v", det, err)
+	}
+	if !reflect.DeepEqual(dst.Details, det) {
+		return fmt.Errorf("errs: details type %T is not preserved by RoundTrip: got %#v, want %#v", det, dst.Details, det)
+	}
+	return nil
+}
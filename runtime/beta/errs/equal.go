@@ -0,0 +1,100 @@
+package errs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Equal reports whether a and b are equal errors, for use in tests.
+//
+// Two *Error values are equal if they have the same code, message,
+// trace id, and deeply equal details and metadata. Stack traces,
+// retry-after hints and wrapped errors are not compared.
+// Nil and empty metadata are considered equal.
+//
+// Errors that are not both *Error values are equal if they are
+// both nil or deeply equal. A nil *Error is equal to a nil error.
+func Equal(a, b error) bool {
+	return errorDiff(a, b) == ""
+}
+
+// errorDiff describes the first difference between a and b,
+// or returns "" if they are equal as defined by Equal.
+func errorDiff(a, b error) string {
+	ea, okA := a.(*Error)
+	eb, okB := b.(*Error)
+	switch {
+	case isNil(a) || isNil(b):
+		if isNil(a) != isNil(b) {
+			return "one error is nil"
+		}
+		return ""
+	case !okA || !okB:
+		if !reflect.DeepEqual(a, b) {
+			return "errors are not both *errs.Error values and are not deeply equal"
+		}
+		return ""
+	}
+
+	switch {
+	case ea.Code != eb.Code:
+		return fmt.Sprintf("codes differ: %s != %s", ea.Code, eb.Code)
+	case ea.Message != eb.Message:
+		return fmt.Sprintf("messages differ: %q != %q", ea.Message, eb.Message)
+	case ea.TraceID != eb.TraceID:
+		return fmt.Sprintf("trace ids differ: %q != %q", ea.TraceID, eb.TraceID)
+	case !reflect.DeepEqual(ea.Details, eb.Details):
+		return fmt.Sprintf("details differ: %#v != %#v", ea.Details, eb.Details)
+	case (len(ea.Meta) > 0 || len(eb.Meta) > 0) && !reflect.DeepEqual(ea.Meta, eb.Meta):
+		return fmt.Sprintf("metadata differs: %v != %v", ea.Meta, eb.Meta)
+	}
+	return ""
+}
+
+// isNil reports whether err is nil or a nil *Error.
+func isNil(err error) bool {
+	e, ok := err.(*Error)
+	return err == nil || (ok && e == nil)
+}
+
+// Equals is a checker for github.com/frankban/quicktest that checks
+// that the error is equal to the wanted error, as defined by Equal:
+//
+//	c.Assert(err, errs.Equals, errs.B().Code(errs.NotFound).Msg("not found").Err())
+//
+// It implements quicktest.Checker without depending on quicktest.
+var Equals = equalsChecker{}
+
+type equalsChecker struct{}
+
+// ArgNames implements quicktest.Checker.
+func (equalsChecker) ArgNames() []string {
+	return []string{"got", "want"}
+}
+
+// Check implements quicktest.Checker.
+func (equalsChecker) Check(got interface{}, args []interface{}, note func(key string, value interface{})) error {
+	gotErr, ok := asError(got)
+	if !ok {
+		return fmt.Errorf("got value is not an error: %T", got)
+	}
+	wantErr, ok := asError(args[0])
+	if !ok {
+		return fmt.Errorf("want value is not an error: %T", args[0])
+	}
+	if diff := errorDiff(gotErr, wantErr); diff != "" {
+		note("difference", diff)
+		return errors.New("errors are not equal")
+	}
+	return nil
+}
+
+// asError reports v as an error. A nil v is a nil error.
+func asError(v interface{}) (error, bool) {
+	if v == nil {
+		return nil, true
+	}
+	err, ok := v.(error)
+	return err, ok
+}
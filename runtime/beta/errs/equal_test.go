@@ -0,0 +1,99 @@
+package errs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEqual(t *testing.T) {
+	base := func() *Builder {
+		return B().Code(NotFound).Msg("no such user").TraceID("trace-1").
+			Details(&retryDetails{Attempts: []int{1, 2}}).Meta("user", "u1")
+	}
+	sentinel := errors.New("sentinel")
+
+	tests := []struct {
+		name string
+		a, b error
+		want bool
+	}{
+		{"nil", nil, nil, true},
+		{"nil and error", nil, base().Err(), false},
+		{"nil *Error", (*Error)(nil), (*Error)(nil), true},
+		{"nil and nil *Error", nil, (*Error)(nil), true},
+		{"same fields", base().Err(), base().Err(), true},
+		{"different code", base().Err(), base().Code(Internal).Err(), false},
+		{"different message", base().Err(), base().Msg("gone").Err(), false},
+		{"different trace id", base().Err(), base().TraceID("trace-2").Err(), false},
+		{"different details", base().Err(), base().Details(&retryDetails{Attempts: []int{1}}).Err(), false},
+		{"different details type", base().Err(), base().Details(userDetails{UserID: "u1"}).Err(), false},
+		{"different meta value", base().Err(), base().Meta("user", "u2").Err(), false},
+		{"extra meta", base().Err(), base().Meta("org", "o1").Err(), false},
+		{"nil and empty meta", &Error{Code: Internal}, &Error{Code: Internal, Meta: Metadata{}}, true},
+		{"ignores retry-after", base().Err(), base().RetryAfter(time.Second).Err(), true},
+		{"ignores cause", base().Err(), base().Cause(sentinel).Err(), true},
+		{"same stdlib error", sentinel, sentinel, true},
+		{"different stdlib errors", errors.New("a"), errors.New("b"), false},
+		{"stdlib and *Error", sentinel, base().Err(), false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Equal(test.a, test.b); got != test.want {
+				t.Errorf("Equal(a, b) = %v, want %v", got, test.want)
+			}
+			if got := Equal(test.b, test.a); got != test.want {
+				t.Errorf("Equal(b, a) = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestEqualIgnoresStack(t *testing.T) {
+	a := B().Code(Internal).Msg("boom").Err()
+	b := B().Code(Internal).Msg("boom").Err() // built on a different line
+	if !Equal(a, b) {
+		t.Errorf("got errors with different stacks unequal, want equal")
+	}
+}
+
+func TestEqualsChecker(t *testing.T) {
+	if got, want := Equals.ArgNames(), []string{"got", "want"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got ArgNames %v, want %v", got, want)
+	}
+
+	want := B().Code(NotFound).Msg("no such user").Err()
+	tests := []struct {
+		name    string
+		got     interface{}
+		wantErr string
+		note    string
+	}{
+		{"equal", B().Code(NotFound).Msg("no such user").Err(), "", ""},
+		{"not equal", B().Code(NotFound).Msg("gone").Err(), "errors are not equal", `messages differ: "gone" != "no such user"`},
+		{"nil", nil, "errors are not equal", "one error is nil"},
+		{"not an error", 42, "got value is not an error: int", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var notes []string
+			note := func(key string, value interface{}) {
+				notes = append(notes, key+": "+value.(string))
+			}
+			err := Equals.Check(test.got, []interface{}{want}, note)
+			if test.wantErr == "" {
+				if err != nil {
+					t.Fatalf("got error %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != test.wantErr {
+				t.Fatalf("got error %v, want %q", err, test.wantErr)
+			}
+			if test.note != "" && (len(notes) != 1 || notes[0] != "difference: "+test.note) {
+				t.Errorf("got notes %q, want %q", notes, "difference: "+test.note)
+			}
+		})
+	}
+}
@@ -1,9 +1,17 @@
 package errs
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"reflect"
+	"runtime"
+	"strconv"
 	"strings"
+	"sync"
+	"time"
 	"unsafe"
 
 	jsoniter "github.com/json-iterator/go"
@@ -21,7 +29,17 @@ type Error struct {
 	Code    ErrCode    `json:"code"`
 	Message string     `json:"message"`
 	Details ErrDetails `json:"details"`
-	Meta    Metadata   `json:"-"` // not exposed to external clients
+	Meta    Metadata   `json:"-"` // not exposed to external clients; see MetaCopy
+
+	// RetryAfter is a hint for how long the client should wait
+	// before retrying, typically used with ResourceExhausted and
+	// Unavailable errors. Zero means no hint is given.
+	RetryAfter time.Duration `json:"-"`
+
+	// TraceID is the ID of the trace the error occurred in, if known.
+	// It is preserved when the error is wrapped, unless the wrapping
+	// error sets its own, and when replicated with RoundTrip.
+	TraceID string `json:"-"`
 
 	// underlying is the underlying error,
 	// for use with errors.Is and errors.As.
@@ -29,10 +47,92 @@ type Error struct {
 	underlying error
 
 	stack stack.Stack
+
+	// frames is the resolved stack of the error this error was
+	// copied from with RoundTripWithStack, if any. It is used by
+	// CleanStack instead of stack, which is where the copy was made.
+	frames []stack.Frame
 }
 
+// Metadata is additional information attached to an error.
+//
+// Metadata may be shared between errors, for example when an error is
+// wrapped, so the Meta field of an *Error should be treated as read-only.
+// Use MetaCopy to get a copy that is safe to modify.
 type Metadata map[string]interface{}
 
+// redacted is the value sensitive metadata values are replaced with.
+const redacted = "[redacted]"
+
+var (
+	sensitiveMu   sync.RWMutex
+	sensitiveKeys = make(map[string]bool)
+)
+
+// SensitiveMeta marks the given metadata keys as sensitive.
+// The values of sensitive keys are redacted when errors are logged
+// or replicated across RPC boundaries with RoundTrip,
+// but remain accessible in-process through the Meta field.
+//
+// It is typically called during initialization.
+func SensitiveMeta(keys ...string) {
+	sensitiveMu.Lock()
+	defer sensitiveMu.Unlock()
+	for _, k := range keys {
+		sensitiveKeys[k] = true
+	}
+}
+
+// Redacted returns a copy of md where the values of
+// keys marked with SensitiveMeta are replaced with "[redacted]".
+func (md Metadata) Redacted() Metadata {
+	if md == nil {
+		return nil
+	}
+	sensitiveMu.RLock()
+	defer sensitiveMu.RUnlock()
+	cp := make(Metadata, len(md))
+	for k, v := range md {
+		if sensitiveKeys[k] {
+			v = redacted
+		}
+		cp[k] = v
+	}
+	return cp
+}
+
+// New returns an error with the given code and message.
+// Its stack starts at the caller of New.
+//
+//go:noinline
+func New(code ErrCode, msg string) error {
+	return &Error{Code: code, Message: msg, stack: callerStack(New)}
+}
+
+// Newf is like New but formats the message according to a format specifier.
+//
+//go:noinline
+func Newf(code ErrCode, format string, args ...interface{}) error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...), stack: callerStack(Newf)}
+}
+
+// callerStack returns the stack of the caller of fn, which must be a
+// function in this package on the current call stack. Unlike passing a
+// fixed skip count to stack.Build, it does not depend on how many calls
+// within this package lie between fn and callerStack.
+func callerStack(fn interface{}) stack.Stack {
+	s := stack.Build(1)
+	entry := reflect.ValueOf(fn).Pointer()
+	for i, pc := range s.Frames {
+		// pc is a return address, so pc-1 is within the calling function.
+		if f := runtime.FuncForPC(pc - 1); f != nil && f.Entry() == entry {
+			s.Frames = s.Frames[i+1:]
+			return s
+		}
+	}
+	return s
+}
+
 func Wrap(err error, msg string, metaPairs ...interface{}) error {
 	if err == nil {
 		return nil
@@ -43,7 +143,10 @@ func Wrap(err error, msg string, metaPairs ...interface{}) error {
 		e.Details = ee.Details
 		e.Code = ee.Code
 		e.Meta = mergeMeta(ee.Meta, metaPairs)
+		e.RetryAfter = ee.RetryAfter
+		e.TraceID = ee.TraceID
 		e.stack = ee.stack
+		e.frames = ee.frames
 	} else {
 		e.Meta = mergeMeta(nil, metaPairs)
 		e.stack = stack.Build(2)
@@ -61,7 +164,10 @@ func WrapCode(err error, code ErrCode, msg string, metaPairs ...interface{}) err
 		e.Details = ee.Details
 		e.Code = ee.Code
 		e.Meta = mergeMeta(ee.Meta, metaPairs)
+		e.RetryAfter = ee.RetryAfter
+		e.TraceID = ee.TraceID
 		e.stack = ee.stack
+		e.frames = ee.frames
 	} else {
 		e.Meta = mergeMeta(nil, metaPairs)
 		e.stack = stack.Build(2)
@@ -69,6 +175,9 @@ func WrapCode(err error, code ErrCode, msg string, metaPairs ...interface{}) err
 	return e
 }
 
+// Convert converts an error to an *Error.
+// If err is already an *Error it is returned unchanged.
+// Otherwise the returned error wraps err and has the code reported by Code.
 func Convert(err error) error {
 	if err == nil {
 		return nil
@@ -76,19 +185,65 @@ func Convert(err error) error {
 		return e
 	}
 	return &Error{
-		Code:       Unknown,
+		Code:       Code(err),
 		underlying: err,
 		stack:      stack.Build(2),
 	}
 }
 
+// Code reports the error code from an error.
+// If err is nil it reports OK. Otherwise it reports the code of
+// the first *Error in err's chain, as found by errors.As.
+// If there is none, errors wrapping context.Canceled or context.DeadlineExceeded
+// report Canceled and DeadlineExceeded, and all other errors report Unknown.
 func Code(err error) ErrCode {
 	if err == nil {
 		return OK
-	} else if e, ok := err.(*Error); ok {
+	}
+	var e *Error
+	if errors.As(err, &e) {
 		return e.Code
 	}
-	return Unknown
+	return stdlibCode(err)
+}
+
+// HasCode reports whether Code(err) is code.
+func HasCode(err error, code ErrCode) bool {
+	return Code(err) == code
+}
+
+// retryableCodes are the codes of errors that are typically transient,
+// for which retrying the operation may succeed.
+var retryableCodes = map[ErrCode]bool{
+	DeadlineExceeded:  true,
+	ResourceExhausted: true,
+	Aborted:           true,
+	Unavailable:       true,
+}
+
+// Retryable reports whether a client should retry the operation that failed with err.
+// If the first *Error in err's chain, as found by errors.As, has a RetryAfter hint
+// the operation is retryable regardless of its code. Otherwise it reports whether
+// Code(err) is a transient error code: DeadlineExceeded, ResourceExhausted,
+// Aborted or Unavailable. If err is nil it reports false.
+func Retryable(err error) bool {
+	var e *Error
+	if errors.As(err, &e) && e.RetryAfter > 0 {
+		return true
+	}
+	return retryableCodes[Code(err)]
+}
+
+// stdlibCode reports the error code for a non-*Error error.
+func stdlibCode(err error) ErrCode {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded
+	default:
+		return Unknown
+	}
 }
 
 func Details(err error) ErrDetails {
@@ -98,6 +253,8 @@ func Details(err error) ErrDetails {
 	return nil
 }
 
+// Meta returns the metadata of err, or nil if err is not an *Error.
+// The returned metadata must be treated as read-only.
 func Meta(err error) Metadata {
 	if e, ok := err.(*Error); ok {
 		return e.Meta
@@ -105,6 +262,73 @@ func Meta(err error) Metadata {
 	return nil
 }
 
+// MetaCopy returns a deep copy of the error's metadata,
+// which the caller is free to modify. Nested maps and slices
+// are copied as well; other values are copied as-is.
+// It returns nil if the error has no metadata.
+func (e *Error) MetaCopy() Metadata {
+	if e.Meta == nil {
+		return nil
+	}
+	return deepCopy(reflect.ValueOf(e.Meta)).Interface().(Metadata)
+}
+
+// deepCopy returns a copy of v where maps and slices,
+// including those nested within interface values, are copied recursively.
+func deepCopy(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.New(v.Type()).Elem()
+		cp.Set(deepCopy(v.Elem()))
+		return cp
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			cp.SetMapIndex(iter.Key(), deepCopy(iter.Value()))
+		}
+		return cp
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		cp := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			cp.Index(i).Set(deepCopy(v.Index(i)))
+		}
+		return cp
+	default:
+		return v
+	}
+}
+
+// LogFields returns the error as a flat set of fields for structured loggers:
+// "error.code", "error.message" (including the messages of wrapped errors),
+// "error.trace_id", and each metadata key prefixed with "error.meta.".
+// Empty values are omitted, and sensitive metadata values are redacted.
+func (e *Error) LogFields() map[string]interface{} {
+	fields := make(map[string]interface{}, 3+len(e.Meta))
+	fields["error.code"] = e.Code.String()
+	if msg := e.ErrorMessage(); msg != "" {
+		fields["error.message"] = msg
+	}
+	if e.TraceID != "" {
+		fields["error.trace_id"] = e.TraceID
+	}
+	for k, v := range e.Meta.Redacted() {
+		if v != nil {
+			fields["error.meta."+k] = v
+		}
+	}
+	return fields
+}
+
 func (e *Error) Error() string {
 	return e.Code.String() + ": " + e.ErrorMessage()
 }
@@ -139,6 +363,87 @@ func (e *Error) Unwrap() error {
 	return e.underlying
 }
 
+// jsonError is the JSON representation of an *Error.
+type jsonError struct {
+	Code              ErrCode    `json:"code"`
+	Message           string     `json:"message"`
+	Details           ErrDetails `json:"details"`
+	RetryAfterSeconds int64      `json:"retry_after_seconds,omitempty"`
+	TraceID           string     `json:"trace_id,omitempty"`
+}
+
+// MarshalJSON marshals the error for external clients.
+// The retry-after hint is included as "retry_after_seconds",
+// rounded up to whole seconds, when it is non-zero,
+// and the trace ID as "trace_id" when it is non-empty.
+func (e *Error) MarshalJSON() ([]byte, error) {
+	return json.Marshal(jsonError{
+		Code:              e.Code,
+		Message:           e.Message,
+		Details:           e.Details,
+		RetryAfterSeconds: retryAfterSeconds(e.RetryAfter),
+		TraceID:           e.TraceID,
+	})
+}
+
+// UnmarshalJSON unmarshals an error in the form written by MarshalJSON.
+// Since the concrete type of the details is not known,
+// any details are unmarshalled as RawDetails.
+func (e *Error) UnmarshalJSON(data []byte) error {
+	var dst struct {
+		Code              *ErrCode            `json:"code"`
+		Message           string              `json:"message"`
+		Details           jsoniter.RawMessage `json:"details"`
+		RetryAfterSeconds int64               `json:"retry_after_seconds"`
+		TraceID           string              `json:"trace_id"`
+	}
+	if err := json.Unmarshal(data, &dst); err != nil {
+		return err
+	} else if dst.Code == nil {
+		return errors.New("errs: missing error code")
+	}
+
+	*e = Error{
+		Code:       *dst.Code,
+		Message:    dst.Message,
+		RetryAfter: time.Duration(dst.RetryAfterSeconds) * time.Second,
+		TraceID:    dst.TraceID,
+	}
+	if len(dst.Details) > 0 && string(dst.Details) != "null" {
+		e.Details = RawDetails(dst.Details)
+	}
+	return nil
+}
+
+// retryAfterSeconds converts d to whole seconds, rounding up.
+func retryAfterSeconds(d time.Duration) int64 {
+	if d <= 0 {
+		return 0
+	}
+	return int64((d + time.Second - 1) / time.Second)
+}
+
+// ErrServer is a sentinel error for use with errors.Is.
+// It matches any *Error whose code belongs to the server error family,
+// meaning codes that map to 5xx HTTP status codes.
+var ErrServer = errors.New("server error")
+
+// Is reports whether e matches target. It allows errors.Is to match
+// e against an error code, as in errors.Is(err, errs.NotFound),
+// and against sentinel errors describing an entire family of codes,
+// such as ErrServer.
+func (e *Error) Is(target error) bool {
+	if code, ok := target.(ErrCode); ok {
+		return e.Code == code
+	}
+	switch target {
+	case ErrServer:
+		return e.Code.HTTPStatus() >= 500
+	default:
+		return false
+	}
+}
+
 func HTTPError(w http.ResponseWriter, err error) {
 	w.Header().Set("Content-Type", "application/json")
 	if err == nil {
@@ -156,13 +461,56 @@ func HTTPError(w http.ResponseWriter, err error) {
 	data, err2 := json.MarshalIndent(e, "", "  ")
 	if err2 != nil {
 		// Must be the details; drop them
-		e2 := &Error{Code: e.Code, Message: e.Message}
+		e2 := &Error{Code: e.Code, Message: e.Message, RetryAfter: e.RetryAfter, TraceID: e.TraceID}
 		data, _ = json.MarshalIndent(e2, "", "  ")
 	}
+	if secs := retryAfterSeconds(e.RetryAfter); secs > 0 {
+		w.Header().Set("Retry-After", strconv.FormatInt(secs, 10))
+	}
 	w.WriteHeader(e.Code.HTTPStatus())
 	w.Write(data)
 }
 
+// maxErrorBodySize is the maximum number of bytes
+// FromHTTPResponse reads from a response body.
+const maxErrorBodySize = 64 << 10
+
+// FromHTTPResponse converts a non-2xx HTTP response to an *Error.
+// It returns nil if the response has a 2xx status code.
+//
+// If the response body is an Encore error, as written by HTTPError,
+// its code, message, details and retry-after hint are used.
+// Otherwise the code is derived from the status code with HTTPStatusToCode,
+// and the (possibly truncated) body is used as the message.
+//
+// FromHTTPResponse reads from but does not close the response body.
+func FromHTTPResponse(resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err != nil {
+		return &Error{
+			Code:       HTTPStatusToCode(resp.StatusCode),
+			Message:    fmt.Sprintf("http status %d: could not read response body", resp.StatusCode),
+			underlying: err,
+			stack:      stack.Build(2),
+		}
+	}
+
+	e := &Error{}
+	if err := json.Unmarshal(body, e); err != nil || e.Code == OK {
+		msg := strings.TrimSpace(string(body))
+		if msg == "" {
+			msg = http.StatusText(resp.StatusCode)
+		}
+		e = &Error{Code: HTTPStatusToCode(resp.StatusCode), Message: msg}
+	}
+	e.stack = stack.Build(2)
+	return e
+}
+
 func HTTPStatus(err error) int {
 	code := Code(err)
 	switch code {
@@ -210,17 +558,24 @@ func mergeMeta(md Metadata, pairs []interface{}) Metadata {
 	if n%2 != 0 {
 		panic(fmt.Sprintf("got uneven number (%d) of metadata key-values", n))
 	}
-	if md == nil && n > 0 {
-		md = make(Metadata, n/2)
+	if n == 0 {
+		return md
+	}
+
+	// Copy the existing metadata so we don't modify
+	// the metadata of the error we're merging from.
+	merged := make(Metadata, len(md)+n/2)
+	for k, v := range md {
+		merged[k] = v
 	}
 	for i := 0; i < n; i += 2 {
 		key, ok := pairs[i].(string)
 		if !ok {
 			panic(fmt.Sprintf("metadata key-value pair #%d key is not a string (is %T)", i/2, pairs[i]))
 		}
-		md[key] = pairs[i+1]
+		merged[key] = pairs[i+1]
 	}
-	return md
+	return merged
 }
 
 func init() {
@@ -235,6 +590,16 @@ func init() {
 		stream.WriteMore()
 		stream.WriteObjectField("details")
 		stream.WriteVal(e.Details)
+		if secs := retryAfterSeconds(e.RetryAfter); secs > 0 {
+			stream.WriteMore()
+			stream.WriteObjectField("retry_after_seconds")
+			stream.WriteInt64(secs)
+		}
+		if e.TraceID != "" {
+			stream.WriteMore()
+			stream.WriteObjectField("trace_id")
+			stream.WriteString(e.TraceID)
+		}
 		stream.WriteObjectEnd()
 	}, nil)
 
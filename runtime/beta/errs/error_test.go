@@ -0,0 +1,667 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"encore.dev/internal/stack"
+)
+
+func TestErrServer(t *testing.T) {
+	tests := []struct {
+		Code ErrCode
+		Want bool
+	}{
+		{Unknown, true},
+		{DeadlineExceeded, true},
+		{Unimplemented, true},
+		{Internal, true},
+		{Unavailable, true},
+		{DataLoss, true},
+		{Canceled, false},
+		{InvalidArgument, false},
+		{NotFound, false},
+		{AlreadyExists, false},
+		{PermissionDenied, false},
+		{ResourceExhausted, false},
+		{FailedPrecondition, false},
+		{Unauthenticated, false},
+	}
+
+	for _, test := range tests {
+		err := B().Code(test.Code).Msg("test").Err()
+		if got := errors.Is(err, ErrServer); got != test.Want {
+			t.Errorf("errors.Is(%s, ErrServer) = %v, want %v", test.Code, got, test.Want)
+		}
+
+		// The match should hold for wrapped errors as well.
+		wrapped := fmt.Errorf("wrapped: %w", err)
+		if got := errors.Is(wrapped, ErrServer); got != test.Want {
+			t.Errorf("errors.Is(wrapped %s, ErrServer) = %v, want %v", test.Code, got, test.Want)
+		}
+	}
+
+	if errors.Is(errors.New("plain"), ErrServer) {
+		t.Errorf("errors.Is(plain error, ErrServer) = true, want false")
+	}
+}
+
+func TestSensitiveMeta(t *testing.T) {
+	SensitiveMeta("test-password")
+
+	err := B().Code(Unauthenticated).Msg("login failed").Meta("test-password", "hunter2", "user", "alice").Err()
+
+	// In-process the raw values remain accessible.
+	if got := Meta(err)["test-password"]; got != "hunter2" {
+		t.Errorf("got in-process meta value %v, want %q", got, "hunter2")
+	}
+
+	want := Metadata{"test-password": "[redacted]", "user": "alice"}
+	if got := Meta(err).Redacted(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got redacted meta %v, want %v", got, want)
+	}
+	if got := Meta(RoundTrip(err)); !reflect.DeepEqual(got, want) {
+		t.Errorf("got round-tripped meta %v, want %v", got, want)
+	}
+
+	// Redacting must not modify the original metadata.
+	if got := Meta(err)["test-password"]; got != "hunter2" {
+		t.Errorf("original meta modified: got %v, want %q", got, "hunter2")
+	}
+}
+
+func TestLogFields(t *testing.T) {
+	SensitiveMeta("test-token")
+
+	cause := B().Code(NotFound).Msg("user not found").TraceID("trace-1").Err()
+	err := Wrap(cause, "lookup failed", "user", "alice", "attempts", 3, "test-token", "s3cr3t", "empty", nil).(*Error)
+
+	want := map[string]interface{}{
+		"error.code":            "not_found",
+		"error.message":         "lookup failed: user not found",
+		"error.trace_id":        "trace-1",
+		"error.meta.user":       "alice",
+		"error.meta.attempts":   3,
+		"error.meta.test-token": "[redacted]",
+	}
+	if got := err.LogFields(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got log fields %v, want %v", got, want)
+	}
+
+	// Empty values are omitted.
+	bare := &Error{Code: Internal}
+	if got, want := bare.LogFields(), map[string]interface{}{"error.code": "internal"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got log fields %v, want %v", got, want)
+	}
+}
+
+func TestMetaCopy(t *testing.T) {
+	err := B().Code(NotFound).Msg("not found").Meta(
+		"user", "alice",
+		"tags", []interface{}{"a", "b"},
+		"nested", map[string]interface{}{"k": "v"},
+	).Err()
+	e := err.(*Error)
+
+	cp := e.MetaCopy()
+	want := Metadata{
+		"user":   "alice",
+		"tags":   []interface{}{"a", "b"},
+		"nested": map[string]interface{}{"k": "v"},
+	}
+	if !reflect.DeepEqual(cp, want) {
+		t.Fatalf("got meta copy %v, want %v", cp, want)
+	}
+
+	// Modifying the copy must not modify the original metadata.
+	cp["user"] = "bob"
+	cp["extra"] = true
+	cp["tags"].([]interface{})[0] = "x"
+	cp["nested"].(map[string]interface{})["k"] = "changed"
+	if !reflect.DeepEqual(e.Meta, want) {
+		t.Errorf("original meta modified: got %v, want %v", e.Meta, want)
+	}
+
+	// The meta of round-tripped errors is independent of the original as well.
+	orig := B().Code(NotFound).Msg("not found").Meta("user", "alice").Err()
+	rt := RoundTrip(orig).(*Error)
+	rt.Meta["user"] = "bob"
+	if got := Meta(orig)["user"]; got != "alice" {
+		t.Errorf("original meta modified by round-tripped error: got %v, want %q", got, "alice")
+	}
+
+	if got := (&Error{Code: Internal}).MetaCopy(); got != nil {
+		t.Errorf("got meta copy %v for error without meta, want nil", got)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	orig := B().Code(NotFound).Msg("not found").Err()
+	tests := []struct {
+		Err  error
+		Want ErrCode
+	}{
+		{context.Canceled, Canceled},
+		{context.DeadlineExceeded, DeadlineExceeded},
+		{fmt.Errorf("query: %w", context.Canceled), Canceled},
+		{fmt.Errorf("query: %w", context.DeadlineExceeded), DeadlineExceeded},
+		{errors.New("boom"), Unknown},
+		{orig, NotFound},
+	}
+
+	for _, test := range tests {
+		err := Convert(test.Err)
+		e, ok := err.(*Error)
+		if !ok {
+			t.Fatalf("Convert(%v) returned %T, want *Error", test.Err, err)
+		}
+		if e.Code != test.Want {
+			t.Errorf("Convert(%v): got code %s, want %s", test.Err, e.Code, test.Want)
+		}
+		if got := Code(test.Err); got != test.Want {
+			t.Errorf("Code(%v) = %s, want %s", test.Err, got, test.Want)
+		}
+		if !errors.Is(err, test.Err) {
+			t.Errorf("errors.Is(Convert(%v), %v) = false, want true", test.Err, test.Err)
+		}
+	}
+
+	if got := Convert(orig); got != orig {
+		t.Errorf("Convert(*Error) = %v, want the error unchanged", got)
+	}
+	if got := Convert(nil); got != nil {
+		t.Errorf("Convert(nil) = %v, want nil", got)
+	}
+}
+
+func TestCode(t *testing.T) {
+	orig := B().Code(NotFound).Msg("not found").Err()
+	tests := []struct {
+		Name string
+		Err  error
+		Want ErrCode
+	}{
+		{"nil", nil, OK},
+		{"plain", errors.New("boom"), Unknown},
+		{"direct", orig, NotFound},
+		{"wrapped", fmt.Errorf("get user: %w", orig), NotFound},
+		{"wrapped twice", fmt.Errorf("handler: %w", fmt.Errorf("get user: %w", orig)), NotFound},
+		{"Wrap", Wrap(orig, "get user"), NotFound},
+		{"WrapCode", WrapCode(errors.New("boom"), Unavailable, "get user"), Unavailable},
+		{"RoundTrip", RoundTrip(fmt.Errorf("get user: %w", orig)), NotFound},
+	}
+
+	for _, test := range tests {
+		if got := Code(test.Err); got != test.Want {
+			t.Errorf("%s: Code(%v) = %s, want %s", test.Name, test.Err, got, test.Want)
+		}
+		if !HasCode(test.Err, test.Want) {
+			t.Errorf("%s: HasCode(%v, %s) = false, want true", test.Name, test.Err, test.Want)
+		}
+		if HasCode(test.Err, Internal) {
+			t.Errorf("%s: HasCode(%v, %s) = true, want false", test.Name, test.Err, Internal)
+		}
+	}
+
+	if got := Convert(fmt.Errorf("get user: %w", orig)).(*Error).Code; got != NotFound {
+		t.Errorf("Convert of a wrapped *Error: got code %s, want %s", got, NotFound)
+	}
+}
+
+func TestIsCode(t *testing.T) {
+	orig := B().Code(NotFound).Msg("not found").Err()
+	tests := []struct {
+		Name   string
+		Err    error
+		Target ErrCode
+		Want   bool
+	}{
+		{"match", orig, NotFound, true},
+		{"mismatch", orig, PermissionDenied, false},
+		{"wrapped", fmt.Errorf("get user: %w", orig), NotFound, true},
+		{"wrapped mismatch", fmt.Errorf("get user: %w", orig), Internal, false},
+		{"Wrap", Wrap(orig, "get user"), NotFound, true},
+		{"WrapCode", WrapCode(errors.New("boom"), Unavailable, "get user"), Unavailable, true},
+		{"RoundTrip", RoundTrip(orig), NotFound, true},
+		{"plain", errors.New("not found"), NotFound, false},
+		{"nil", nil, OK, false},
+	}
+
+	for _, test := range tests {
+		if got := errors.Is(test.Err, test.Target); got != test.Want {
+			t.Errorf("%s: errors.Is(%v, %s) = %v, want %v", test.Name, test.Err, test.Target, got, test.Want)
+		}
+	}
+}
+
+func TestRoundTripWithStack(t *testing.T) {
+	err := B().Code(Internal).Msg("boom").Meta("key", "value").Err()
+	orig := Stack(err)
+	if len(orig.Frames) == 0 {
+		t.Fatal("got no stack frames for the original error")
+	}
+
+	rt := RoundTripWithStack(err)
+	want := stack.Resolve(orig)
+	if got := rt.(*Error).frames; !reflect.DeepEqual(got, want) {
+		t.Errorf("got frames %v, want %v", got, want)
+	}
+	if got, want := CleanStack(rt), CleanStack(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("got clean stack %v, want %v", got, want)
+	}
+	if got, want := CleanStack(Wrap(RoundTripWithStack(rt), "wrapped")), CleanStack(err); !reflect.DeepEqual(got, want) {
+		t.Errorf("got clean stack %v after another round trip and wrapping, want %v", got, want)
+	}
+	if Code(rt) != Internal || rt.(*Error).Message != "boom" {
+		t.Errorf("got %v, want an equivalent error", rt)
+	}
+	if !reflect.DeepEqual(Meta(rt), Meta(err)) {
+		t.Errorf("got meta %v, want %v", Meta(rt), Meta(err))
+	}
+
+	if got := RoundTripWithStack(nil); got != nil {
+		t.Errorf("RoundTripWithStack(nil) = %v, want nil", got)
+	}
+}
+
+func TestRoundTripJSON(t *testing.T) {
+	SensitiveMeta("json-secret")
+	err := B().Code(NotFound).Msg("user not found").
+		Details(quotaDetails{Limit: 10, Used: 11}).
+		Meta("user", "u1", "json-secret", "hunter2").
+		RetryAfter(1500 * time.Millisecond).Err()
+	err.(*Error).TraceID = "trace-1"
+
+	data, jsonErr := RoundTripJSON(err)
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	rt, ok := FromJSON(data).(*Error)
+	if !ok {
+		t.Fatalf("got %T, want *Error", FromJSON(data))
+	}
+	if rt.Code != NotFound || rt.Message != "user not found" || rt.TraceID != "trace-1" || rt.RetryAfter != 1500*time.Millisecond {
+		t.Errorf("got %+v, want an equivalent error", rt)
+	}
+	if want := (Metadata{"user": "u1", "json-secret": redacted}); !reflect.DeepEqual(rt.Meta, want) {
+		t.Errorf("got meta %v, want %v", rt.Meta, want)
+	}
+
+	raw, ok := rt.Details.(RawDetails)
+	if !ok {
+		t.Fatalf("got details of type %T, want RawDetails", rt.Details)
+	}
+	var det quotaDetails
+	if err := json.Unmarshal(raw, &det); err != nil {
+		t.Fatal(err)
+	} else if det != (quotaDetails{Limit: 10, Used: 11}) {
+		t.Errorf("got details %+v, want %+v", det, quotaDetails{Limit: 10, Used: 11})
+	}
+
+	// Other errors are serialized like RoundTrip copies them.
+	data, jsonErr = RoundTripJSON(context.Canceled)
+	if jsonErr != nil {
+		t.Fatal(jsonErr)
+	}
+	if got := FromJSON(data); Code(got) != Canceled || got.Error() != "canceled: "+context.Canceled.Error() {
+		t.Errorf("got %v, want a canceled error", got)
+	}
+
+	if data, jsonErr := RoundTripJSON(nil); data != nil || jsonErr != nil {
+		t.Errorf("RoundTripJSON(nil) = %q, %v, want nil, nil", data, jsonErr)
+	}
+	if got := FromJSON(nil); got != nil {
+		t.Errorf("FromJSON(nil) = %v, want nil", got)
+	}
+	if got := FromJSON([]byte(`{"message": "no code"}`)); Code(got) != Internal {
+		t.Errorf("got code %v for JSON without a code, want %v", Code(got), Internal)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	err := B().Code(Unavailable).Msg("try again later").RetryAfter(5 * time.Second).Err()
+
+	rt := RoundTrip(err)
+	if got := rt.(*Error).RetryAfter; got != 5*time.Second {
+		t.Errorf("got RetryAfter %v after RoundTrip, want %v", got, 5*time.Second)
+	}
+	if Code(rt) != Unavailable {
+		t.Errorf("got code %v after RoundTrip, want %v", Code(rt), Unavailable)
+	}
+
+	data, err2 := json.Marshal(rt)
+	if err2 != nil {
+		t.Fatal(err2)
+	}
+	if want := `"retry_after_seconds":5`; !strings.Contains(string(data), want) {
+		t.Errorf("got JSON %s, want it to contain %s", data, want)
+	}
+
+	// Without a hint the field is omitted.
+	data, err2 = json.Marshal(B().Code(Unavailable).Msg("down").Err())
+	if err2 != nil {
+		t.Fatal(err2)
+	}
+	if strings.Contains(string(data), "retry_after_seconds") {
+		t.Errorf("got JSON %s, want no retry_after_seconds", data)
+	}
+
+	w := httptest.NewRecorder()
+	HTTPError(w, err)
+	if got := w.Header().Get("Retry-After"); got != "5" {
+		t.Errorf("got Retry-After header %q, want %q", got, "5")
+	}
+}
+
+func TestTraceID(t *testing.T) {
+	err := B().Code(NotFound).Msg("no such user").TraceID("trace-1").Err()
+
+	// The trace ID survives wrapping and round trips.
+	wrapped := Wrap(err, "get user")
+	if got := wrapped.(*Error).TraceID; got != "trace-1" {
+		t.Errorf("got TraceID %q after Wrap, want %q", got, "trace-1")
+	}
+	rt := RoundTrip(wrapped).(*Error)
+	if rt.TraceID != "trace-1" {
+		t.Errorf("got TraceID %q after RoundTrip, want %q", rt.TraceID, "trace-1")
+	}
+
+	// Simulate sending the error over the wire.
+	data, err2 := json.Marshal(rt)
+	if err2 != nil {
+		t.Fatal(err2)
+	}
+	if want := `"trace_id":"trace-1"`; !strings.Contains(string(data), want) {
+		t.Errorf("got JSON %s, want it to contain %s", data, want)
+	}
+	var decoded Error
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	} else if decoded.TraceID != "trace-1" {
+		t.Errorf("got TraceID %q after decoding, want %q", decoded.TraceID, "trace-1")
+	}
+
+	// The outer error's trace ID takes precedence.
+	outer := B().Cause(err).TraceID("trace-2").Err()
+	if got := outer.(*Error).TraceID; got != "trace-2" {
+		t.Errorf("got TraceID %q, want %q", got, "trace-2")
+	}
+	inner := B().Code(Internal).Msg("boom").Err()
+	outer = B().Cause(inner).TraceID("trace-3").Err()
+	if got := RoundTrip(outer).(*Error).TraceID; got != "trace-3" {
+		t.Errorf("got TraceID %q, want %q", got, "trace-3")
+	}
+
+	// Without a trace ID the field is omitted.
+	data, err2 = json.Marshal(inner)
+	if err2 != nil {
+		t.Fatal(err2)
+	}
+	if strings.Contains(string(data), "trace_id") {
+		t.Errorf("got JSON %s, want no trace_id", data)
+	}
+}
+
+func TestFromHTTPResponse(t *testing.T) {
+	t.Run("encore error", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		HTTPError(w, B().Code(NotFound).Msg("user not found").
+			Details(userDetails{UserID: "u1"}).RetryAfter(3*time.Second).Err())
+
+		err := FromHTTPResponse(w.Result())
+		e, ok := err.(*Error)
+		if !ok {
+			t.Fatalf("got %T, want *Error", err)
+		}
+		if e.Code != NotFound {
+			t.Errorf("got code %v, want %v", e.Code, NotFound)
+		}
+		if e.Message != "user not found" {
+			t.Errorf("got message %q, want %q", e.Message, "user not found")
+		}
+		if e.RetryAfter != 3*time.Second {
+			t.Errorf("got RetryAfter %v, want %v", e.RetryAfter, 3*time.Second)
+		}
+		raw, ok := e.Details.(RawDetails)
+		if !ok {
+			t.Fatalf("got details of type %T, want RawDetails", e.Details)
+		}
+		var det userDetails
+		if err := json.Unmarshal(raw, &det); err != nil {
+			t.Fatal(err)
+		} else if det.UserID != "u1" {
+			t.Errorf("got details %+v, want UserID u1", det)
+		}
+	})
+
+	t.Run("plain text", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: 500,
+			Body:       io.NopCloser(strings.NewReader("something went wrong\n")),
+		}
+		err := FromHTTPResponse(resp)
+		if Code(err) != Internal {
+			t.Errorf("got code %v, want %v", Code(err), Internal)
+		}
+		if got := err.(*Error).Message; got != "something went wrong" {
+			t.Errorf("got message %q, want %q", got, "something went wrong")
+		}
+	})
+
+	t.Run("empty body", func(t *testing.T) {
+		resp := &http.Response{StatusCode: 503, Body: io.NopCloser(strings.NewReader(""))}
+		err := FromHTTPResponse(resp)
+		if Code(err) != Unavailable {
+			t.Errorf("got code %v, want %v", Code(err), Unavailable)
+		}
+		if got := err.(*Error).Message; got != "Service Unavailable" {
+			t.Errorf("got message %q, want %q", got, "Service Unavailable")
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		resp := &http.Response{StatusCode: 204, Body: io.NopCloser(strings.NewReader(""))}
+		if err := FromHTTPResponse(resp); err != nil {
+			t.Errorf("got %v, want nil", err)
+		}
+	})
+}
+
+type userDetails struct{ UserID string }
+type quotaDetails struct{ Limit, Used int }
+type retryDetails struct{ Attempts []int }
+
+func (userDetails) ErrDetails()   {}
+func (quotaDetails) ErrDetails()  {}
+func (*retryDetails) ErrDetails() {}
+
+type privateDetails struct {
+	UserID string
+	token  string
+}
+type chanDetails struct{ Done chan struct{} }
+type mapDetails struct{ Counts map[string]int }
+
+func (privateDetails) ErrDetails() {}
+func (chanDetails) ErrDetails()    {}
+func (mapDetails) ErrDetails()     {}
+
+func TestValidateDetails(t *testing.T) {
+	tests := []struct {
+		Details ErrDetails
+		Err     string
+	}{
+		{nil, ""},
+		{userDetails{UserID: "u1"}, ""},
+		{&retryDetails{Attempts: []int{1, 2}}, ""},
+		{mapDetails{Counts: map[string]int{"a": 1}}, ""},
+		{privateDetails{UserID: "u1"}, "errs: details type errs.privateDetails: field token is unexported and is lost by RoundTrip"},
+		{chanDetails{Done: make(chan struct{})}, "errs: details type errs.chanDetails: field Done of type chan struct {} is lost by RoundTrip"},
+		// Gob does not transmit empty slices, so they are decoded as nil.
+		{&retryDetails{Attempts: []int{}}, "errs: details type *errs.retryDetails is not preserved by RoundTrip: got &errs.retryDetails{Attempts:[]int(nil)}, want &errs.retryDetails{Attempts:[]int{}}"},
+	}
+	for _, test := range tests {
+		err := ValidateDetails(test.Details)
+		if test.Err == "" && err != nil {
+			t.Errorf("ValidateDetails(%#v) = %v, want nil", test.Details, err)
+		} else if test.Err != "" && (err == nil || err.Error() != test.Err) {
+			t.Errorf("ValidateDetails(%#v) = %v, want %s", test.Details, err, test.Err)
+		}
+	}
+}
+
+func TestRetryable(t *testing.T) {
+	tests := []struct {
+		Code ErrCode
+		Want bool
+	}{
+		{OK, false},
+		{Canceled, false},
+		{Unknown, false},
+		{InvalidArgument, false},
+		{DeadlineExceeded, true},
+		{NotFound, false},
+		{AlreadyExists, false},
+		{PermissionDenied, false},
+		{ResourceExhausted, true},
+		{FailedPrecondition, false},
+		{Aborted, true},
+		{OutOfRange, false},
+		{Unimplemented, false},
+		{Internal, false},
+		{Unavailable, true},
+		{DataLoss, false},
+		{Unauthenticated, false},
+	}
+	if len(tests) != len(codeNames) {
+		t.Fatalf("got %d test cases, want one for each of the %d codes", len(tests), len(codeNames))
+	}
+
+	for _, test := range tests {
+		err := &Error{Code: test.Code, Message: "test"}
+		if got := Retryable(err); got != test.Want {
+			t.Errorf("Retryable(%s) = %v, want %v", test.Code, got, test.Want)
+		}
+
+		// The result should hold for wrapped errors as well.
+		wrapped := fmt.Errorf("wrapped: %w", err)
+		if got := Retryable(wrapped); got != test.Want {
+			t.Errorf("Retryable(wrapped %s) = %v, want %v", test.Code, got, test.Want)
+		}
+
+		// A retry-after hint takes precedence over the code.
+		err.RetryAfter = time.Second
+		if !Retryable(err) {
+			t.Errorf("Retryable(%s with RetryAfter) = false, want true", test.Code)
+		}
+	}
+
+	if Retryable(nil) {
+		t.Errorf("Retryable(nil) = true, want false")
+	}
+	if Retryable(errors.New("plain")) {
+		t.Errorf("Retryable(plain error) = true, want false")
+	}
+	if !Retryable(context.DeadlineExceeded) {
+		t.Errorf("Retryable(context.DeadlineExceeded) = false, want true")
+	}
+}
+
+func TestRoundTripConcurrent(t *testing.T) {
+	details := []ErrDetails{
+		userDetails{UserID: "u1"},
+		quotaDetails{Limit: 10, Used: 11},
+		&retryDetails{Attempts: []int{1, 2, 3}},
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		for _, d := range details {
+			wg.Add(1)
+			go func(d ErrDetails) {
+				defer wg.Done()
+				err := &Error{Code: Internal, Message: "oops", Details: d}
+				rt, ok := RoundTrip(err).(*Error)
+				if !ok {
+					t.Errorf("RoundTrip returned %T, want *Error", rt)
+					return
+				}
+				if !reflect.DeepEqual(rt.Details, d) {
+					t.Errorf("got details %#v after RoundTrip, want %#v", rt.Details, d)
+				}
+			}(d)
+		}
+	}
+	wg.Wait()
+}
+
+func TestCleanStack(t *testing.T) {
+	frames := []stack.Frame{
+		{Function: "runtime.gopanic", File: "runtime/panic.go", Line: 838},
+		{Function: "reflect.Value.call", File: "reflect/value.go", Line: 556},
+		{Function: "encore.app/svc.lookup", File: "svc/svc.go", Line: 20},
+		{Function: "encore.dev/storage/sqldb.(*Database).QueryRow", File: "sqldb/db.go", Line: 90},
+		{Function: "encore.app/svc.GetUser", File: "svc/svc.go", Line: 12},
+		{Function: "encore.dev/appruntime/api.(*Server).handler", File: "api/server.go", Line: 310},
+		{Function: "encore.dev/appruntime/api.(*Server).ServeHTTP", File: "api/server.go", Line: 200},
+	}
+
+	// Framework frames are only removed from the ends of the stack.
+	want := frames[2:5]
+	if got := cleanFrames(frames); !reflect.DeepEqual(got, want) {
+		t.Errorf("got frames %v, want %v", got, want)
+	}
+
+	// A stack consisting only of framework frames is removed entirely.
+	if got := cleanFrames(frames[5:]); got != nil {
+		t.Errorf("got frames %v, want nil", got)
+	}
+
+	SetCleanStackPackages([]string{"runtime"}, []string{"encore.dev/appruntime", "encore.app/svc"})
+	defer SetCleanStackPackages([]string{"runtime", "reflect"}, []string{"encore.dev"})
+	want = frames[1:4]
+	if got := cleanFrames(frames); !reflect.DeepEqual(got, want) {
+		t.Errorf("got frames %v with custom packages, want %v", got, want)
+	}
+
+	if got := CleanStack(errors.New("plain")); got != nil {
+		t.Errorf("CleanStack(plain error) = %v, want nil", got)
+	}
+}
+
+func TestNewStack(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"New", New(NotFound, "user not found")},
+		{"Newf", Newf(NotFound, "user %d not found", 5)},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			e, ok := test.err.(*Error)
+			if !ok {
+				t.Fatalf("got %T, want *Error", test.err)
+			}
+			if e.Code != NotFound || !strings.HasPrefix(e.Message, "user ") {
+				t.Errorf("got code %v and message %q", e.Code, e.Message)
+			}
+
+			frames := runtime.CallersFrames(Stack(test.err).Frames)
+			f, _ := frames.Next()
+			if want := "encore.dev/beta/errs.TestNewStack"; f.Function != want {
+				t.Errorf("got top frame %q, want %q", f.Function, want)
+			}
+		})
+	}
+}
@@ -3,7 +3,14 @@ package errs
 import (
 	"bytes"
 	"encoding/gob"
+	"fmt"
 	"log"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	jsoniter "github.com/json-iterator/go"
 
 	"encore.dev/internal/stack"
 )
@@ -44,6 +51,70 @@ func DropStackFrame(err error) error {
 	return err
 }
 
+var (
+	cleanStackMu sync.RWMutex
+	// leadingStackPkgs and trailingStackPkgs are the package path prefixes
+	// of the frames CleanStack removes from the start and end of stacks.
+	leadingStackPkgs  = []string{"runtime", "reflect"}
+	trailingStackPkgs = []string{"encore.dev"}
+)
+
+// SetCleanStackPackages configures the package path prefixes CleanStack uses.
+// Leading frames (the innermost calls) are removed while their package matches
+// one of leading, and trailing frames (the outermost calls) are removed while
+// their package matches one of trailing. A prefix matches a package path if
+// it is equal to it or a parent of it, so "encore.dev" matches "encore.dev/rlog".
+//
+// By default leading is ["runtime", "reflect"] and trailing is ["encore.dev"].
+// It is typically called during initialization.
+func SetCleanStackPackages(leading, trailing []string) {
+	cleanStackMu.Lock()
+	defer cleanStackMu.Unlock()
+	leadingStackPkgs = append([]string(nil), leading...)
+	trailingStackPkgs = append([]string(nil), trailing...)
+}
+
+// CleanStack returns the stack of err with leading runtime frames and
+// trailing framework frames removed, leaving the frames of user code,
+// as configured by SetCleanStackPackages. It returns nil if err is not
+// an *Error or has no stack.
+//
+// For errors copied with RoundTripWithStack it is the stack of
+// the original error.
+func CleanStack(err error) []stack.Frame {
+	if e, ok := err.(*Error); ok && e.frames != nil {
+		return cleanFrames(append([]stack.Frame(nil), e.frames...))
+	}
+	return cleanFrames(stack.Resolve(Stack(err)))
+}
+
+// cleanFrames implements CleanStack for resolved frames.
+func cleanFrames(frames []stack.Frame) []stack.Frame {
+	cleanStackMu.RLock()
+	defer cleanStackMu.RUnlock()
+	for len(frames) > 0 && hasPkgPrefix(frames[0].Package(), leadingStackPkgs) {
+		frames = frames[1:]
+	}
+	for len(frames) > 0 && hasPkgPrefix(frames[len(frames)-1].Package(), trailingStackPkgs) {
+		frames = frames[:len(frames)-1]
+	}
+	if len(frames) == 0 {
+		return nil
+	}
+	return frames
+}
+
+// hasPkgPrefix reports whether pkg is equal to or
+// within one of the package paths in prefixes.
+func hasPkgPrefix(pkg string, prefixes []string) bool {
+	for _, p := range prefixes {
+		if pkg == p || strings.HasPrefix(pkg, p+"/") {
+			return true
+		}
+	}
+	return false
+}
+
 // RoundTrip copies an error, returning an equivalent error
 // for replicating across RPC boundaries.
 func RoundTrip(err error) error {
@@ -51,15 +122,17 @@ func RoundTrip(err error) error {
 		return nil
 	} else if e, ok := err.(*Error); ok {
 		e2 := &Error{
-			Code:    e.Code,
-			Message: e.Message,
-			stack:   stack.Build(3), // skip caller of RoundTrip as well
+			Code:       e.Code,
+			Message:    e.Message,
+			RetryAfter: e.RetryAfter,
+			TraceID:    e.TraceID,
+			stack:      stack.Build(3), // skip caller of RoundTrip as well
 		}
 
 		// Copy details
 		if e.Details != nil {
 			var buf bytes.Buffer
-			gob.Register(e.Details)
+			registerDetails(e.Details)
 			enc := gob.NewEncoder(&buf)
 			if err := enc.Encode(struct{ Details ErrDetails }{Details: e.Details}); err != nil {
 				log.Printf("failed to encode error details: %v", err)
@@ -74,11 +147,13 @@ func RoundTrip(err error) error {
 			}
 		}
 
-		// Copy meta
+		// Copy meta, redacting sensitive values.
+		// Encoding and decoding it means the copy shares no
+		// maps or slices with the original.
 		if e.Meta != nil {
 			var buf bytes.Buffer
 			enc := gob.NewEncoder(&buf)
-			if err := enc.Encode(e.Meta); err != nil {
+			if err := enc.Encode(e.Meta.Redacted()); err != nil {
 				log.Printf("failed to encode error metadata: %v", err)
 			} else {
 				dec := gob.NewDecoder(&buf)
@@ -91,9 +166,132 @@ func RoundTrip(err error) error {
 		return e2
 	} else {
 		return &Error{
-			Code:    Unknown,
+			Code:    Code(err),
 			Message: err.Error(),
 			stack:   stack.Build(3), // skip caller of RoundTrip as well
 		}
 	}
 }
+
+// registeredDetails tracks the error detail types registered with gob,
+// mapping each concrete type to a *sync.Once guarding its registration.
+var registeredDetails sync.Map // reflect.Type -> *sync.Once
+
+// registerDetails registers the concrete type of details with gob
+// so it can be encoded as an ErrDetails interface value.
+// Each type is registered exactly once, and registerDetails
+// does not return until the type has been registered.
+func registerDetails(details ErrDetails) {
+	once, _ := registeredDetails.LoadOrStore(reflect.TypeOf(details), new(sync.Once))
+	once.(*sync.Once).Do(func() { gob.Register(details) })
+}
+
+// RoundTripWithStack is like RoundTrip but preserves the stack trace
+// of the original error, so that the error can be traced back to where
+// it originated rather than to where it crossed the RPC boundary.
+// The stack is copied as resolved frames, since program counters are
+// only meaningful in the process they were captured in, and is what
+// CleanStack returns for the copy.
+// If err is not an *Error the stack is captured like RoundTrip does.
+func RoundTripWithStack(err error) error {
+	e2, ok := RoundTrip(err).(*Error)
+	if !ok {
+		return nil
+	}
+	e2.stack = stack.Build(3) // skip caller of RoundTripWithStack as well
+	if e, ok := err.(*Error); ok {
+		if e.frames != nil {
+			e2.frames = append([]stack.Frame(nil), e.frames...)
+		} else {
+			e2.frames = stack.Resolve(e.stack)
+		}
+	}
+	return e2
+}
+
+// roundTripJSON is the JSON representation of an *Error
+// written by RoundTripJSON and read by FromJSON.
+type roundTripJSON struct {
+	Code         ErrCode    `json:"code"`
+	Message      string     `json:"message"`
+	Details      ErrDetails `json:"details,omitempty"`
+	Meta         Metadata   `json:"meta,omitempty"`
+	RetryAfterNS int64      `json:"retry_after_ns,omitempty"`
+	TraceID      string     `json:"trace_id,omitempty"`
+}
+
+// RoundTripJSON is like RoundTrip but serializes the error to JSON instead
+// of copying it, for replicating errors to consumers not written in Go.
+// Unlike MarshalJSON, which is meant for external clients, the JSON
+// includes the error's metadata, with sensitive values redacted.
+//
+// The JSON is an object with the fields "code" (the code's string form),
+// "message", "details", "meta", "retry_after_ns" (the retry-after hint
+// in nanoseconds) and "trace_id", all but code and message omitted when
+// empty. The details are written using their JSON encoding, so details
+// types must marshal to JSON, and unmarshal from it to be read back.
+//
+// If err is not an *Error it is serialized like RoundTrip would copy it.
+// RoundTripJSON returns nil if err is nil.
+func RoundTripJSON(err error) ([]byte, error) {
+	if err == nil {
+		return nil, nil
+	}
+	e, ok := err.(*Error)
+	if !ok {
+		e = &Error{Code: Code(err), Message: err.Error()}
+	}
+	return json.Marshal(roundTripJSON{
+		Code:         e.Code,
+		Message:      e.Message,
+		Details:      e.Details,
+		Meta:         e.Meta.Redacted(),
+		RetryAfterNS: int64(e.RetryAfter),
+		TraceID:      e.TraceID,
+	})
+}
+
+// FromJSON returns the error serialized by RoundTripJSON.
+// Since the concrete type of the details is not known, any details
+// are returned as RawDetails for the caller to unmarshal, and metadata
+// values have the types encoding/json decodes into an interface{},
+// such as float64 for numbers.
+//
+// FromJSON returns nil if data is empty or the JSON null value.
+// If data is not a serialized error it returns an *Error with code
+// Internal describing the problem.
+//
+//go:noinline
+func FromJSON(data []byte) error {
+	if s := strings.TrimSpace(string(data)); s == "" || s == "null" {
+		return nil
+	}
+	var dst struct {
+		Code         *ErrCode            `json:"code"`
+		Message      string              `json:"message"`
+		Details      jsoniter.RawMessage `json:"details"`
+		Meta         Metadata            `json:"meta"`
+		RetryAfterNS int64               `json:"retry_after_ns"`
+		TraceID      string              `json:"trace_id"`
+	}
+	if err := json.Unmarshal(data, &dst); err != nil {
+		return &Error{Code: Internal, Message: fmt.Sprintf("errs: invalid error JSON: %v", err),
+			underlying: err, stack: callerStack(FromJSON)}
+	} else if dst.Code == nil {
+		return &Error{Code: Internal, Message: "errs: invalid error JSON: missing error code",
+			stack: callerStack(FromJSON)}
+	}
+
+	e := &Error{
+		Code:       *dst.Code,
+		Message:    dst.Message,
+		Meta:       dst.Meta,
+		RetryAfter: time.Duration(dst.RetryAfterNS),
+		TraceID:    dst.TraceID,
+		stack:      callerStack(FromJSON),
+	}
+	if len(dst.Details) > 0 && string(dst.Details) != "null" {
+		e.Details = RawDetails(dst.Details)
+	}
+	return e
+}
@@ -0,0 +1,40 @@
+package errs
+
+import "errors"
+
+// internalServerError is the message of errors masked by Externalize.
+const internalServerError = "Internal server error"
+
+// serverFaultCodes are the codes of errors whose messages
+// Externalize hides from external clients.
+var serverFaultCodes = map[ErrCode]bool{
+	Unknown:  true,
+	Internal: true,
+	DataLoss: true,
+}
+
+// Externalize prepares err to be returned to external clients,
+// hiding the details of server faults.
+//
+// If Code(err) is Unknown, Internal or DataLoss it returns a new *Error
+// with the same code and the message "Internal server error", without
+// any details or metadata. The trace ID of the first *Error in err's
+// chain, as found by errors.As, is preserved so the error can still be
+// correlated with the internal one. Other errors, including all client
+// faults like NotFound or InvalidArgument, are returned unchanged.
+// If err is nil it returns nil.
+//
+// Since errors not created by this package report Unknown,
+// their messages are hidden as well.
+func Externalize(err error) error {
+	code := Code(err)
+	if !serverFaultCodes[code] {
+		return err
+	}
+	e := &Error{Code: code, Message: internalServerError}
+	var ee *Error
+	if errors.As(err, &ee) {
+		e.TraceID = ee.TraceID
+	}
+	return e
+}
@@ -0,0 +1,68 @@
+package errs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestExternalizeServerFaults(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantCode ErrCode
+		wantID   string
+	}{
+		{"internal", B().Code(Internal).Msg("db password rejected").TraceID("trace-1").
+			Details(userDetails{UserID: "u1"}).Meta("query", "SELECT 1").Err(), Internal, "trace-1"},
+		{"unknown", B().Code(Unknown).Msg("unexpected state").Err(), Unknown, ""},
+		{"data loss", B().Code(DataLoss).Msg("corrupt row 42").TraceID("trace-2").Err(), DataLoss, "trace-2"},
+		{"wrapped", fmt.Errorf("load: %w", B().Code(Internal).Msg("boom").TraceID("trace-3").Err()), Internal, "trace-3"},
+		{"plain", errors.New("open /etc/app.conf: permission denied"), Unknown, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			got, ok := Externalize(test.err).(*Error)
+			if !ok {
+				t.Fatalf("got %T, want *Error", Externalize(test.err))
+			}
+			if got.Code != test.wantCode {
+				t.Errorf("got code %v, want %v", got.Code, test.wantCode)
+			}
+			if got.Message != "Internal server error" {
+				t.Errorf("got message %q, want %q", got.Message, "Internal server error")
+			}
+			if got.TraceID != test.wantID {
+				t.Errorf("got trace id %q, want %q", got.TraceID, test.wantID)
+			}
+			if got.Details != nil || got.Meta != nil {
+				t.Errorf("got details %v and meta %v, want none", got.Details, got.Meta)
+			}
+			if got.Unwrap() != nil {
+				t.Errorf("got underlying error %v, want nil", got.Unwrap())
+			}
+		})
+	}
+}
+
+func TestExternalizeClientFaults(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+	}{
+		{"nil", nil},
+		{"not found", B().Code(NotFound).Msg("no such user").Details(userDetails{UserID: "u1"}).Err()},
+		{"invalid argument", B().Code(InvalidArgument).Msg("bad name").Meta("field", "name").Err()},
+		{"permission denied", B().Code(PermissionDenied).Msg("not an admin").Err()},
+		{"unavailable", B().Code(Unavailable).Msg("try later").Err()},
+		{"canceled", context.Canceled},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := Externalize(test.err); got != test.err {
+				t.Errorf("Externalize(err) = %v, want err unchanged", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,12 @@
+// Package config provides access to the configuration of Encore services.
+package config
+
+// Load returns the configuration of the calling service,
+// decoded into the config struct type T.
+//
+// Load must be called with a named struct type, at most once per service.
+// Calls are recognized by the Encore compiler which provides the
+// configuration, so it must be used within an Encore application.
+func Load[T any]() *T {
+	panic("encore apps must be run using the encore command")
+}
@@ -3,8 +3,10 @@ package cron
 type Duration int64
 
 const (
-	Minute Duration = 60
+	Second Duration = 1
+	Minute Duration = 60 * Second
 	Hour   Duration = 60 * Minute
+	Day    Duration = 24 * Hour
 )
 
 type JobConfig struct {
@@ -47,6 +47,43 @@ func Build(skip int) Stack {
 	return Stack{Frames: pcs, Off: off}
 }
 
+// A Frame is a resolved frame of a Stack.
+type Frame struct {
+	Function string // fully qualified function name, such as "encore.dev/beta/errs.Wrap"
+	File     string
+	Line     int
+}
+
+// Package returns the import path of the package the frame's function belongs to.
+func (f Frame) Package() string {
+	name := f.Function
+	// The last path element may contain dots only in escaped form,
+	// so the package path ends at the first dot after the last slash.
+	slash := strings.LastIndexByte(name, '/')
+	if dot := strings.IndexByte(name[slash+1:], '.'); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
+
+// Resolve resolves the program counters of s into frames,
+// innermost call first.
+func Resolve(s Stack) []Frame {
+	if len(s.Frames) == 0 {
+		return nil
+	}
+	frames := make([]Frame, 0, len(s.Frames))
+	cf := runtime.CallersFrames(s.Frames)
+	for {
+		f, more := cf.Next()
+		frames = append(frames, Frame{Function: f.Function, File: f.File, Line: f.Line})
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
 func Print(s Stack) {
 	var b bytes.Buffer
 	cf := runtime.CallersFrames(s.Frames)
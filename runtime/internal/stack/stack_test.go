@@ -21,3 +21,22 @@ func userCode() int {
 	s := Build(2)
 	return len(s.Frames)
 }
+
+func TestFramePackage(t *testing.T) {
+	tests := []struct {
+		Function string
+		Want     string
+	}{
+		{"main.main", "main"},
+		{"runtime.gopanic", "runtime"},
+		{"encore.dev/beta/errs.Wrap", "encore.dev/beta/errs"},
+		{"encore.dev/beta/errs.(*Builder).Err", "encore.dev/beta/errs"},
+		{"encore.app/svc.GetUser.func1", "encore.app/svc"},
+		{"gopkg.in/yaml%2ev3.Unmarshal", "gopkg.in/yaml%2ev3"},
+	}
+	for _, test := range tests {
+		if got := (Frame{Function: test.Function}).Package(); got != test.Want {
+			t.Errorf("Frame{Function: %q}.Package() = %q, want %q", test.Function, got, test.Want)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+// Package pubsub provides asynchronous messaging between services
+// using topics and subscriptions.
+package pubsub
+
+import "context"
+
+// DeliveryGuarantee is the delivery guarantee of a topic.
+type DeliveryGuarantee int
+
+const (
+	// AtLeastOnce guarantees that each message is delivered to each
+	// subscription at least once. Handlers must be idempotent.
+	AtLeastOnce DeliveryGuarantee = iota + 1
+	// ExactlyOnce guarantees that each message is delivered
+	// to each subscription exactly once.
+	ExactlyOnce
+)
+
+// TopicConfig configures a topic.
+type TopicConfig struct {
+	DeliveryGuarantee DeliveryGuarantee
+}
+
+// A Topic is a named channel messages of type T are published to.
+type Topic[T any] struct {
+	Name   string
+	Config TopicConfig
+}
+
+// NewTopic declares a new topic. It must be called
+// as a package-level variable declaration, and the topic
+// name must be unique within the application.
+func NewTopic[T any](name string, cfg TopicConfig) *Topic[T] {
+	return &Topic[T]{Name: name, Config: cfg}
+}
+
+// Publish publishes msg to the topic and returns the id of the message.
+func (t *Topic[T]) Publish(ctx context.Context, msg *T) (id string, err error) {
+	panic("encore apps must be run using the encore command")
+}
+
+// SubscriptionConfig configures a subscription.
+type SubscriptionConfig[T any] struct {
+	// Handler is called for each message published to the topic.
+	// It must be a package-level function.
+	Handler func(ctx context.Context, msg *T) error
+}
+
+// A Subscription receives the messages published to a topic.
+type Subscription[T any] struct {
+	Topic  *Topic[T]
+	Name   string
+	Config SubscriptionConfig[T]
+}
+
+// NewSubscription declares a new subscription to topic. It must be called
+// as a package-level variable declaration within a service, and the
+// subscription name must be unique for the topic.
+func NewSubscription[T any](topic *Topic[T], name string, cfg SubscriptionConfig[T]) *Subscription[T] {
+	return &Subscription[T]{Topic: topic, Name: name, Config: cfg}
+}
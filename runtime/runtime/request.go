@@ -363,7 +363,7 @@ func finishReq(outputs [][]byte, err error, httpStatus int) {
 		default:
 			e := errs.Convert(err).(*errs.Error)
 			ev := req.Logger.Error()
-			for k, v := range e.Meta {
+			for k, v := range e.Meta.Redacted() {
 				ev = ev.Interface(k, v)
 			}
 			ev.Str("error", e.ErrorMessage()).Str("code", e.Code.String()).Msg("request failed")